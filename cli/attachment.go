@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"viscue/tui/entity"
+	"viscue/tui/tool/vault"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/samber/lo"
+)
+
+// attachmentCommand lists the attachments of an item or writes
+// one of them to a path, or to stdout when no path is given.
+func attachmentCommand(db *sqlx.DB, args []string) error {
+	if len(args) == 0 {
+		return errors.New("expected a subcommand: list or get")
+	}
+
+	switch args[0] {
+	case "list":
+		if err := requireArgs(args[1:], "name"); err != nil {
+			return err
+		}
+	case "get":
+		if len(args) != 4 {
+			if err := requireArgs(args[1:], "name", "attachment"); err != nil {
+				return fmt.Errorf("%w [path]", err)
+			}
+		}
+	default:
+		return fmt.Errorf("unknown attachment subcommand %q", args[0])
+	}
+
+	if err := unlock(db); err != nil {
+		return err
+	}
+	password, err := findPassword(db, args[1])
+	if err != nil {
+		return err
+	}
+	attachments, err := vault.Attachments(db, password.Id)
+	if err != nil {
+		return fmt.Errorf("failed loading attachments: %w", err)
+	}
+
+	if args[0] == "list" {
+		for _, attachment := range attachments {
+			fmt.Printf("%s\t%d\n", attachment.Name, attachment.Size)
+		}
+		return nil
+	}
+
+	found, ok := lo.Find(attachments, func(attachment entity.Attachment) bool {
+		return strings.EqualFold(attachment.Name, args[2])
+	})
+	if !ok {
+		return fmt.Errorf("%q has no attachment named %q", password.Name, args[2])
+	}
+	attachment, err := vault.OpenAttachment(db, found.Id)
+	if err != nil {
+		return fmt.Errorf("failed decrypting attachment: %w", err)
+	}
+
+	if len(args) == 3 {
+		_, err = os.Stdout.Write(attachment.Data)
+		return err
+	}
+	path, err := vault.WriteAttachment(attachment, args[3])
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(os.Stderr, "saved to", path)
+	return nil
+}
@@ -0,0 +1,158 @@
+// Package cli exposes a handful of vault operations as
+// subcommands so that they can be used from scripts, e.g.
+// `viscue otp github`. Every subcommand asks for the account
+// password before touching the vault.
+package cli
+
+import (
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"viscue/tui/entity"
+	"viscue/tui/tool/cache"
+	"viscue/tui/tool/database"
+	"viscue/tui/tool/debugger"
+	"viscue/tui/tool/keychain"
+	"viscue/tui/tool/settings"
+	"viscue/tui/tool/vault"
+
+	"github.com/charmbracelet/x/term"
+	"github.com/jmoiron/sqlx"
+	"github.com/samber/lo"
+)
+
+const usage = `Usage: viscue [command]
+
+Without a command, viscue starts the terminal user interface.
+
+Commands:
+  otp <name>            print the current one-time password of an item
+  lookup <url>          list the items saved for a website
+  git-credential get    act as a git credential helper
+  attachment list <name>
+                        list the attachments of an item
+  attachment get <name> <attachment> [path]
+                        write an attachment to path or stdout
+  help                  show this message
+`
+
+// Run executes the subcommand given by args and returns the exit code.
+func Run(args []string) int {
+	file, err := debugger.New()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "failed initializing debugger:", err)
+		return 1
+	}
+	defer file.Close()
+
+	var command func(*sqlx.DB, []string) error
+	switch args[0] {
+	case "otp":
+		command = otpCommand
+	case "lookup":
+		command = lookupCommand
+	case "git-credential":
+		command = gitCredentialCommand
+	case "attachment":
+		command = attachmentCommand
+	case "help", "-h", "--help":
+		fmt.Print(usage)
+		return 0
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", args[0], usage)
+		return 2
+	}
+
+	db, err := database.New()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "failed connecting to database:", err)
+		return 1
+	}
+	defer db.Close()
+
+	if err = keychain.Use(settings.KeyringBackend.Get(db)); err != nil {
+		fmt.Fprintln(os.Stderr, "failed opening keyring:", err)
+		return 1
+	}
+
+	if err = command(db, args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, "viscue:", err)
+		return 1
+	}
+	return 0
+}
+
+// unlock asks for the account password on the terminal
+// and loads the vault keys into the cache. When stdin is not
+// a terminal, e.g. for git, the controlling terminal is used.
+func unlock(db *sqlx.DB) error {
+	var username string
+	err := db.QueryRowx("SELECT value FROM configurations WHERE key = ?",
+		"username").Scan(&username)
+	if err != nil {
+		return errors.New("no account found, run viscue to create one")
+	}
+
+	input := os.Stdin
+	if !term.IsTerminal(input.Fd()) {
+		tty, err := os.Open("/dev/tty")
+		if err != nil {
+			return errors.New("no terminal to read the password from")
+		}
+		defer tty.Close()
+		input = tty
+	}
+
+	fmt.Fprintf(os.Stderr, "Password for %s: ", username)
+	password, err := term.ReadPassword(input.Fd())
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return fmt.Errorf("failed reading password: %w", err)
+	}
+
+	return vault.Unlock(db, username, string(password))
+}
+
+// findPassword returns the only item with the given name,
+// compared case-insensitively.
+func findPassword(db *sqlx.DB, name string) (entity.Password, error) {
+	var passwords []entity.Password
+	err := db.Select(&passwords,
+		`SELECT id, category_id, type, name, email, username, password, otp,
+			notes, data, created_at, updated_at, last_used_at
+		FROM passwords WHERE LOWER(name) = LOWER(?) AND deleted_at IS NULL`,
+		strings.TrimSpace(name),
+	)
+	if err != nil {
+		return entity.Password{}, fmt.Errorf("failed querying items: %w", err)
+	}
+
+	switch len(passwords) {
+	case 0:
+		return entity.Password{}, fmt.Errorf("no item named %q", name)
+	case 1:
+	default:
+		return entity.Password{}, fmt.Errorf("%d items are named %q",
+			len(passwords), name)
+	}
+
+	password := passwords[0]
+	if err = password.Decrypt(cache.Get[*rsa.PrivateKey](cache.PrivateKey)); err != nil {
+		return entity.Password{}, fmt.Errorf("failed decrypting item: %w", err)
+	}
+	return password, nil
+}
+
+// requireArgs fails unless exactly the named arguments are given.
+func requireArgs(args []string, names ...string) error {
+	if len(args) != len(names) {
+		return fmt.Errorf("expected arguments: %s", strings.Join(
+			lo.Map(names, func(name string, _ int) string {
+				return "<" + name + ">"
+			}), " "))
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"viscue/tui/entity"
+	"viscue/tui/tool/urlmatch"
+	"viscue/tui/tool/vault"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/samber/lo"
+)
+
+// lookupCommand lists the items having a URL matching the given
+// one, the most precisely matched first.
+func lookupCommand(db *sqlx.DB, args []string) error {
+	if err := requireArgs(args, "url"); err != nil {
+		return err
+	}
+	if err := unlock(db); err != nil {
+		return err
+	}
+
+	candidates, err := lookup(db, args[0])
+	if err != nil {
+		return err
+	}
+	if len(candidates) == 0 {
+		return fmt.Errorf("no item saved for %s", args[0])
+	}
+	for _, password := range candidates {
+		fmt.Printf("%s\t%s\t%s\n", password.Name, signInName(password),
+			password.PrimaryURL())
+	}
+	return nil
+}
+
+// gitCredentialCommand implements the `get` action of the git
+// credential helper protocol, e.g. in `.gitconfig`:
+//
+//	[credential]
+//		helper = viscue git-credential
+//
+// Other actions are acknowledged without doing anything since
+// the vault is only edited from the terminal user interface.
+// Only the URLs matched by their exact URL or host answer, so that
+// the scheme and port have to be the same: a password is never sent
+// to a sibling subdomain or over plain http.
+func gitCredentialCommand(db *sqlx.DB, args []string) error {
+	if err := requireArgs(args, "action"); err != nil {
+		return err
+	}
+	attributes, err := readCredentialAttributes(os.Stdin)
+	if err != nil {
+		return err
+	}
+	if args[0] != "get" {
+		return nil
+	}
+
+	host := attributes["host"]
+	if host == "" {
+		return nil
+	}
+	protocol := attributes["protocol"]
+	if protocol == "" {
+		protocol = "https"
+	}
+	target := protocol + "://" + host + "/" + attributes["path"]
+
+	if err = unlock(db); err != nil {
+		return err
+	}
+	candidates, err := lookup(db, target, urlmatch.Exact, urlmatch.Host)
+	if err != nil {
+		return err
+	}
+	if len(candidates) == 0 {
+		return nil // let git fall back to the next helper
+	}
+
+	password := candidates[0]
+	fmt.Printf("username=%s\npassword=%s\n", signInName(password), password.Password)
+	return nil
+}
+
+// lookup returns the login items saved for the target URL, only
+// considering the given rules when there are any.
+func lookup(db *sqlx.DB, target string, rules ...urlmatch.Rule) ([]entity.Password, error) {
+	passwords, err := vault.Passwords(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed loading items: %w", err)
+	}
+	passwords = lo.Filter(passwords, func(password entity.Password, _ int) bool {
+		return !password.DeletedAt.Valid
+	})
+	return entity.LookupURL(passwords, target, rules...), nil
+}
+
+// signInName returns the name used to sign in, preferring the username.
+func signInName(password entity.Password) string {
+	if password.Username != "" {
+		return password.Username
+	}
+	return password.Email
+}
+
+// readCredentialAttributes parses the `key=value` lines git sends,
+// up to the first blank line.
+func readCredentialAttributes(r io.Reader) (map[string]string, error) {
+	attributes := make(map[string]string)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			break
+		}
+		if key, value, found := strings.Cut(line, "="); found {
+			attributes[key] = value
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed reading git attributes: %w", err)
+	}
+	return attributes, nil
+}
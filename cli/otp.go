@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"crypto/rsa"
+	"fmt"
+	"os"
+	"time"
+
+	"viscue/tui/tool/cache"
+	"viscue/tui/tool/otp"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// otpCommand prints the current one-time password of an item.
+// HOTP counters are moved forward since their codes are single use.
+func otpCommand(db *sqlx.DB, args []string) error {
+	if err := requireArgs(args, "name"); err != nil {
+		return err
+	}
+	if err := unlock(db); err != nil {
+		return err
+	}
+
+	password, err := findPassword(db, args[0])
+	if err != nil {
+		return err
+	}
+	if password.Otp == "" {
+		return fmt.Errorf("%q has no one-time password", password.Name)
+	}
+
+	key, err := otp.Parse(password.Otp)
+	if err != nil {
+		return fmt.Errorf("invalid one-time password secret: %w", err)
+	}
+
+	now := time.Now()
+	if key.Type == otp.TOTP {
+		fmt.Println(key.Code(now))
+		fmt.Fprintf(os.Stderr, "valid for %s\n", key.Remaining(now))
+		return nil
+	}
+
+	// The counter is saved before the code is shown, so that
+	// a code is never printed twice
+	code := key.Code(now)
+	key.Counter++
+	password.Otp = key.String()
+	if err = password.Encrypt(cache.Get[*rsa.PublicKey](cache.PublicKey)); err != nil {
+		return fmt.Errorf("failed encrypting item: %w", err)
+	}
+	_, err = db.Exec("UPDATE passwords SET otp = ? WHERE id = ?",
+		password.Otp, password.Id)
+	if err != nil {
+		return fmt.Errorf("failed advancing the HOTP counter: %w", err)
+	}
+	fmt.Println(code)
+	return nil
+}
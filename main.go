@@ -3,9 +3,13 @@ package main
 import (
 	"os"
 
+	"viscue/cli"
 	"viscue/tui"
 )
 
 func main() {
+	if len(os.Args) > 1 {
+		os.Exit(cli.Run(os.Args[1:]))
+	}
 	os.Exit(tui.Run())
 }
@@ -1,6 +1,7 @@
 package tui
 
 import (
+	"fmt"
 	"net/http"
 	_ "net/http/pprof"
 	"os"
@@ -9,7 +10,15 @@ import (
 	"viscue/tui/tool/cache"
 	"viscue/tui/tool/database"
 	"viscue/tui/tool/debugger"
+	"viscue/tui/tool/keychain"
+	"viscue/tui/tool/keymap"
+	"viscue/tui/tool/settings"
+	"viscue/tui/tool/theme"
+	"viscue/tui/tool/zone"
 	"viscue/tui/views/library"
+	"viscue/tui/views/library/submodel/prompt"
+	"viscue/tui/views/library/submodel/shelf"
+	"viscue/tui/views/library/submodel/sidebar"
 	"viscue/tui/views/login"
 	"viscue/tui/views/warning"
 
@@ -49,7 +58,8 @@ func (m *app) Init() tea.Cmd {
 func (m *app) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
-		if msg.Height < 24 || msg.Width < 90 {
+		// Smaller terminals get the compact layout down to the minimum
+		if msg.Height < style.MinHeight || msg.Width < style.MinWidth {
 			m.warningView = warning.NewScreenSize(msg.Width, msg.Height)
 		} else {
 			m.warningView = nil
@@ -65,6 +75,13 @@ func (m *app) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case login.Successful:
 		m.appView = library.New(m.db)
 		return m, m.appView.Init()
+	case library.Locked:
+		m.appView = login.New(m.db)
+		return m, m.appView.Init()
+	case theme.ChangedMsg:
+		if msg.Err == nil {
+			style.Apply(msg.Theme)
+		}
 	}
 
 PassToCurrentView:
@@ -76,13 +93,6 @@ PassToCurrentView:
 func (m *app) View() string {
 	width := cache.Get[int](cache.TerminalWidth)
 	canvas := lipgloss.NewStyle().Width(width).Render
-	header := style.LogoContainer.Width(width).Render(
-		lipgloss.JoinVertical(
-			lipgloss.Center,
-			style.Logo,
-			style.SubLogo,
-		),
-	)
 
 	var view string
 	if m.warningView != nil {
@@ -90,14 +100,19 @@ func (m *app) View() string {
 	} else {
 		view = m.appView.View()
 	}
+	if m.warningView == nil && !style.IsCompact() {
+		header := style.LogoContainer.Width(width).Render(
+			lipgloss.JoinVertical(
+				lipgloss.Center,
+				style.Logo.String(),
+				style.SubLogo.String(),
+			),
+		)
+		view = lipgloss.JoinVertical(lipgloss.Center, header, view)
+	}
 
-	return canvas(
-		lipgloss.JoinVertical(
-			lipgloss.Center,
-			header,
-			view,
-		),
-	)
+	// Zones are found on the whole view, where mouse events point at
+	return zone.Scan(canvas(view))
 }
 
 func Run() int {
@@ -115,13 +130,41 @@ func Run() int {
 	}
 	defer file.Close()
 
+	if err = applyKeymap(); err != nil {
+		log.Error("failed loading keymap", "err", err)
+		fmt.Fprintln(os.Stderr, "invalid keymap:", err)
+		return 1
+	}
+
+	if err = keychain.Use(settings.KeyringBackend.Get(db)); err != nil {
+		log.Error("failed opening keyring", "err", err)
+		return 1
+	}
+
+	current, err := theme.Load(settings.Theme.Get(db))
+	if err != nil {
+		log.Error("failed loading theme", "err", err)
+		fmt.Fprintln(os.Stderr, "invalid theme:", err)
+		return 1
+	}
+	style.Apply(current)
+
 	if _, ok := os.LookupEnv("pprof"); ok {
 		go func() {
 			http.ListenAndServe("localhost:6060", nil)
 		}()
 	}
 
-	_, err = tea.NewProgram(NewApp(db)).Run()
+	program := tea.NewProgram(NewApp(db), tea.WithMouseCellMotion())
+	stop, err := theme.Watch(program.Send, func() string {
+		return settings.Theme.Get(db)
+	})
+	if err != nil {
+		log.Error("failed watching theme file", "err", err)
+	}
+	defer stop()
+
+	_, err = program.Run()
 	if err != nil {
 		log.Error("unable to start application", "err", err)
 		return 1
@@ -129,3 +172,27 @@ func Run() int {
 
 	return 0
 }
+
+// applyKeymap rebinds the keys the user has remapped in the keymap file.
+func applyKeymap() error {
+	config, err := keymap.Load()
+	if err != nil {
+		return err
+	}
+	err = config.Check("login", "shelf", "sidebar", "tags", "search",
+		"prompt", "dropdown")
+	if err != nil {
+		return err
+	}
+	for _, apply := range []func(keymap.Config) error{
+		login.ApplyKeymap,
+		shelf.ApplyKeymap,
+		sidebar.ApplyKeymap,
+		prompt.ApplyKeymap,
+	} {
+		if err = apply(config); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -6,7 +6,10 @@
 package list
 
 import (
+	"strconv"
+
 	"viscue/tui/style"
+	"viscue/tui/tool/zone"
 
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
@@ -18,15 +21,15 @@ var (
 	DefaultItemStyle = lipgloss.NewStyle().
 				PaddingLeft(1).
 				Bold(true)
-	DefaultSelectedItemStyle = DefaultItemStyle.Background(style.ColorPurple).
-					Foreground(style.ColorNormal)
+	DefaultSelectedItemStyle = DefaultItemStyle.Background(style.ColorAccent).
+					Foreground(style.ColorContrast)
 	DefaultBlurredItemStyle = lipgloss.NewStyle().
 				PaddingLeft(1).
-				Foreground(style.ColorGray).
+				Foreground(style.ColorMuted).
 				Bold(true)
 	DefaultBlurredSelectedItemStyle = DefaultBlurredItemStyle.
-					Background(style.ColorGray).
-					Foreground(style.ColorNormal)
+					Background(style.ColorMuted).
+					Foreground(style.ColorContrast)
 )
 
 type Styles struct {
@@ -48,16 +51,21 @@ func DefaultStyles() Styles {
 type Model struct {
 	Styles Styles
 
-	vp      viewport.Model
-	items   []Item
-	currIdx int
-	focused bool
+	vp        viewport.Model
+	items     []Item
+	currIdx   int
+	focused   bool
+	emptyText string
+	zoneId    string // marks the items for mouse events
+	clicks    zone.Clicks
 }
 
 func New(opts ...Option) Model {
 	m := Model{
-		Styles: DefaultStyles(),
-		vp:     viewport.New(0, 0),
+		Styles:    DefaultStyles(),
+		vp:        viewport.New(0, 0),
+		emptyText: "No categories",
+		zoneId:    zone.NewPrefix(),
 	}
 	for _, opt := range opts {
 		opt(&m)
@@ -90,6 +98,13 @@ func WithItems(items []Item) Option {
 	}
 }
 
+// WithEmptyText sets the text shown when there is no item
+func WithEmptyText(text string) Option {
+	return func(m *Model) {
+		m.emptyText = text
+	}
+}
+
 func WithFocused(focused bool) Option {
 	return func(m *Model) {
 		if focused {
@@ -105,6 +120,21 @@ func (m Model) Init() tea.Cmd {
 }
 
 func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	// The mouse points at the list whether it is focused or not
+	if msg, ok := msg.(tea.MouseMsg); ok {
+		if _, ok := zone.Hit(m.zoneId, msg); !ok {
+			return m, nil
+		}
+		if up, ok := zone.IsWheel(msg); ok && up {
+			m.ScrollUp()
+		} else if ok {
+			m.ScrollDown()
+		} else if zone.IsClick(msg) {
+			m.Click(msg)
+		}
+		return m, nil
+	}
+
 	// Ignore msg when blurred
 	if !m.focused {
 		return m, nil
@@ -112,6 +142,8 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		// Fixed moves, the views with remappable keys calling
+		// Up and Down themselves instead
 		switch msg.String() {
 		case "j", "down", "tab":
 			m.Down()
@@ -129,7 +161,7 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 
 func (m Model) View() string {
 	m.vp.SetContent(m.renderItems())
-	return m.vp.View()
+	return zone.Mark(m.zoneId, m.vp.View())
 }
 
 type Item interface {
@@ -150,7 +182,7 @@ func (m Model) Items() []Item {
 
 func (m Model) renderItems() string {
 	if len(m.items) == 0 {
-		return "No categories\n"
+		return m.emptyText + "\n"
 	}
 
 	var content string
@@ -158,7 +190,8 @@ func (m Model) renderItems() string {
 		str := item.String()
 		width := lipgloss.Width(str)
 		if width > m.vp.Width-2 {
-			str = str[:m.vp.Width-2] + "…"
+			// Cut on runes so that multibyte labels stay valid
+			str = string([]rune(str)[:max(0, m.vp.Width-2)]) + "…"
 		}
 		fn := m.Styles.BlurredItem.Render
 		if m.focused {
@@ -166,9 +199,9 @@ func (m Model) renderItems() string {
 		}
 		if idx == m.currIdx {
 			if m.focused {
-				fn = m.Styles.SelectedItem.Render
+				fn = style.Emphasize(m.Styles.SelectedItem).Render
 			} else {
-				fn = m.Styles.BlurredSelectedItem.Render
+				fn = style.Emphasize(m.Styles.BlurredSelectedItem).Render
 			}
 		}
 		content += fn(str) + "\n"
@@ -263,3 +296,45 @@ func (m *Model) Down() {
 		m.vp.LineDown(1)
 	}
 }
+
+// ItemAt returns the index of the item under the mouse, if any.
+func (m Model) ItemAt(msg tea.MouseMsg) (int, bool) {
+	z, ok := zone.Hit(m.zoneId, msg)
+	if !ok {
+		return 0, false
+	}
+	_, y := z.Pos(msg)
+	index := y + m.vp.YOffset
+	return index, index < len(m.items)
+}
+
+// Click moves the cursor to the item clicked, reporting whether
+// an item was hit and whether the click is the second one on it in a row.
+func (m *Model) Click(msg tea.MouseMsg) (double, ok bool) {
+	index, ok := m.ItemAt(msg)
+	if !ok || !zone.IsClick(msg) {
+		return false, false
+	}
+	m.SetIndex(index)
+	return m.clicks.Register(strconv.Itoa(index)), true
+}
+
+// ScrollUp scrolls the items by a line, keeping the cursor
+// on a visible item.
+func (m *Model) ScrollUp() {
+	if m.vp.AtTop() {
+		return
+	}
+	m.vp.LineUp(1)
+	m.currIdx = min(m.currIdx, m.vp.YOffset+m.vp.Height-1)
+}
+
+// ScrollDown scrolls the items by a line, keeping the cursor
+// on a visible item.
+func (m *Model) ScrollDown() {
+	if m.vp.AtBottom() {
+		return
+	}
+	m.vp.LineDown(1)
+	m.currIdx = max(m.currIdx, m.vp.YOffset)
+}
@@ -33,26 +33,44 @@ var (
 	}
 )
 
+// DefaultHistoryLimit is the number of notifications kept in the history.
+const DefaultHistoryLimit = 100
+
 type Model struct {
 	Style    lipgloss.Style
 	duration time.Duration
 	position Position
-	msg      string
-	visible  bool
+	limit    int
+
+	current Entry
+	visible bool
+	shown   int     // increases on every tick started, telling them apart
+	queue   []Entry // waiting for the current one to expire
+	history []Entry // oldest first
 }
 
-type TickMsg struct{}
+// TickMsg expires the notification it was started for,
+// unless another one has been shown since.
+type TickMsg struct {
+	shown int
+}
 
+// ShowMsg asks for a notification to be shown. Messages sharing
+// a non-empty Key replace each other instead of queueing up,
+// e.g. the steps of a countdown.
 type ShowMsg struct {
 	Message string
+	Level   Level
+	Key     string
 }
 
 func New(opts ...Option) Model {
 	m := Model{
 		Style: lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).
-			BorderForeground(style.ColorPurple).Padding(1, 2),
+			BorderForeground(style.ColorAccent).Padding(1, 2),
 		position: BottomRight,
 		duration: 1 * time.Second,
+		limit:    DefaultHistoryLimit,
 	}
 	for _, opt := range opts {
 		opt(&m)
@@ -83,6 +101,13 @@ func WithStyle(style lipgloss.Style) Option {
 	}
 }
 
+// WithHistoryLimit sets how many notifications the history keeps
+func WithHistoryLimit(limit int) Option {
+	return func(m *Model) {
+		m.limit = limit
+	}
+}
+
 func (m Model) Init() tea.Cmd {
 	return nil
 }
@@ -90,10 +115,13 @@ func (m Model) Init() tea.Cmd {
 func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case TickMsg:
-		m.Hide()
-		return m, nil
+		if msg.shown != m.shown {
+			return m, nil
+		}
+		cmd := m.next()
+		return m, cmd
 	case ShowMsg:
-		cmd := m.Show(msg.Message)
+		cmd := m.Show(msg)
 		return m, cmd
 	}
 	return m, nil
@@ -103,20 +131,49 @@ func (m Model) View() string {
 	if !m.visible {
 		return ""
 	}
-	return m.Style.Render(m.msg)
-}
-
-func (m *Model) Show(msg string) tea.Cmd {
-	m.msg = msg
+	return m.Style.BorderForeground(m.current.Level.Color()).
+		Render(m.current.Level.Icon() + " " + m.current.Message)
+}
+
+// Show displays the notification, or queues it while
+// another one is displayed.
+func (m *Model) Show(msg ShowMsg) tea.Cmd {
+	entry := Entry{
+		Message: msg.Message,
+		Level:   msg.Level,
+		Key:     msg.Key,
+		Time:    time.Now(),
+	}
+	m.record(entry)
+
+	if msg.Key != "" {
+		if m.visible && m.current.Key == msg.Key {
+			m.current = entry
+			if len(m.queue) > 0 {
+				// Let the queued ones have their turn
+				return nil
+			}
+			return m.tick()
+		}
+		for i := range m.queue {
+			if m.queue[i].Key == msg.Key {
+				m.queue[i] = entry
+				return nil
+			}
+		}
+	}
+	if m.visible {
+		m.queue = append(m.queue, entry)
+		return nil
+	}
+	m.current = entry
 	m.visible = true
-	return tea.Tick(m.duration,
-		func(t time.Time) tea.Msg {
-			return TickMsg{}
-		})
+	return m.tick()
 }
 
 func (m *Model) Hide() {
 	m.visible = false
+	m.queue = nil
 }
 
 func (m *Model) SetDuration(duration time.Duration) {
@@ -138,3 +195,13 @@ func (m Model) Position() Position {
 func (m Model) Visible() bool {
 	return m.visible
 }
+
+// History returns the notifications shown so far, oldest first.
+func (m Model) History() []Entry {
+	return append([]Entry(nil), m.history...)
+}
+
+// ClearHistory forgets every notification shown so far.
+func (m *Model) ClearHistory() {
+	m.history = nil
+}
@@ -0,0 +1,119 @@
+package notification
+
+import (
+	"time"
+
+	"viscue/tui/style"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Level tells how important a notification is.
+type Level int
+
+const (
+	LevelInfo Level = iota
+	LevelSuccess
+	LevelWarning
+	LevelError
+)
+
+func (level Level) String() string {
+	switch level {
+	case LevelSuccess:
+		return "success"
+	case LevelWarning:
+		return "warning"
+	case LevelError:
+		return "error"
+	default:
+		return "info"
+	}
+}
+
+// Icon returns the symbol prefixing notifications of the level.
+func (level Level) Icon() string {
+	switch level {
+	case LevelSuccess:
+		return "✔"
+	case LevelWarning:
+		return "⚠"
+	case LevelError:
+		return "✖"
+	default:
+		return "ℹ"
+	}
+}
+
+// Color returns the color notifications of the level are drawn with.
+func (level Level) Color() lipgloss.TerminalColor {
+	switch level {
+	case LevelSuccess:
+		return style.ColorSuccess
+	case LevelWarning:
+		return style.ColorWarning
+	case LevelError:
+		return style.ColorError
+	default:
+		return style.ColorAccent
+	}
+}
+
+// Entry is a notification as kept in the history.
+type Entry struct {
+	Message string
+	Level   Level
+	Key     string
+	Time    time.Time
+}
+
+// tick starts the expiry of the current notification. Errors
+// and warnings stay twice as long to leave time to read them.
+func (m *Model) tick() tea.Cmd {
+	m.shown++
+	shown := m.shown
+	duration := m.duration
+	if m.current.Level >= LevelWarning {
+		duration *= 2
+	}
+	return tea.Tick(duration,
+		func(t time.Time) tea.Msg {
+			return TickMsg{shown: shown}
+		})
+}
+
+// next shows the first queued notification, if any,
+// once the current one has expired.
+func (m *Model) next() tea.Cmd {
+	if len(m.queue) == 0 {
+		m.visible = false
+		return nil
+	}
+	m.current = m.queue[0]
+	m.queue = m.queue[1:]
+	return m.tick()
+}
+
+// record appends the entry to the history, where successive
+// entries sharing a key are folded into the latest one.
+func (m *Model) record(entry Entry) {
+	if n := len(m.history); n > 0 && entry.Key != "" &&
+		m.history[n-1].Key == entry.Key {
+		m.history[n-1] = entry
+		return
+	}
+	m.history = append(m.history, entry)
+	if m.limit > 0 && len(m.history) > m.limit {
+		m.history = m.history[len(m.history)-m.limit:]
+	}
+}
+
+// Overlay draws the notification over the background,
+// at the position the model has been given.
+func (m Model) Overlay(background string) string {
+	if !m.visible {
+		return background
+	}
+	return style.Overlay(background, m.View(), m.position.X, m.position.Y)
+}
@@ -1,11 +1,17 @@
 package table
 
 import (
+	"slices"
+	"strconv"
+	"strings"
+
 	"viscue/tui/style"
+	"viscue/tui/tool/zone"
 
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 )
 
 var (
@@ -16,31 +22,52 @@ var (
 				PaddingLeft(1).
 				Bold(true)
 	_defaultCellStyle         = lipgloss.NewStyle().PaddingLeft(1).Foreground(style.ColorNormal)
-	_defaultBlurredCellStyle  = _defaultCellStyle.Foreground(style.ColorGray)
+	_defaultBlurredCellStyle  = _defaultCellStyle.Foreground(style.ColorMuted)
 	_defaultSelectedCellStyle = lipgloss.NewStyle().
-					Foreground(style.ColorNormal).
-					Background(style.ColorPurple)
-	_defaultBlurredSelectedCellStyle = _defaultSelectedCellStyle.Background(style.ColorGray)
+					Foreground(style.ColorContrast).
+					Background(style.ColorAccent)
+	_defaultBlurredSelectedCellStyle = _defaultSelectedCellStyle.Background(style.ColorMuted)
+	_defaultMarkedCellStyle          = lipgloss.NewStyle().Foreground(style.ColorAccentPale)
+	_defaultMarkSymbol               = "▌"
+	_defaultHighlightStyle           = lipgloss.NewStyle().Bold(true).Underline(true)
 )
 
 type Row []string
 
+// Highlights holds, by row index then column index, the indexes
+// of the runes to emphasize in a cell, e.g. the ones a search matched.
+type Highlights map[int]map[int][]int
+
 type Column struct {
 	Title string
 	Width int
 }
 
 type Model struct {
-	vp      viewport.Model
-	rows    []Row
-	columns []Column
-	currIdx int
-	focused bool
+	vp         viewport.Model
+	rows       []Row
+	columns    []Column
+	currIdx    int
+	focused    bool
+	highlights Highlights
+	zoneId     string // marks the rows for mouse events
+	clicks     zone.Clicks
+
+	// Rows marked for bulk actions, told apart by their first cell.
+	// Ranges extend from the anchor, the last row marked on its own,
+	// to the extent, replacing the previous range. Both are -1 until
+	// a row is marked.
+	marked         map[string]bool
+	anchor, extent int
 }
 
 func New(opts ...Option) Model {
 	model := Model{
-		vp: viewport.New(0, 0),
+		vp:     viewport.New(0, 0),
+		zoneId: zone.NewPrefix(),
+		marked: make(map[string]bool),
+		anchor: -1,
+		extent: -1,
 	}
 	for _, opt := range opts {
 		opt(&model)
@@ -93,6 +120,8 @@ func (m Model) Init() tea.Cmd {
 func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		// Fixed moves, the views with remappable keys calling
+		// Up and Down themselves instead
 		switch msg.String() {
 		case "j", "down", "tab":
 			m.Down()
@@ -101,6 +130,18 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 			m.Up()
 			return m, nil
 		}
+	case tea.MouseMsg:
+		if _, ok := zone.Hit(m.zoneId, msg); !ok {
+			return m, nil
+		}
+		if up, ok := zone.IsWheel(msg); ok && up {
+			m.ScrollUp()
+		} else if ok {
+			m.ScrollDown()
+		} else if zone.IsClick(msg) {
+			m.Click(msg)
+		}
+		return m, nil
 	}
 	var cmd tea.Cmd
 	m.vp, cmd = m.vp.Update(msg)
@@ -110,7 +151,8 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 // View should display header and viewport that joined vertically as a table.
 func (m Model) View() string {
 	m.vp.SetContent(m.renderRows())
-	return lipgloss.JoinVertical(lipgloss.Top, m.renderHeader(), m.vp.View())
+	return lipgloss.JoinVertical(lipgloss.Top, m.renderHeader(),
+		zone.Mark(m.zoneId, m.vp.View()))
 }
 
 // renderHeader renders the header of the table from columns
@@ -141,13 +183,17 @@ func (m Model) cellStyle(index, width int) lipgloss.Style {
 		selectedStyle = _defaultSelectedCellStyle
 	}
 
+	if m.isMarked(index) {
+		cellStyle = cellStyle.Inherit(_defaultMarkedCellStyle)
+	}
+
 	st := cellStyle.Width(width).MaxWidth(width)
 	if m.currIdx == index {
 		// TODO: Perhaps make a util function to enhance `Inherit`
 		if _defaultSelectedCellStyle.GetForeground() == nil {
 			cellStyle = cellStyle.UnsetForeground()
 		}
-		st = st.UnsetForeground().Inherit(selectedStyle)
+		st = style.Emphasize(st.UnsetForeground().Inherit(selectedStyle))
 	}
 
 	return st
@@ -164,30 +210,98 @@ func (m Model) renderRows() string {
 			columnWidth := m.columns[columnIndex].Width
 			if columnWidth <= 0 {
 				continue
-			} else if len(cell)-1 >= columnWidth {
-				cell = cell[:columnWidth-3] + "…"
+			} else if runes := []rune(cell); len(runes) > columnWidth {
+				cell = string(runes[:columnWidth-3]) + "…"
 			}
 			cellStyle := m.cellStyle(rowIndex, columnWidth)
-			cells = append(cells, cellStyle.Render(cell))
+			cells = append(cells, highlight(cell,
+				m.highlights[rowIndex][columnIndex], cellStyle))
 		}
 		str := lipgloss.JoinHorizontal(lipgloss.Left, cells...)
+		if len(m.marked) > 0 && len(cells) > 0 {
+			// The padding of the first cell leaves room for the mark
+			mark := " "
+			if m.isMarked(rowIndex) {
+				mark = _defaultMarkedCellStyle.Render(_defaultMarkSymbol)
+			}
+			str = mark + ansi.TruncateLeft(str, 1, "")
+		}
 		rows = append(rows, str)
 	}
 	return lipgloss.JoinVertical(lipgloss.Left, rows...)
 }
 
-// SetRows replace the row field and reset the cursor to 0
+// highlight renders the cell, emphasizing the runes at the given indexes.
+func highlight(cell string, indexes []int, cellStyle lipgloss.Style) string {
+	if len(indexes) == 0 {
+		return cellStyle.Render(cell)
+	}
+	text := cellStyle.UnsetWidth().UnsetMaxWidth().UnsetPaddingLeft()
+	emphasized := text.Inherit(_defaultHighlightStyle)
+
+	var b strings.Builder
+	runes := []rune(cell)
+	for start := 0; start < len(runes); {
+		// Render each run of emphasized or plain runes at once
+		on := slices.Contains(indexes, start)
+		end := start + 1
+		for end < len(runes) && slices.Contains(indexes, end) == on {
+			end++
+		}
+		if on {
+			b.WriteString(emphasized.Render(string(runes[start:end])))
+		} else {
+			b.WriteString(text.Render(string(runes[start:end])))
+		}
+		start = end
+	}
+	return cellStyle.Render(b.String())
+}
+
+// SetRows replace the row field and reset the cursor to 0,
+// dropping the highlights and the marks of the rows that are gone
 func (m *Model) SetRows(rows []Row) {
+	m.highlights = nil
 	m.rows = rows
+	kept := make(map[string]bool, len(m.marked))
+	for _, row := range rows {
+		if len(row) > 0 && m.marked[row[0]] {
+			kept[row[0]] = true
+		}
+	}
+	m.marked = kept
+	m.anchor, m.extent = -1, -1
 	m.vp.SetContent(m.renderRows())
 	m.currIdx = 0
 	m.vp.SetYOffset(0)
 }
 
+// SetHighlights emphasizes runes within the cells of the current rows
+func (m *Model) SetHighlights(highlights Highlights) {
+	m.highlights = highlights
+	m.vp.SetContent(m.renderRows())
+}
+
 func (m Model) Rows() []Row {
 	return m.rows
 }
 
+// Index returns the index of the row where the cursor is at
+func (m Model) Index() int {
+	return m.currIdx
+}
+
+// SetIndex moves the cursor to the given row index, scrolling
+// the viewport along the way
+func (m *Model) SetIndex(idx int) {
+	for idx < m.currIdx && m.currIdx > 0 {
+		m.Up()
+	}
+	for idx > m.currIdx && m.currIdx < len(m.rows)-1 {
+		m.Down()
+	}
+}
+
 // SelectedRow returns the row where the cursor is at
 func (m Model) SelectedRow() Row {
 	if m.currIdx > len(m.rows)-1 {
@@ -271,3 +385,126 @@ func (m *Model) Down() {
 		m.vp.LineDown(1)
 	}
 }
+
+// RowAt returns the index of the row under the mouse, if any.
+func (m Model) RowAt(msg tea.MouseMsg) (int, bool) {
+	z, ok := zone.Hit(m.zoneId, msg)
+	if !ok {
+		return 0, false
+	}
+	_, y := z.Pos(msg)
+	index := y + m.vp.YOffset
+	return index, index < len(m.rows)
+}
+
+// Click moves the cursor to the row clicked, reporting whether
+// a row was hit and whether the click is the second one on it in a row.
+// Holding ctrl marks the row, shift marks the rows up to it.
+func (m *Model) Click(msg tea.MouseMsg) (double, ok bool) {
+	index, ok := m.RowAt(msg)
+	if !ok || !zone.IsClick(msg) {
+		return false, false
+	}
+	switch {
+	case msg.Shift:
+		m.MarkTo(index)
+		return false, true
+	case msg.Ctrl:
+		m.SetIndex(index)
+		m.ToggleMark()
+		return false, true
+	}
+	m.SetIndex(index)
+	return m.clicks.Register(strconv.Itoa(index)), true
+}
+
+// ScrollUp scrolls the rows by a line, keeping the cursor
+// on a visible row
+func (m *Model) ScrollUp() {
+	if m.vp.AtTop() {
+		return
+	}
+	m.vp.LineUp(1)
+	m.currIdx = min(m.currIdx, m.vp.YOffset+m.vp.Height-1)
+}
+
+// ScrollDown scrolls the rows by a line, keeping the cursor
+// on a visible row
+func (m *Model) ScrollDown() {
+	if m.vp.AtBottom() {
+		return
+	}
+	m.vp.LineDown(1)
+	m.currIdx = max(m.currIdx, m.vp.YOffset)
+}
+
+func (m Model) isMarked(index int) bool {
+	return len(m.rows[index]) > 0 && m.marked[m.rows[index][0]]
+}
+
+// ToggleMark marks the row under the cursor, or unmarks it, making
+// it the anchor of the next range.
+func (m *Model) ToggleMark() {
+	if m.currIdx >= len(m.rows) || len(m.rows[m.currIdx]) == 0 {
+		return
+	}
+	key := m.rows[m.currIdx][0]
+	if m.marked[key] {
+		delete(m.marked, key)
+	} else {
+		m.marked[key] = true
+	}
+	m.anchor, m.extent = m.currIdx, m.currIdx
+}
+
+// MarkTo moves the cursor to the given row index, marking every
+// row from the anchor to it in place of the previous range.
+func (m *Model) MarkTo(index int) {
+	if len(m.rows) == 0 {
+		return
+	}
+	index = max(0, min(index, len(m.rows)-1))
+	if m.anchor < 0 {
+		// Starting a range from the cursor
+		m.anchor, m.extent = m.currIdx, m.currIdx
+	} else {
+		m.markRange(m.anchor, m.extent, false)
+	}
+	m.markRange(m.anchor, index, true)
+	m.extent = index
+	m.SetIndex(index)
+}
+
+// markRange marks or unmarks the rows between both indexes, included.
+func (m *Model) markRange(from, to int, marked bool) {
+	if from > to {
+		from, to = to, from
+	}
+	for i := from; i <= to && i < len(m.rows); i++ {
+		if len(m.rows[i]) == 0 {
+			continue
+		}
+		if marked {
+			m.marked[m.rows[i][0]] = true
+		} else {
+			delete(m.marked, m.rows[i][0])
+		}
+	}
+}
+
+// ClearMarks unmarks every row.
+func (m *Model) ClearMarks() {
+	clear(m.marked)
+	m.anchor, m.extent = -1, -1
+}
+
+// MarkedRows returns the marked rows in their order.
+func (m Model) MarkedRows() []Row {
+	var rows []Row
+	for i, row := range m.rows {
+		if m.isMarked(i) {
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
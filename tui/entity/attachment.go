@@ -0,0 +1,45 @@
+package entity
+
+import (
+	"crypto/rsa"
+	"strconv"
+	"time"
+
+	"viscue/tui/tool/crypto"
+)
+
+// Attachment is a file kept next to a password. Its content is
+// sealed with a random AES-GCM key wrapped by the vault key. The
+// content is only loaded when the file is saved back to disk.
+type Attachment struct {
+	Id         int64     `db:"id"`
+	PasswordId int64     `db:"password_id"`
+	Name       string    `db:"name"`
+	Size       int64     `db:"size"`
+	Data       []byte    `db:"data"`
+	CreatedAt  time.Time `db:"created_at"`
+}
+
+// Encrypt seals both the name and the content. They are labeled
+// with the id of their password, hence it has to be set first.
+func (attachment *Attachment) Encrypt(pub *rsa.PublicKey) (err error) {
+	attachment.Name, err = sealValue(pub, attachment.Name, attachment.label())
+	if err != nil {
+		return err
+	}
+	attachment.Data, err = crypto.Seal(pub, attachment.Data, attachment.label())
+	return err
+}
+
+func (attachment *Attachment) Decrypt(priv *rsa.PrivateKey) (err error) {
+	attachment.Name, err = openValue(priv, attachment.Name, attachment.label())
+	if err != nil || len(attachment.Data) == 0 {
+		return err
+	}
+	attachment.Data, err = crypto.Open(priv, attachment.Data, attachment.label())
+	return err
+}
+
+func (attachment Attachment) label() []byte {
+	return []byte("attachment:" + strconv.FormatInt(attachment.PasswordId, 10))
+}
@@ -1,15 +1,18 @@
 package entity
 
 import (
-	"fmt"
+	"database/sql"
+	"errors"
+	"slices"
 	"strings"
 
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 )
 
 type Category struct {
-	Id   int64  `db:"id"`
-	Name string `db:"name"`
+	Id       int64         `db:"id"`
+	Name     string        `db:"name"`
+	ParentId sql.NullInt64 `db:"parent_id"`
 }
 
 // String implements list.Item
@@ -23,8 +26,59 @@ func (category Category) Validate() error {
 	)
 	if err != nil {
 		msg := strings.Split(err.Error(), "; ")
-		return fmt.Errorf(strings.Join(msg, " and "))
+		return errors.New(strings.Join(msg, " and "))
 	}
 
 	return nil
 }
+
+// Children returns the categories directly nested under the given one.
+func Children(categories []Category, id int64) []Category {
+	var children []Category
+	for _, category := range categories {
+		if category.ParentId.Valid && category.ParentId.Int64 == id {
+			children = append(children, category)
+		}
+	}
+	return children
+}
+
+// Descendants returns the IDs of the categories nested, at any
+// depth, under the given one.
+func Descendants(categories []Category, id int64) []int64 {
+	var ids []int64
+	queue := []int64{id}
+	for len(queue) > 0 {
+		for _, child := range Children(categories, queue[0]) {
+			if child.Id == id || slices.Contains(ids, child.Id) {
+				continue // Guards against a corrupted cycle
+			}
+			ids = append(ids, child.Id)
+			queue = append(queue, child.Id)
+		}
+		queue = queue[1:]
+	}
+	return ids
+}
+
+// Path returns the names of the category and its parents,
+// from the top level one down, joined by slashes.
+func Path(categories []Category, id int64) string {
+	var names []string
+	for seen := map[int64]bool{}; !seen[id]; {
+		seen[id] = true
+		index := slices.IndexFunc(categories, func(category Category) bool {
+			return category.Id == id
+		})
+		if index < 0 {
+			break
+		}
+		names = append(names, categories[index].Name)
+		if !categories[index].ParentId.Valid {
+			break
+		}
+		id = categories[index].ParentId.Int64
+	}
+	slices.Reverse(names)
+	return strings.Join(names, "/")
+}
@@ -0,0 +1,60 @@
+package entity
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/hex"
+
+	"viscue/tui/tool/crypto"
+)
+
+// encryptValue encrypts the value with RSA-OAEP bound to the
+// given label and returns it hex encoded.
+func encryptValue(pub *rsa.PublicKey, value string, label []byte) (string, error) {
+	b, err := rsa.EncryptOAEP(sha256.New(), rand.Reader, pub,
+		[]byte(value), label)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// decryptValue reverses encryptValue.
+func decryptValue(priv *rsa.PrivateKey, value string, label []byte) (string, error) {
+	decoded, err := hex.DecodeString(value)
+	if err != nil {
+		return "", err
+	}
+
+	plaintext, err := rsa.DecryptOAEP(sha256.New(), rand.Reader, priv,
+		decoded, label)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// sealValue encrypts values that may exceed what RSA-OAEP can
+// hold, e.g. notes, and returns it hex encoded.
+func sealValue(pub *rsa.PublicKey, value string, label []byte) (string, error) {
+	b, err := crypto.Seal(pub, []byte(value), label)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// openValue reverses sealValue.
+func openValue(priv *rsa.PrivateKey, value string, label []byte) (string, error) {
+	decoded, err := hex.DecodeString(value)
+	if err != nil {
+		return "", err
+	}
+
+	plaintext, err := crypto.Open(priv, decoded, label)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
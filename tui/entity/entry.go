@@ -0,0 +1,56 @@
+package entity
+
+import (
+	"strings"
+
+	"viscue/tui/tool/urlmatch"
+)
+
+// Entry is a non-empty value of a password as listed to the user.
+type Entry struct {
+	Label     string
+	Value     string
+	Concealed bool
+	Key       string        // schema key of the type specific values
+	Rule      urlmatch.Rule // match rule of the URLs
+}
+
+// Entries lists the non-empty values of the password in the order
+// they are shown: the type specific ones, URLs, custom fields and
+// notes, unless the type asks for them already.
+func (password Password) Entries() []Entry {
+	var entries []Entry
+	add := func(entry Entry) {
+		if entry.Value != "" {
+			entries = append(entries, entry)
+		}
+	}
+
+	schema := password.Type.Schema()
+	for _, field := range schema.Fields {
+		add(Entry{
+			Label:     field.Label,
+			Value:     password.Value(field.Key),
+			Concealed: field.Concealed,
+			Key:       field.Key,
+		})
+	}
+	for _, url := range password.URLs {
+		add(Entry{Label: "URL", Value: url.URL, Rule: url.Rule})
+	}
+	for _, field := range password.Fields {
+		add(Entry{Label: field.Name, Value: field.Value, Concealed: field.Concealed})
+	}
+	if _, ok := schema.Field(KeyNotes); !ok {
+		add(Entry{Label: "Notes", Value: password.Notes})
+	}
+	return entries
+}
+
+// Display returns the value, masked when concealed unless revealed.
+func (entry Entry) Display(reveal bool) string {
+	if entry.Concealed && !reveal {
+		return strings.Repeat("•", 12)
+	}
+	return entry.Value
+}
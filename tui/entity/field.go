@@ -0,0 +1,48 @@
+package entity
+
+import (
+	"context"
+	"crypto/rsa"
+	"strconv"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// Field is a custom key/value pair attached to a password. A
+// concealed field is treated like a password and kept masked.
+type Field struct {
+	Id         int64  `db:"id"`
+	PasswordId int64  `db:"password_id"`
+	Name       string `db:"name"`
+	Value      string `db:"value"`
+	Concealed  bool   `db:"concealed"`
+	Position   int    `db:"position"`
+}
+
+// Encrypt seals both the name and the value. They are labeled
+// with the id of their password, hence it has to be set first.
+func (field *Field) Encrypt(pub *rsa.PublicKey) error {
+	group, _ := errgroup.WithContext(context.TODO())
+	for _, value := range []*string{&field.Name, &field.Value} {
+		group.Go(func() (err error) {
+			*value, err = sealValue(pub, *value, field.label())
+			return err
+		})
+	}
+	return group.Wait()
+}
+
+func (field *Field) Decrypt(priv *rsa.PrivateKey) error {
+	group, _ := errgroup.WithContext(context.TODO())
+	for _, value := range []*string{&field.Name, &field.Value} {
+		group.Go(func() (err error) {
+			*value, err = openValue(priv, *value, field.label())
+			return err
+		})
+	}
+	return group.Wait()
+}
+
+func (field Field) label() []byte {
+	return []byte("password_field:" + strconv.FormatInt(field.PasswordId, 10))
+}
@@ -0,0 +1,224 @@
+package entity
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+// ItemType tells what kind of secret an item holds. Every type
+// shares the same encrypted storage and categories, only the
+// values it asks for differ.
+type ItemType string
+
+const (
+	TypeLogin         ItemType = "login"
+	TypeCard          ItemType = "card"
+	TypeIdentity      ItemType = "identity"
+	TypeSSHKey        ItemType = "ssh_key"
+	TypeAPICredential ItemType = "api_credential"
+	TypeSecureNote    ItemType = "secure_note"
+)
+
+// ItemTypes lists every type in the order they are cycled through.
+var ItemTypes = []ItemType{
+	TypeLogin,
+	TypeCard,
+	TypeIdentity,
+	TypeSSHKey,
+	TypeAPICredential,
+	TypeSecureNote,
+}
+
+// Keys of the values shared by several types. They are stored
+// in their own columns instead of the type specific data.
+const (
+	KeyEmail    = "email"
+	KeyUsername = "username"
+	KeyPassword = "password"
+	KeyOtp      = "otp"
+	KeyNotes    = "notes"
+)
+
+// SchemaField describes a value asked by an item type.
+type SchemaField struct {
+	Key         string
+	Label       string
+	Placeholder string
+	Concealed   bool
+	Multiline   bool
+	Rules       []validation.Rule
+}
+
+// Schema describes the values of an item type and which
+// two of them are shown in the table.
+type Schema struct {
+	Label   string
+	Fields  []SchemaField
+	Columns [2]string
+	URLs    bool // whether the type is used on websites
+}
+
+var (
+	digitsRegex = regexp.MustCompile(`^[0-9 ]+$`)
+	expiryRegex = regexp.MustCompile(`^(0[1-9]|1[0-2])/[0-9]{2}$`)
+	cvvRegex    = regexp.MustCompile(`^[0-9]{3,4}$`)
+	dateRegex   = regexp.MustCompile(`^[0-9]{4}-[0-9]{2}-[0-9]{2}$`)
+)
+
+var schemas = map[ItemType]Schema{
+	TypeLogin: {
+		Label: "Login",
+		Fields: []SchemaField{
+			{Key: KeyEmail, Label: "Email", Rules: []validation.Rule{validation.Required}},
+			{Key: KeyUsername, Label: "Username"},
+			{Key: KeyPassword, Label: "Password", Concealed: true,
+				Rules: []validation.Rule{validation.Required}},
+			{Key: KeyOtp, Label: "OTP", Concealed: true,
+				Placeholder: "otpauth:// URI or base32 secret"},
+		},
+		Columns: [2]string{KeyEmail, KeyUsername},
+		URLs:    true,
+	},
+	TypeCard: {
+		Label: "Card",
+		Fields: []SchemaField{
+			{Key: "cardholder", Label: "Holder"},
+			{Key: "number", Label: "Number", Concealed: true,
+				Rules: []validation.Rule{
+					validation.Required,
+					validation.Match(digitsRegex).Error("must contain digits only"),
+					validation.By(luhn),
+				}},
+			{Key: "expiry", Label: "Expiry", Placeholder: "MM/YY",
+				Rules: []validation.Rule{
+					validation.Required,
+					validation.Match(expiryRegex).Error("must be in the form MM/YY"),
+				}},
+			{Key: "cvv", Label: "CVV", Concealed: true,
+				Rules: []validation.Rule{
+					validation.Match(cvvRegex).Error("must be 3 or 4 digits"),
+				}},
+			{Key: "pin", Label: "PIN", Concealed: true},
+		},
+		Columns: [2]string{"cardholder", "number"},
+	},
+	TypeIdentity: {
+		Label: "Identity",
+		Fields: []SchemaField{
+			{Key: "full_name", Label: "Full Name",
+				Rules: []validation.Rule{validation.Required}},
+			{Key: KeyEmail, Label: "Email"},
+			{Key: "phone", Label: "Phone"},
+			{Key: "birth_date", Label: "Birth Date", Placeholder: "YYYY-MM-DD",
+				Rules: []validation.Rule{
+					validation.Match(dateRegex).Error("must be in the form YYYY-MM-DD"),
+				}},
+			{Key: "address", Label: "Address", Multiline: true},
+		},
+		Columns: [2]string{"full_name", KeyEmail},
+	},
+	TypeSSHKey: {
+		Label: "SSH Key",
+		Fields: []SchemaField{
+			{Key: KeyUsername, Label: "Username"},
+			{Key: "host", Label: "Host"},
+			{Key: "public_key", Label: "Public Key", Multiline: true},
+			{Key: "private_key", Label: "Private", Concealed: true, Multiline: true,
+				Rules: []validation.Rule{validation.Required}},
+			{Key: "passphrase", Label: "Passphrase", Concealed: true},
+		},
+		Columns: [2]string{KeyUsername, "host"},
+	},
+	TypeAPICredential: {
+		Label: "API Credential",
+		Fields: []SchemaField{
+			{Key: "endpoint", Label: "Endpoint"},
+			{Key: "key", Label: "Key", Rules: []validation.Rule{validation.Required}},
+			{Key: "secret", Label: "Secret", Concealed: true,
+				Rules: []validation.Rule{validation.Required}},
+		},
+		Columns: [2]string{"endpoint", "key"},
+	},
+	TypeSecureNote: {
+		Label: "Secure Note",
+		Fields: []SchemaField{
+			{Key: KeyNotes, Label: "Note", Multiline: true,
+				Rules: []validation.Rule{validation.Required}},
+		},
+		Columns: [2]string{KeyNotes, ""},
+	},
+}
+
+// Schema returns the schema of the type, falling back to
+// the login one for unknown types.
+func (itemType ItemType) Schema() Schema {
+	schema, ok := schemas[itemType]
+	if !ok {
+		return schemas[TypeLogin]
+	}
+	return schema
+}
+
+// String returns the human readable name of the type.
+func (itemType ItemType) String() string {
+	return itemType.Schema().Label
+}
+
+// Next returns the following type, wrapping around.
+func (itemType ItemType) Next() ItemType {
+	for i, t := range ItemTypes {
+		if t == itemType {
+			return ItemTypes[(i+1)%len(ItemTypes)]
+		}
+	}
+	return TypeLogin
+}
+
+// Field returns the schema field with the given key.
+func (schema Schema) Field(key string) (SchemaField, bool) {
+	for _, field := range schema.Fields {
+		if field.Key == key {
+			return field, true
+		}
+	}
+	return SchemaField{}, false
+}
+
+// ColumnTitle returns the table header of the column showing the key.
+func (schema Schema) ColumnTitle(key string) string {
+	field, _ := schema.Field(key)
+	return field.Label
+}
+
+// luhn validates a card number with the Luhn checksum.
+func luhn(value any) error {
+	number := strings.ReplaceAll(value.(string), " ", "")
+	if number == "" {
+		return nil
+	}
+
+	sum := 0
+	double := false
+	for i := len(number) - 1; i >= 0; i-- {
+		digit, err := strconv.Atoi(string(number[i]))
+		if err != nil {
+			return errors.New("must contain digits only")
+		}
+		if double {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+		double = !double
+	}
+	if sum%10 != 0 {
+		return errors.New("is not a valid card number")
+	}
+	return nil
+}
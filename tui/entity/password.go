@@ -2,14 +2,15 @@ package entity
 
 import (
 	"context"
-	"crypto/rand"
 	"crypto/rsa"
-	"crypto/sha256"
 	"database/sql"
-	"encoding/hex"
-	"fmt"
+	"encoding/json"
+	"errors"
+	"maps"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	"viscue/tui/component/table"
 
@@ -18,24 +19,122 @@ import (
 	"golang.org/x/sync/errgroup"
 )
 
+// Password is an item of the library. Despite the name it holds
+// any ItemType: the values shared by several types have their own
+// columns while the rest is kept in Data.
 type Password struct {
-	Id         int64         `db:"id"`
-	CategoryId sql.NullInt64 `db:"category_id"`
-	Name       string        `db:"name"`
-	Email      string        `db:"email"`
-	Username   string        `db:"username"`
-	Password   string        `db:"password"`
+	Id         int64             `db:"id"`
+	CategoryId sql.NullInt64     `db:"category_id"`
+	Type       ItemType          `db:"type"`
+	Name       string            `db:"name"`
+	Email      string            `db:"email"`
+	Username   string            `db:"username"`
+	Password   string            `db:"password"`
+	Otp        string            `db:"otp"` // otpauth URI
+	Notes      string            `db:"notes"`
+	Data       map[string]string `db:"-"`    // type specific values
+	SealedData string            `db:"data"` // Data once encrypted
+	CreatedAt  time.Time         `db:"created_at"`
+	UpdatedAt  time.Time         `db:"updated_at"`
+	LastUsedAt sql.NullTime      `db:"last_used_at"`
+	Favorite   bool              `db:"favorite"`
+	DeletedAt  sql.NullTime      `db:"deleted_at"` // set while in the trash
+	Fields     []Field           `db:"-"`          // custom fields, stored separately
+	URLs       []URL             `db:"-"`          // stored separately as well
+	Tags       []string          `db:"-"`          // names of the tags
+}
+
+// HasTag tells whether the password is tagged with the name,
+// compared case-insensitively like the tags table does.
+func (password Password) HasTag(name string) bool {
+	return slices.ContainsFunc(password.Tags, func(tag string) bool {
+		return strings.EqualFold(tag, name)
+	})
+}
+
+// PrimaryURL returns the first URL of the password, if any.
+func (password Password) PrimaryURL() string {
+	if len(password.URLs) == 0 {
+		return ""
+	}
+	return password.URLs[0].URL
+}
+
+// Value returns the value of the given schema key.
+func (password Password) Value(key string) string {
+	switch key {
+	case KeyEmail:
+		return password.Email
+	case KeyUsername:
+		return password.Username
+	case KeyPassword:
+		return password.Password
+	case KeyOtp:
+		return password.Otp
+	case KeyNotes:
+		return password.Notes
+	default:
+		return password.Data[key]
+	}
+}
+
+// SetValue sets the value of the given schema key.
+func (password *Password) SetValue(key, value string) {
+	switch key {
+	case KeyEmail:
+		password.Email = value
+	case KeyUsername:
+		password.Username = value
+	case KeyPassword:
+		password.Password = value
+	case KeyOtp:
+		password.Otp = value
+	case KeyNotes:
+		password.Notes = value
+	default:
+		if password.Data == nil {
+			password.Data = make(map[string]string)
+		}
+		if value == "" {
+			delete(password.Data, key)
+			return
+		}
+		password.Data[key] = value
+	}
+}
+
+// DisplayValue returns the value of the given schema key as shown
+// in the table. Concealed values only reveal their last characters.
+func (password Password) DisplayValue(key string) string {
+	value := password.Value(key)
+	if value == "" {
+		return "-"
+	}
+	if field, _ := password.Type.Schema().Field(key); field.Concealed {
+		visible := max(len(value)-4, 0)
+		return "•••• " + value[visible:]
+	}
+	line, _, _ := strings.Cut(value, "\n")
+	return line
 }
 
 func (password Password) Validate() error {
-	err := validation.ValidateStruct(&password,
-		validation.Field(&password.Name, validation.Required),
-		validation.Field(&password.Email, validation.Required),
-		validation.Field(&password.Password, validation.Required),
-	)
-	if err != nil {
+	errs := validation.Errors{
+		"name": validation.Validate(password.Name, validation.Required),
+	}
+	for _, field := range password.Type.Schema().Fields {
+		errs[strings.ToLower(field.Label)] = validation.Validate(
+			password.Value(field.Key), field.Rules...)
+	}
+	for _, url := range password.URLs {
+		if err := url.Validate(); err != nil {
+			errs["url"] = err
+			break
+		}
+	}
+	if err := errs.Filter(); err != nil {
 		msg := strings.Split(err.Error(), "; ")
-		return fmt.Errorf(strings.Join(msg, " and "))
+		return errors.New(strings.Join(msg, " and "))
 	}
 
 	return nil
@@ -45,85 +144,109 @@ func (password Password) Copy() Password {
 	return Password{
 		Id:         password.Id,
 		CategoryId: password.CategoryId,
+		Type:       password.Type,
 		Name:       password.Name,
 		Email:      password.Email,
 		Username:   password.Username,
 		Password:   password.Password,
+		Otp:        password.Otp,
+		Notes:      password.Notes,
+		Data:       maps.Clone(password.Data),
+		SealedData: password.SealedData,
+		CreatedAt:  password.CreatedAt,
+		UpdatedAt:  password.UpdatedAt,
+		LastUsedAt: password.LastUsedAt,
+		Favorite:   password.Favorite,
+		DeletedAt:  password.DeletedAt,
+		Fields:     slices.Clone(password.Fields),
+		URLs:       slices.Clone(password.URLs),
+		Tags:       slices.Clone(password.Tags),
 	}
 }
 
 func (password *Password) Encrypt(pub *rsa.PublicKey) error {
 	group, _ := errgroup.WithContext(context.TODO())
-	group.Go(func() error { // Encrypt email
-		b, err := rsa.EncryptOAEP(sha256.New(), rand.Reader, pub,
-			[]byte(password.Email), []byte(password.Name))
-		if err != nil {
+	for _, field := range password.encryptedFields() {
+		group.Go(func() (err error) {
+			*field, err = encryptValue(pub, *field, []byte(password.Name))
 			return err
-		}
-		password.Email = hex.EncodeToString(b)
-		return nil
-	})
-	group.Go(func() error { // Encrypt password
-		b, err := rsa.EncryptOAEP(sha256.New(), rand.Reader, pub,
-			[]byte(password.Password), []byte(password.Name))
-		if err != nil {
+		})
+	}
+	if password.Notes != "" {
+		group.Go(func() (err error) {
+			password.Notes, err = sealValue(pub, password.Notes,
+				[]byte(password.Name))
 			return err
-		}
-		password.Password = hex.EncodeToString(b)
-		return nil
-	})
-
+		})
+	}
+	if len(password.Data) > 0 {
+		group.Go(func() error {
+			data, err := json.Marshal(password.Data)
+			if err != nil {
+				return err
+			}
+			password.SealedData, err = sealValue(pub, string(data),
+				[]byte(password.Name))
+			return err
+		})
+	} else {
+		password.SealedData = ""
+	}
 	return group.Wait()
 }
 
 func (password *Password) Decrypt(priv *rsa.PrivateKey) error {
 	group, _ := errgroup.WithContext(context.TODO())
-	group.Go(func() error { // Decrypt email
-		decoded, err := hex.DecodeString(password.Email)
-		if err != nil {
-			return err
-		}
-
-		plaintext, err := rsa.DecryptOAEP(sha256.New(), rand.Reader, priv,
-			decoded, []byte(password.Name))
-		if err != nil {
-			return err
-		}
-
-		password.Email = string(plaintext)
-		return nil
-	})
-	group.Go(func() error { // Decrypt password
-		decoded, err := hex.DecodeString(password.Password)
-		if err != nil {
+	for _, field := range password.encryptedFields() {
+		group.Go(func() (err error) {
+			*field, err = decryptValue(priv, *field, []byte(password.Name))
 			return err
-		}
-
-		plaintext, err := rsa.DecryptOAEP(sha256.New(), rand.Reader, priv,
-			decoded, []byte(password.Name))
-		if err != nil {
+		})
+	}
+	if password.Notes != "" {
+		group.Go(func() (err error) {
+			password.Notes, err = openValue(priv, password.Notes,
+				[]byte(password.Name))
 			return err
-		}
-
-		password.Password = string(plaintext)
-		return nil
-	})
-
+		})
+	}
+	if password.SealedData != "" {
+		group.Go(func() error {
+			data, err := openValue(priv, password.SealedData,
+				[]byte(password.Name))
+			if err != nil {
+				return err
+			}
+			return json.Unmarshal([]byte(data), &password.Data)
+		})
+	}
 	return group.Wait()
 }
 
+// encryptedFields returns the fields stored encrypted. Optional
+// fields are left out when empty so that they stay empty.
+func (password *Password) encryptedFields() []*string {
+	fields := []*string{&password.Email, &password.Password}
+	if password.Otp != "" {
+		fields = append(fields, &password.Otp)
+	}
+	return fields
+}
+
 func (password Password) ToTableRow() table.Row {
-	username := "-"
-	if password.Username != "" {
-		username = password.Username
+	columns := password.Type.Schema().Columns
+	second := ""
+	if columns[1] != "" {
+		second = password.DisplayValue(columns[1])
 	}
 
 	return table.Row{
 		strconv.FormatInt(password.Id, 10),               // ID (hidden)
 		strconv.FormatInt(password.CategoryId.Int64, 10), // CategoryId (hidden)
 		password.Name,
-		password.Email,
-		username,
+		password.DisplayValue(columns[0]),
+		second,
+		displayURL(password.PrimaryURL()),
 		password.Password, // Password (hidden)
 	}
 }
@@ -133,7 +256,7 @@ func NewPasswordFromTableRow(row table.Row) (Password, error) {
 		Name:     row[2],
 		Email:    row[3],
 		Username: row[4],
-		Password: row[5],
+		Password: row[6],
 	}
 	id, err := strconv.ParseInt(row[0], 10, 64)
 	if err != nil {
@@ -0,0 +1,32 @@
+package entity
+
+import (
+	"crypto/rsa"
+	"strconv"
+	"time"
+)
+
+// PasswordHistory is a previous value of a password. Unlike the
+// password itself, it is labeled with the id of its password so
+// that it stays readable after the password is renamed.
+type PasswordHistory struct {
+	Id         int64     `db:"id"`
+	PasswordId int64     `db:"password_id"`
+	Password   string    `db:"password"`
+	CreatedAt  time.Time `db:"created_at"`
+}
+
+func (history *PasswordHistory) Encrypt(pub *rsa.PublicKey) (err error) {
+	history.Password, err = encryptValue(pub, history.Password, history.label())
+	return err
+}
+
+func (history *PasswordHistory) Decrypt(priv *rsa.PrivateKey) (err error) {
+	history.Password, err = decryptValue(priv, history.Password, history.label())
+	return err
+}
+
+func (history PasswordHistory) label() []byte {
+	return []byte("password_history:" +
+		strconv.FormatInt(history.PasswordId, 10))
+}
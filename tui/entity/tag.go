@@ -0,0 +1,13 @@
+package entity
+
+// Tag labels passwords across categories. Unlike a category,
+// a password can have any number of tags.
+type Tag struct {
+	Id   int64  `db:"id"`
+	Name string `db:"name"`
+}
+
+// String implements list.Item
+func (tag Tag) String() string {
+	return tag.Name
+}
@@ -0,0 +1,82 @@
+package entity
+
+import (
+	"crypto/rsa"
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"viscue/tui/tool/urlmatch"
+
+	"github.com/samber/lo"
+)
+
+// URL is a website an item is used on. The first one is the
+// primary URL, shown in the table.
+type URL struct {
+	Id         int64         `db:"id"`
+	PasswordId int64         `db:"password_id"`
+	URL        string        `db:"url"`
+	Rule       urlmatch.Rule `db:"match_rule"`
+	Position   int           `db:"position"`
+}
+
+// Encrypt seals the URL. It is labeled with the id of
+// its password, hence it has to be set first.
+func (url *URL) Encrypt(pub *rsa.PublicKey) (err error) {
+	url.URL, err = sealValue(pub, url.URL, url.label())
+	return err
+}
+
+func (url *URL) Decrypt(priv *rsa.PrivateKey) (err error) {
+	url.URL, err = openValue(priv, url.URL, url.label())
+	return err
+}
+
+// Validate checks that the URL can be matched against, i.e. that
+// it compiles as a regular expression when it is one.
+func (url URL) Validate() error {
+	if _, err := urlmatch.ParseRule(string(url.Rule)); err != nil {
+		return err
+	}
+	if url.Rule == urlmatch.Regex {
+		if _, err := regexp.Compile(url.URL); err != nil {
+			return errors.New("is not a valid regular expression")
+		}
+		return nil
+	}
+	if _, err := urlmatch.Parse(url.URL); err != nil {
+		return errors.New("is not a valid url")
+	}
+	return nil
+}
+
+func (url URL) label() []byte {
+	return []byte("password_url:" + strconv.FormatInt(url.PasswordId, 10))
+}
+
+// displayURL trims the scheme off the URL to save some room.
+func displayURL(url string) string {
+	if url == "" {
+		return "-"
+	}
+	_, rest, found := strings.Cut(url, "://")
+	if !found {
+		return url
+	}
+	return rest
+}
+
+// LookupURL returns the passwords having a URL matching the
+// target, the most precisely matched ones first. When rules are
+// given, only the URLs matched by one of them are considered.
+func LookupURL(passwords []Password, target string, rules ...urlmatch.Rule) []Password {
+	return urlmatch.Lookup(target, passwords,
+		func(password Password) []urlmatch.Pattern {
+			return lo.FilterMap(password.URLs, func(url URL, _ int) (urlmatch.Pattern, bool) {
+				pattern := urlmatch.Pattern{URL: url.URL, Rule: url.Rule}
+				return pattern, len(rules) == 0 || lo.Contains(rules, url.Rule)
+			})
+		})
+}
@@ -2,22 +2,29 @@ package style
 
 import "github.com/charmbracelet/lipgloss"
 
+// Color is a color of the active theme. Styles hold on to it rather
+// than to the color itself, so that applying another theme restyles
+// everything already built on the next render. Colors are left out
+// until the default theme is applied.
+type Color struct {
+	lipgloss.TerminalColor
+}
+
 var (
-	ColorNormal = lipgloss.AdaptiveColor{
-		Light: "#000000", // Black
-		Dark:  "#FFFFFF", // White
-	}
-	ColorPurple     = lipgloss.AdaptiveColor{Light: "#A020F0", Dark: "#A020F0"}
-	ColorPurplePale = lipgloss.AdaptiveColor{
-		Light: "#F0A0F0", Dark: "#F0A0F0",
-	}
-	ColorRed = lipgloss.AdaptiveColor{
-		Light: "#FF3333", Dark: "#FF3333",
-	}
-	ColorRedPale = lipgloss.AdaptiveColor{
-		Light: "#FFA0A0", Dark: "#FFA0A0",
-	}
-	ColorGray = lipgloss.AdaptiveColor{
-		Light: "#969696", Dark: "#c2c2c2",
-	}
+	ColorNormal       = &Color{lipgloss.NoColor{}} // Text
+	ColorContrast     = &Color{lipgloss.NoColor{}} // Text over accent and muted backgrounds
+	ColorAccent       = &Color{lipgloss.NoColor{}}
+	ColorAccentPale   = &Color{lipgloss.NoColor{}}
+	ColorError        = &Color{lipgloss.NoColor{}}
+	ColorErrorPale    = &Color{lipgloss.NoColor{}}
+	ColorSuccess      = &Color{lipgloss.NoColor{}}
+	ColorWarning      = &Color{lipgloss.NoColor{}}
+	ColorMuted        = &Color{lipgloss.NoColor{}}
+	ColorButtonText   = &Color{lipgloss.NoColor{}}
+	ColorButton       = &Color{lipgloss.NoColor{}}
+	ColorButtonActive = &Color{lipgloss.NoColor{}}
 )
+
+func init() {
+	Apply(DefaultTheme())
+}
@@ -11,7 +11,7 @@ func NewSpinner() spinner.Model {
 	s := spinner.New(
 		spinner.WithSpinner(spinner.Jump),
 		spinner.WithStyle(lipgloss.NewStyle().
-			Foreground(ColorPurple),
+			Foreground(ColorAccent),
 		),
 	)
 
@@ -21,14 +21,13 @@ func NewSpinner() spinner.Model {
 // Button
 
 var ButtonStyle = lipgloss.NewStyle().
-	Foreground(lipgloss.Color("#FFF7DB")).
-	Background(lipgloss.Color("#888B7E")).
+	Foreground(ColorButtonText).
+	Background(ColorButton).
 	Padding(0, 2).
 	MarginTop(1)
 
 var ActiveButtonStyle = ButtonStyle.
-	Foreground(lipgloss.Color("#FFF7DB")).
-	Background(lipgloss.Color("#F25D94")).
+	Background(ColorButtonActive).
 	MarginRight(2).
 	Underline(true)
 
@@ -42,7 +41,7 @@ var TextInputPromptStyle = lipgloss.NewStyle().
 	MarginRight(1)
 
 var SearchBoxStyle = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).
-	BorderForeground(ColorPurple).
+	BorderForeground(ColorAccent).
 	Align(lipgloss.Left, lipgloss.Center).
 	PaddingLeft(1).
 	PaddingRight(1)
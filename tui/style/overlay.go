@@ -0,0 +1,46 @@
+package style
+
+import (
+	"math"
+	"strings"
+
+	"viscue/tui/tool/zone"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Overlay draws the foreground over the background, placed at
+// the given horizontal and vertical positions, e.g. a toast at
+// the bottom right corner of the screen.
+func Overlay(background, foreground string, x, y lipgloss.Position) string {
+	if foreground == "" {
+		return background
+	}
+	lines := strings.Split(background, "\n")
+	boxLines := strings.Split(foreground, "\n")
+	boxWidth := lipgloss.Width(foreground)
+	left := offset(x, lipgloss.Width(background), boxWidth)
+	top := offset(y, len(lines), len(boxLines))
+
+	for i, line := range boxLines {
+		row := top + i
+		if row >= len(lines) {
+			break
+		}
+		before := ansi.Truncate(zone.Clip(lines[row], 0, left), left, "")
+		if gap := left - ansi.StringWidth(before); gap > 0 {
+			before += strings.Repeat(" ", gap)
+		}
+		after := ansi.TruncateLeft(
+			zone.Clip(lines[row], left+boxWidth, math.MaxInt), left+boxWidth, "")
+		lines[row] = before + ansi.ResetStyle + line + after
+	}
+	return strings.Join(lines, "\n")
+}
+
+// offset returns where a box of the inner size starts
+// within the outer one at the given position.
+func offset(position lipgloss.Position, outer, inner int) int {
+	return max(0, int(math.Round(float64(position)*float64(outer-inner))))
+}
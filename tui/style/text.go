@@ -7,44 +7,87 @@ import (
 )
 
 var (
-	ErrorText = lipgloss.NewStyle().MarginTop(2).Foreground(ColorRed).Render
+	ErrorText = lipgloss.NewStyle().MarginTop(2).Foreground(ColorError).Render
 
 	LogoContainer = lipgloss.NewStyle().Align(lipgloss.Center,
 		lipgloss.Center).
 		MarginBottom(2)
 	Logo = lipgloss.NewStyle().
 		Align(lipgloss.Center, lipgloss.Center).
-		Foreground(ColorPurple).
+		Foreground(ColorAccent).
 		SetString(` ▌ ▐·▪  .▄▄ ·  ▄▄· ▄• ▄▌▄▄▄ .
 ▪█·█▌██ ▐█ ▀. ▐█ ▌▪█▪██▌▀▄.▀·
 ▐█▐█•▐█·▄▀▀▀█▄██ ▄▄█▌▐█▌▐▀▀▪▄
  ███ ▐█▌▐█▄▪▐█▐███▌▐█▄█▌▐█▄▄▌
 . ▀  ▀▀▀ ▀▀▀▀ ·▀▀▀  ▀▀▀  ▀▀▀
-`).Render()
+`)
 	SubLogo = lipgloss.NewStyle().
 		Align(lipgloss.Center, lipgloss.Center).
-		Foreground(ColorPurplePale).
-		SetString(`Your personal terminal password manager.`).Render()
-	HeaderHeight = lipgloss.Height(Logo) + lipgloss.Height(SubLogo) + 2
-
-	HelpContainer = lipgloss.NewStyle().
-			Align(lipgloss.Center, lipgloss.Center).
-			Height(HelpViewHeight).
-			MaxHeight(HelpViewHeight).
-			Render
-
-	ModelTitleStyle = lipgloss.NewStyle().Background(ColorGray).
-			Foreground(ColorNormal).
+		Foreground(ColorAccentPale).
+		SetString(`Your personal terminal password manager.`)
+	HeaderHeight = lipgloss.Height(Logo.String()) + lipgloss.Height(SubLogo.String()) + 2
+
+	ModelTitleStyle = lipgloss.NewStyle().Background(ColorMuted).
+			Foreground(ColorContrast).
 			MarginBottom(1).
 			Padding(0, 1)
-	ModelTitleFocusedStyle = ModelTitleStyle.Background(ColorPurple)
+	ModelTitleFocusedStyle = ModelTitleStyle.Background(ColorAccent)
 )
 
 const (
 	HelpViewHeight = 4 // Maximum height of the help view
+
+	// Below these, the layout turns compact: no logo, the help on
+	// a single line and the sidebar collapsed into a dropdown. The
+	// header, the help, the borders and the search of the regular
+	// layout take 24 lines, which leaves the table and its header
+	// nothing on a 24 line terminal and only a handful of rows up
+	// to 30 lines.
+	CompactWidth, CompactHeight = 90, 30
+	// Below these, nothing fits anymore
+	MinWidth, MinHeight = 50, 15
 )
 
+// IsCompact reports whether the terminal is too small
+// for the regular layout.
+func IsCompact() bool {
+	return cache.Get[int](cache.TerminalWidth) < CompactWidth ||
+		cache.Get[int](cache.TerminalHeight) < CompactHeight
+}
+
+// HelpHeight is the height of the help view, a single line of
+// the short help when compact.
+func HelpHeight() int {
+	if IsCompact() {
+		return 1
+	}
+	return HelpViewHeight
+}
+
+// HelpContainer centers the help view within its height, cutting
+// what the help lets overflow when the ellipsis does not fit.
+func HelpContainer(view string) string {
+	return lipgloss.NewStyle().
+		Align(lipgloss.Center, lipgloss.Center).
+		Height(HelpHeight()).
+		MaxHeight(HelpHeight()).
+		MaxWidth(cache.Get[int](cache.TerminalWidth)).
+		Render(view)
+}
+
 func CalculateAppHeight() int {
 	terminalHeight := cache.Get[int](cache.TerminalHeight)
+	if IsCompact() {
+		return terminalHeight - HelpHeight()
+	}
 	return terminalHeight - HeaderHeight - HelpViewHeight
 }
+
+// PanelWidth returns the percent of the width a panel takes,
+// or the whole of it when compact.
+func PanelWidth(appWidth, percent int) int {
+	if IsCompact() {
+		return appWidth
+	}
+	return appWidth * percent / 100
+}
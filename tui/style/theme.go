@@ -0,0 +1,224 @@
+package style
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Theme is a palette filling every color of the interface.
+type Theme struct {
+	Name string
+
+	Normal       lipgloss.TerminalColor
+	Contrast     lipgloss.TerminalColor
+	Accent       lipgloss.TerminalColor
+	AccentPale   lipgloss.TerminalColor
+	Error        lipgloss.TerminalColor
+	ErrorPale    lipgloss.TerminalColor
+	Success      lipgloss.TerminalColor
+	Warning      lipgloss.TerminalColor
+	Muted        lipgloss.TerminalColor
+	ButtonText   lipgloss.TerminalColor
+	Button       lipgloss.TerminalColor
+	ButtonActive lipgloss.TerminalColor
+}
+
+// Colors maps the name of every color of the theme, as written
+// in a custom theme, to where the theme holds it.
+func (theme *Theme) Colors() map[string]*lipgloss.TerminalColor {
+	return map[string]*lipgloss.TerminalColor{
+		"normal":        &theme.Normal,
+		"contrast":      &theme.Contrast,
+		"accent":        &theme.Accent,
+		"accent_pale":   &theme.AccentPale,
+		"error":         &theme.Error,
+		"error_pale":    &theme.ErrorPale,
+		"success":       &theme.Success,
+		"warning":       &theme.Warning,
+		"muted":         &theme.Muted,
+		"button_text":   &theme.ButtonText,
+		"button":        &theme.Button,
+		"button_active": &theme.ButtonActive,
+	}
+}
+
+// Colorless reports whether the theme draws no color at all, in
+// which case selections are told apart by reversing the video.
+func (theme Theme) Colorless() bool {
+	for _, color := range theme.Colors() {
+		if _, ok := (*color).(lipgloss.NoColor); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+const (
+	ThemeAuto         = "auto"
+	ThemeDark         = "dark"
+	ThemeLight        = "light"
+	ThemeHighContrast = "high-contrast"
+	ThemeSolarized    = "solarized"
+	ThemeNoColor      = "no-color"
+)
+
+var (
+	darkTheme = Theme{
+		Name:         ThemeDark,
+		Normal:       lipgloss.Color("#FFFFFF"),
+		Contrast:     lipgloss.Color("#FFFFFF"),
+		Accent:       lipgloss.Color("#A020F0"),
+		AccentPale:   lipgloss.Color("#F0A0F0"),
+		Error:        lipgloss.Color("#FF3333"),
+		ErrorPale:    lipgloss.Color("#FFA0A0"),
+		Success:      lipgloss.Color("#33CC66"),
+		Warning:      lipgloss.Color("#FFCC33"),
+		Muted:        lipgloss.Color("#C2C2C2"),
+		ButtonText:   lipgloss.Color("#FFF7DB"),
+		Button:       lipgloss.Color("#888B7E"),
+		ButtonActive: lipgloss.Color("#F25D94"),
+	}
+	lightTheme = Theme{
+		Name:         ThemeLight,
+		Normal:       lipgloss.Color("#000000"),
+		Contrast:     lipgloss.Color("#000000"),
+		Accent:       lipgloss.Color("#A020F0"),
+		AccentPale:   lipgloss.Color("#F0A0F0"),
+		Error:        lipgloss.Color("#FF3333"),
+		ErrorPale:    lipgloss.Color("#FFA0A0"),
+		Success:      lipgloss.Color("#008000"),
+		Warning:      lipgloss.Color("#B8860B"),
+		Muted:        lipgloss.Color("#969696"),
+		ButtonText:   lipgloss.Color("#FFF7DB"),
+		Button:       lipgloss.Color("#888B7E"),
+		ButtonActive: lipgloss.Color("#F25D94"),
+	}
+	highContrastTheme = Theme{
+		Name:         ThemeHighContrast,
+		Normal:       lipgloss.Color("#FFFFFF"),
+		Contrast:     lipgloss.Color("#000000"),
+		Accent:       lipgloss.Color("#FFFF00"),
+		AccentPale:   lipgloss.Color("#FFFFFF"),
+		Error:        lipgloss.Color("#FF0000"),
+		ErrorPale:    lipgloss.Color("#FF8080"),
+		Success:      lipgloss.Color("#00FF00"),
+		Warning:      lipgloss.Color("#FFA500"),
+		Muted:        lipgloss.Color("#C0C0C0"),
+		ButtonText:   lipgloss.Color("#000000"),
+		Button:       lipgloss.Color("#C0C0C0"),
+		ButtonActive: lipgloss.Color("#FFFF00"),
+	}
+	// solarizedTheme follows the palette of Ethan Schoonover,
+	// adapting its base tones to the terminal background.
+	solarizedTheme = Theme{
+		Name:         ThemeSolarized,
+		Normal:       lipgloss.AdaptiveColor{Light: "#586E75", Dark: "#93A1A1"},
+		Contrast:     lipgloss.AdaptiveColor{Light: "#FDF6E3", Dark: "#FDF6E3"},
+		Accent:       lipgloss.Color("#268BD2"),
+		AccentPale:   lipgloss.Color("#2AA198"),
+		Error:        lipgloss.Color("#DC322F"),
+		ErrorPale:    lipgloss.Color("#CB4B16"),
+		Success:      lipgloss.Color("#859900"),
+		Warning:      lipgloss.Color("#B58900"),
+		Muted:        lipgloss.AdaptiveColor{Light: "#93A1A1", Dark: "#586E75"},
+		ButtonText:   lipgloss.Color("#FDF6E3"),
+		Button:       lipgloss.Color("#657B83"),
+		ButtonActive: lipgloss.Color("#D33682"),
+	}
+	noColorTheme = Theme{
+		Name:         ThemeNoColor,
+		Normal:       lipgloss.NoColor{},
+		Contrast:     lipgloss.NoColor{},
+		Accent:       lipgloss.NoColor{},
+		AccentPale:   lipgloss.NoColor{},
+		Error:        lipgloss.NoColor{},
+		ErrorPale:    lipgloss.NoColor{},
+		Success:      lipgloss.NoColor{},
+		Warning:      lipgloss.NoColor{},
+		Muted:        lipgloss.NoColor{},
+		ButtonText:   lipgloss.NoColor{},
+		Button:       lipgloss.NoColor{},
+		ButtonActive: lipgloss.NoColor{},
+	}
+)
+
+// Themes returns the built-in themes by name.
+func Themes() map[string]Theme {
+	return map[string]Theme{
+		ThemeAuto:         autoTheme(),
+		ThemeDark:         darkTheme,
+		ThemeLight:        lightTheme,
+		ThemeHighContrast: highContrastTheme,
+		ThemeSolarized:    solarizedTheme,
+		ThemeNoColor:      noColorTheme,
+	}
+}
+
+// ThemeNames returns the names of the built-in themes, sorted.
+func ThemeNames() []string {
+	return slices.Sorted(maps.Keys(Themes()))
+}
+
+// DefaultTheme is the theme used when none has been picked,
+// the light or dark one depending on the terminal background.
+func DefaultTheme() Theme {
+	return autoTheme()
+}
+
+// autoTheme picks every color from the light theme on a light
+// background and from the dark theme on a dark one.
+func autoTheme() Theme {
+	theme := Theme{Name: ThemeAuto}
+	darkColors := darkTheme.Colors()
+	for name, color := range lightTheme.Colors() {
+		*theme.Colors()[name] = lipgloss.AdaptiveColor{
+			Light: string((*color).(lipgloss.Color)),
+			Dark:  string((*darkColors[name]).(lipgloss.Color)),
+		}
+	}
+	return theme
+}
+
+var active Theme
+
+// Apply makes the theme the active one, restyling the interface
+// on the next render. Colors missing from it are left out.
+func Apply(theme Theme) {
+	slots := map[string]*Color{
+		"normal":        ColorNormal,
+		"contrast":      ColorContrast,
+		"accent":        ColorAccent,
+		"accent_pale":   ColorAccentPale,
+		"error":         ColorError,
+		"error_pale":    ColorErrorPale,
+		"success":       ColorSuccess,
+		"warning":       ColorWarning,
+		"muted":         ColorMuted,
+		"button_text":   ColorButtonText,
+		"button":        ColorButton,
+		"button_active": ColorButtonActive,
+	}
+	for name, color := range theme.Colors() {
+		if *color == nil {
+			*color = lipgloss.NoColor{}
+		}
+		slots[name].TerminalColor = *color
+	}
+	active = theme
+}
+
+// ActiveTheme returns the theme currently applied.
+func ActiveTheme() Theme {
+	return active
+}
+
+// Emphasize makes the style, drawn over an accent or muted background,
+// stand out even when the active theme has no color.
+func Emphasize(style lipgloss.Style) lipgloss.Style {
+	if active.Colorless() {
+		return style.Reverse(true)
+	}
+	return style
+}
@@ -0,0 +1,59 @@
+package breach
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// rangeAPI talks to a server implementing the k-anonymity
+// range API, i.e. `GET {base}/range/{prefix}` responding with
+// `SUFFIX:COUNT` lines. Only the first five characters of the
+// hash ever leave the machine.
+type rangeAPI struct {
+	base   string
+	mode   Mode
+	client *http.Client
+}
+
+func newRangeAPI(base string, mode Mode) *rangeAPI {
+	return &rangeAPI{
+		base:   strings.TrimSuffix(base, "/"),
+		mode:   mode,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (a *rangeAPI) Count(password string) (int, error) {
+	hash := a.mode.hash(password)
+	prefix, suffix := hash[:prefixLength], hash[prefixLength:]
+
+	url := fmt.Sprintf("%s/range/%s", a.base, prefix)
+	if a.mode == NTLM {
+		url += "?mode=ntlm"
+	}
+
+	res, err := a.client.Get(url)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("pwned passwords range API responded with %s",
+			res.Status)
+	}
+
+	// Responses are not guaranteed to be sorted, hence
+	// we go through every line instead of using scanFor.
+	scanner := bufio.NewScanner(res.Body)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if lineHash(line) == suffix {
+			return lineCount(line)
+		}
+	}
+	return 0, scanner.Err()
+}
@@ -0,0 +1,97 @@
+// Package breach checks passwords against the Pwned Passwords
+// dataset without ever sending a hash to a live service. The
+// dataset can either be a downloaded copy on disk (a single
+// sorted file or a directory of range files) or a local server
+// that exposes the same k-anonymity range API.
+package breach
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"unicode/utf16"
+
+	"golang.org/x/crypto/md4"
+)
+
+// Mode is the hash flavour of the dataset.
+type Mode string
+
+const (
+	SHA1 Mode = "sha1"
+	NTLM Mode = "ntlm"
+)
+
+const (
+	// SourceEnv points to the dataset. It can be a sorted file,
+	// a directory of range files or an http(s) base URL.
+	SourceEnv = "hibp_source"
+	// ModeEnv selects the hash flavour of the dataset, either
+	// `sha1` (default) or `ntlm`.
+	ModeEnv = "hibp_mode"
+
+	// prefixLength is the length of the hash prefix used
+	// by the range API and the range files.
+	prefixLength = 5
+)
+
+var ErrUnknownMode = errors.New("unknown pwned passwords hash mode")
+
+// Checker looks up how many times a password has been seen
+// in a breach. A count of zero means it was never seen.
+type Checker interface {
+	Count(password string) (int, error)
+}
+
+// New creates a Checker reading from the given source.
+func New(source string, mode Mode) (Checker, error) {
+	if mode == "" {
+		mode = SHA1
+	}
+	if mode != SHA1 && mode != NTLM {
+		return nil, ErrUnknownMode
+	}
+
+	if strings.HasPrefix(source, "http://") ||
+		strings.HasPrefix(source, "https://") {
+		return newRangeAPI(source, mode), nil
+	}
+
+	info, err := os.Stat(source)
+	if err != nil {
+		return nil, fmt.Errorf("failed reading pwned passwords source: %w", err)
+	}
+	if info.IsDir() {
+		return &rangeDirectory{path: source, mode: mode}, nil
+	}
+	return &sortedFile{path: source, mode: mode}, nil
+}
+
+// FromEnv creates a Checker from the environment variables.
+// It returns a nil Checker when no source is configured.
+func FromEnv() (Checker, error) {
+	source, ok := os.LookupEnv(SourceEnv)
+	if !ok || source == "" {
+		return nil, nil
+	}
+	return New(source, Mode(strings.ToLower(os.Getenv(ModeEnv))))
+}
+
+// hash returns the upper-cased hex digest of the password
+// matching the format used by the Pwned Passwords dataset.
+func (mode Mode) hash(password string) string {
+	switch mode {
+	case NTLM:
+		h := md4.New()
+		for _, r := range utf16.Encode([]rune(password)) {
+			h.Write([]byte{byte(r), byte(r >> 8)})
+		}
+		return strings.ToUpper(hex.EncodeToString(h.Sum(nil)))
+	default:
+		sum := sha1.Sum([]byte(password))
+		return strings.ToUpper(hex.EncodeToString(sum[:]))
+	}
+}
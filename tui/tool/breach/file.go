@@ -0,0 +1,136 @@
+package breach
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// scanThreshold is the window size under which the binary
+// search stops seeking and scans the remaining lines.
+const scanThreshold = 4096
+
+// sortedFile is a single file where each line has the form
+// `HASH:COUNT` and lines are sorted by hash.
+type sortedFile struct {
+	path string
+	mode Mode
+}
+
+func (f *sortedFile) Count(password string) (int, error) {
+	hash := f.mode.hash(password)
+
+	file, err := os.Open(f.path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	stat, err := file.Stat()
+	if err != nil {
+		return 0, err
+	}
+
+	// Narrow the window down by probing the first complete
+	// line after the middle offset until it is small enough.
+	low, high := int64(0), stat.Size()
+	for high-low > scanThreshold {
+		mid := low + (high-low)/2
+		line, err := lineAfter(file, mid)
+		if err != nil && err != io.EOF {
+			return 0, err
+		}
+		if line != "" && lineHash(line) < hash {
+			low = mid
+		} else {
+			high = mid
+		}
+	}
+
+	if _, err = file.Seek(low, io.SeekStart); err != nil {
+		return 0, err
+	}
+	reader := bufio.NewReader(file)
+	if low > 0 {
+		// Skip the partial line, it is known to be lower.
+		if _, err = reader.ReadString('\n'); err != nil {
+			return 0, nil
+		}
+	}
+	return scanFor(reader, hash)
+}
+
+// rangeDirectory is a directory of range files named after the
+// first five characters of the hash, e.g. `21BD1.txt`, where each
+// line has the form `SUFFIX:COUNT`.
+type rangeDirectory struct {
+	path string
+	mode Mode
+}
+
+func (d *rangeDirectory) Count(password string) (int, error) {
+	hash := d.mode.hash(password)
+	prefix, suffix := hash[:prefixLength], hash[prefixLength:]
+
+	file, err := os.Open(filepath.Join(d.path, prefix+".txt"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	defer file.Close()
+
+	return scanFor(bufio.NewReader(file), suffix)
+}
+
+// lineAfter returns the first complete line starting after offset.
+func lineAfter(file *os.File, offset int64) (string, error) {
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return "", err
+	}
+	reader := bufio.NewReader(file)
+	if _, err := reader.ReadString('\n'); err != nil {
+		return "", err
+	}
+	line, err := reader.ReadString('\n')
+	return strings.TrimSpace(line), err
+}
+
+// scanFor reads sorted lines until it finds the hash or
+// passes the position where the hash would have been.
+func scanFor(reader *bufio.Reader, hash string) (int, error) {
+	for {
+		line, err := reader.ReadString('\n')
+		line = strings.TrimSpace(line)
+		if line != "" {
+			switch h := lineHash(line); {
+			case h == hash:
+				return lineCount(line)
+			case h > hash:
+				return 0, nil
+			}
+		}
+		if err == io.EOF {
+			return 0, nil
+		} else if err != nil {
+			return 0, err
+		}
+	}
+}
+
+func lineHash(line string) string {
+	hash, _, _ := strings.Cut(line, ":")
+	return strings.ToUpper(hash)
+}
+
+func lineCount(line string) (int, error) {
+	_, count, found := strings.Cut(line, ":")
+	if !found {
+		return 1, nil
+	}
+	return strconv.Atoi(strings.TrimSpace(count))
+}
@@ -0,0 +1,122 @@
+package breach
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// writeSortedFile writes the hashes of the passwords, sorted, each
+// seen as many times as its index in the returned order plus one.
+func writeSortedFile(t *testing.T, passwords []string, newline string) (string, []string) {
+	t.Helper()
+	sorted := append([]string(nil), passwords...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return SHA1.hash(sorted[i]) < SHA1.hash(sorted[j])
+	})
+
+	lines := make([]string, len(sorted))
+	for i, password := range sorted {
+		lines[i] = fmt.Sprintf("%s:%d", SHA1.hash(password), i+1)
+	}
+	path := filepath.Join(t.TempDir(), "pwned-passwords-sha1.txt")
+	content := strings.Join(lines, newline) + newline
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path, sorted
+}
+
+func TestSortedFileCount(t *testing.T) {
+	passwords := make([]string, 5000)
+	for i := range passwords {
+		passwords[i] = fmt.Sprintf("password-%d", i)
+	}
+
+	for _, newline := range []string{"\n", "\r\n"} {
+		path, sorted := writeSortedFile(t, passwords, newline)
+		checker := &sortedFile{path: path, mode: SHA1}
+
+		tests := []struct {
+			name     string
+			password string
+			want     int
+		}{
+			{"first line", sorted[0], 1},
+			{"second line", sorted[1], 2},
+			{"middle line", sorted[len(sorted)/2], len(sorted)/2 + 1},
+			{"before last line", sorted[len(sorted)-2], len(sorted) - 1},
+			{"last line", sorted[len(sorted)-1], len(sorted)},
+			{"missing hash", "not-in-the-file", 0},
+		}
+		for _, tt := range tests {
+			t.Run(fmt.Sprintf("%s %q", tt.name, newline), func(t *testing.T) {
+				got, err := checker.Count(tt.password)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got != tt.want {
+					t.Errorf("Count(%q) = %d, want %d", tt.password, got, tt.want)
+				}
+			})
+		}
+	}
+}
+
+func TestSortedFileCountEveryLine(t *testing.T) {
+	passwords := make([]string, 300)
+	for i := range passwords {
+		passwords[i] = fmt.Sprintf("p%d", i)
+	}
+	path, sorted := writeSortedFile(t, passwords, "\n")
+	// Drop the trailing newline so that the last line ends the file
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.WriteFile(path, content[:len(content)-1], 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	checker := &sortedFile{path: path, mode: SHA1}
+	for i, password := range sorted {
+		if got, err := checker.Count(password); err != nil || got != i+1 {
+			t.Errorf("Count(%q) = %d, %v, want %d", password, got, err, i+1)
+		}
+	}
+}
+
+func TestRangeDirectoryCount(t *testing.T) {
+	dir := t.TempDir()
+	hash := SHA1.hash("password")
+	err := os.WriteFile(filepath.Join(dir, hash[:prefixLength]+".txt"),
+		[]byte("0018A45C4D1DEF81644B54AB7F969B88D65:1\r\n"+hash[prefixLength:]+":42\r\n"), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checker := &rangeDirectory{path: dir, mode: SHA1}
+	for password, want := range map[string]int{"password": 42, "no range file": 0} {
+		if got, err := checker.Count(password); err != nil || got != want {
+			t.Errorf("Count(%q) = %d, %v, want %d", password, got, err, want)
+		}
+	}
+}
+
+func TestModeHash(t *testing.T) {
+	tests := []struct {
+		mode Mode
+		want string
+	}{
+		{SHA1, "5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8"},
+		{NTLM, "8846F7EAEE8FB117AD06BDD830B7586C"},
+	}
+	for _, tt := range tests {
+		if got := tt.mode.hash("password"); got != tt.want {
+			t.Errorf("%s hash = %s, want %s", tt.mode, got, tt.want)
+		}
+	}
+}
@@ -43,3 +43,12 @@ func Set(key Key, value any) {
 	defer mutex.Unlock()
 	memStore[key] = value
 }
+
+// Delete removes the keys from the cache.
+func Delete(keys ...Key) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	for _, key := range keys {
+		delete(memStore, key)
+	}
+}
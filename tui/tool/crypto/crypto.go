@@ -7,11 +7,13 @@ import (
 	"crypto/rsa"
 	"crypto/sha256"
 	"crypto/x509"
+	"encoding/base64"
 	"encoding/pem"
 	"errors"
 	"io"
 
-	"github.com/zalando/go-keyring"
+	"viscue/tui/tool/keychain"
+
 	"golang.org/x/crypto/hkdf"
 	"golang.org/x/crypto/pbkdf2"
 )
@@ -19,6 +21,11 @@ import (
 const (
 	SaltStorageName      = "Viscue AUC Salt"
 	SecretKeyStorageName = "Viscue Secret Key"
+
+	// AucIterations is the number of PBKDF2 iterations deriving the AUC.
+	AucIterations = 870000
+	// RsaKeyBits is the size of the private key of the account.
+	RsaKeyBits = 3072
 )
 
 func GenerateAccountUnlockKey(password, secretKey, username string) (
@@ -28,7 +35,14 @@ func GenerateAccountUnlockKey(password, secretKey, username string) (
 	if err != nil {
 		return nil, err
 	}
+	return DeriveAccountUnlockKey(password, secretKey, salt, username)
+}
 
+// DeriveAccountUnlockKey computes the AUC from the given salt rather
+// than the one in keyring, as when the username is about to change.
+func DeriveAccountUnlockKey(password, secretKey, salt, username string) (
+	[]byte, error,
+) {
 	// Calculate the derivative of salt using HKDF.
 	kdf := hkdf.New(sha256.New, []byte(salt), []byte(username),
 		[]byte("viscue-client"))
@@ -39,7 +53,7 @@ func GenerateAccountUnlockKey(password, secretKey, username string) (
 	salt = string(saltByte)
 
 	// Create AUC from password and salt.
-	aucByte := pbkdf2.Key([]byte(password), []byte(salt), 870000, 32,
+	aucByte := pbkdf2.Key([]byte(password), []byte(salt), AucIterations, 32,
 		sha256.New)
 
 	// Trim our secret key to match the length of AUC, so we can XOR both.
@@ -53,16 +67,16 @@ func GenerateAccountUnlockKey(password, secretKey, username string) (
 
 // findOrMakeSalt searched salt in keyring. If not found, generate it.
 func findOrMakeSalt(username string) (string, error) {
-	salt, err := keyring.Get(SaltStorageName, username)
+	salt, err := keychain.Get(SaltStorageName, username)
 	if err != nil {
-		if errors.Is(err, keyring.ErrNotFound) {
+		if errors.Is(err, keychain.ErrNotFound) {
 			// Generate salt if previously not found, then save it in keyring.
 			salt, err = GenerateSalt()
 			if err != nil {
 				return "", err
 			}
 
-			err = keyring.Set(SaltStorageName, username, salt)
+			err = keychain.Set(SaltStorageName, username, salt)
 			if err != nil {
 				return "", err
 			}
@@ -75,7 +89,7 @@ func findOrMakeSalt(username string) (string, error) {
 }
 
 func GenerateRsaPrivateKey() (*rsa.PrivateKey, error) {
-	private, err := rsa.GenerateKey(rand.Reader, 3072)
+	private, err := rsa.GenerateKey(rand.Reader, RsaKeyBits)
 	if err != nil {
 		return nil, err
 	}
@@ -83,6 +97,17 @@ func GenerateRsaPrivateKey() (*rsa.PrivateKey, error) {
 	return private, nil
 }
 
+// Fingerprint identifies the public key by the SHA-256 digest of its
+// DER encoding, written the way OpenSSH does.
+func Fingerprint(key *rsa.PublicKey) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(der)
+	return "SHA256:" + base64.RawStdEncoding.EncodeToString(sum[:]), nil
+}
+
 func rsaPrivateToPem(key *rsa.PrivateKey) []byte {
 	return pem.EncodeToMemory(
 		&pem.Block{
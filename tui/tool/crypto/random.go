@@ -2,8 +2,13 @@ package crypto
 
 import (
 	"crypto/rand"
+	"errors"
 	"math/big"
 	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/samber/lo"
 )
 
 const (
@@ -12,9 +17,11 @@ const (
 	numbersCharacters      = "0123456789"
 	specialCharacters      = "~!@#%^&*-_+={}|"
 
+	// MinPasswordLength is the shortest password the generator makes
+	MinPasswordLength = 8
+
 	secretKeyEntropy = upperLettersCharacters + numbersCharacters
 	saltEntropy      = lowerLettersCharacters + upperLettersCharacters + numbersCharacters
-	completeEntropy  = lowerLettersCharacters + upperLettersCharacters + numbersCharacters + specialCharacters
 )
 
 func GenerateSecretKey() (string, error) {
@@ -25,8 +32,75 @@ func GenerateSalt() (string, error) {
 	return generateRandomString(saltEntropy, 32)
 }
 
-func GenerateRandomPassword(length int) (string, error) {
-	return generateRandomString(completeEntropy, length)
+// GeneratePassword generates a password of lower and upper case
+// letters, along with digits and special characters when asked for.
+// Every set of characters it draws from shows up at least once.
+func GeneratePassword(length int, digits, symbols bool) (string, error) {
+	sets := passwordSets(digits, symbols)
+	if length < len(sets) {
+		return "", errors.New("password is too short to hold every set of characters")
+	}
+	entropy := strings.Join(sets, "")
+	for {
+		password, err := generateRandomString(entropy, length)
+		if err != nil {
+			return "", err
+		}
+		if !lo.SomeBy(sets, func(set string) bool {
+			return !strings.ContainsAny(password, set)
+		}) {
+			return password, nil
+		}
+	}
+}
+
+// passwordSets returns the sets of characters passwords are made of.
+func passwordSets(digits, symbols bool) []string {
+	sets := []string{lowerLettersCharacters, upperLettersCharacters}
+	if digits {
+		sets = append(sets, numbersCharacters)
+	}
+	if symbols {
+		sets = append(sets, specialCharacters)
+	}
+	return sets
+}
+
+// IsWeakPassword tells whether the password is too short, or draws
+// from too few classes of characters for its length: lower case,
+// upper case, digits and anything else. Below 12 characters it takes
+// three of them and below 16 two, a longer passphrase being strong
+// whatever it is made of.
+func IsWeakPassword(password string) bool {
+	length := utf8.RuneCountInString(password)
+	switch {
+	case length < MinPasswordLength:
+		return true
+	case length < 12:
+		return characterClasses(password) < 3
+	case length < 16:
+		return characterClasses(password) < 2
+	}
+	return false
+}
+
+// characterClasses counts the classes of characters in the password,
+// letters without case counting as lower case.
+func characterClasses(password string) int {
+	var lower, upper, digit, other bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsLetter(r):
+			lower = true
+		default:
+			other = true
+		}
+	}
+	return lo.Count([]bool{lower, upper, digit, other}, true)
 }
 
 func generateRandomString(entropy string, length int) (string, error) {
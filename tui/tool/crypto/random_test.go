@@ -0,0 +1,67 @@
+package crypto
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIsWeakPassword(t *testing.T) {
+	tests := []struct {
+		password string
+		want     bool
+	}{
+		{"", true},
+		{"Abc1!", true},
+		{"hunter2", true},
+		{"password", true},
+		{"AbcdEfgh", true},
+		{"Password1", false},
+		{"PASSWORD123", true},
+		{"PASSWORD-123", false},
+		{"12345678901234", true},
+		{"Ünïcödé", true},
+		{"Ünïcödé-2024", false},
+		{"ÜNÏCÖDÉÜNÏCÖDÉ", true},
+		{"パスワードパスワード1234", false},
+		{"correct horse battery staple", false},
+		{"abcdefghijklmnop", false},
+	}
+	for _, tt := range tests {
+		if got := IsWeakPassword(tt.password); got != tt.want {
+			t.Errorf("IsWeakPassword(%q) = %v, want %v", tt.password, got, tt.want)
+		}
+	}
+}
+
+func TestGeneratePassword(t *testing.T) {
+	tests := []struct {
+		length          int
+		digits, symbols bool
+	}{
+		{MinPasswordLength, false, false},
+		{MinPasswordLength, true, false},
+		{MinPasswordLength, true, true},
+		{4, true, true},
+		{128, true, true},
+	}
+	for _, tt := range tests {
+		for range 50 {
+			password, err := GeneratePassword(tt.length, tt.digits, tt.symbols)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(password) != tt.length {
+				t.Fatalf("GeneratePassword(%d) made %q", tt.length, password)
+			}
+			for _, set := range passwordSets(tt.digits, tt.symbols) {
+				if !strings.ContainsAny(password, set) {
+					t.Fatalf("%q has none of %q", password, set)
+				}
+			}
+		}
+	}
+
+	if _, err := GeneratePassword(3, true, true); err == nil {
+		t.Error("GeneratePassword(3) made a password without every set")
+	}
+}
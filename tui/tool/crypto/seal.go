@@ -0,0 +1,75 @@
+package crypto
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"errors"
+)
+
+// Seal encrypts plaintext of any length for the owner of the
+// public key. A random AES-256-GCM key encrypts the plaintext and
+// is itself wrapped with RSA-OAEP. The label is bound to both, so
+// the ciphertext can only be opened with the same label.
+//
+// The output is laid out as: wrapped key | nonce | ciphertext.
+func Seal(pub *rsa.PublicKey, plaintext, label []byte) ([]byte, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+
+	wrapped, err := rsa.EncryptOAEP(sha256.New(), rand.Reader, pub, key, label)
+	if err != nil {
+		return nil, err
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	sealed := append(wrapped, nonce...)
+	return gcm.Seal(sealed, nonce, plaintext, label), nil
+}
+
+// Open reverses Seal.
+func Open(priv *rsa.PrivateKey, sealed, label []byte) ([]byte, error) {
+	keySize := priv.Size()
+	if len(sealed) < keySize {
+		return nil, errors.New("sealed data is too short")
+	}
+
+	key, err := rsa.DecryptOAEP(sha256.New(), rand.Reader, priv,
+		sealed[:keySize], label)
+	if err != nil {
+		return nil, err
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	sealed = sealed[keySize:]
+	if len(sealed) < gcm.NonceSize() {
+		return nil, errors.New("sealed data is too short")
+	}
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, ciphertext, label)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
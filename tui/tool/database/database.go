@@ -40,6 +40,19 @@ func (*sqlHook) After(
 	return ctx, nil
 }
 
+// Path returns where the vault is stored, in the working directory
+// when local_db is set and in the home directory otherwise.
+func Path() (string, error) {
+	if _, ok := os.LookupEnv("local_db"); ok {
+		return "sqlite.db", nil
+	}
+	homedir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch home directory")
+	}
+	return filepath.Join(homedir, ".viscue.sqlite"), nil
+}
+
 //go:embed migrations
 var migrations embed.FS
 
@@ -47,17 +60,18 @@ func New() (*sqlx.DB, error) {
 	sql.Register("sqlite3_with_sqlHook",
 		sqlhooks.Wrap(&sqlite3.SQLiteDriver{}, &sqlHook{}))
 
-	dbpath := "sqlite.db"
-	_, ok := os.LookupEnv("local_db")
-	if !ok {
-		homedir, err := os.UserHomeDir()
-		if err != nil {
-			return nil, fmt.Errorf("failed to fetch home directory")
-		}
-		dbpath = filepath.Join(homedir, ".viscue.sqlite")
+	dbpath, err := Path()
+	if err != nil {
+		return nil, err
 	}
 
-	db, err := sqlx.Connect("sqlite3_with_sqlHook", dbpath)
+	// Foreign keys are enforced, which SQLite leaves off by default, so
+	// that deleting a password also deletes its history, fields, URLs,
+	// attachments and tags, and deleting a category moves what is left
+	// within it to Uncategorized rather than pointing to nothing. Rows
+	// referencing a missing one can no longer be written, hence undo and
+	// the account deletion defer the checks until they commit.
+	db, err := sqlx.Connect("sqlite3_with_sqlHook", dbpath+"?_foreign_keys=on")
 	if err != nil {
 		err = fmt.Errorf("failed connecting to sqlite3: %s", err.Error())
 	}
@@ -0,0 +1,97 @@
+package database
+
+import (
+	"database/sql"
+	"os"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+)
+
+func newTestDB(t *testing.T) *sqlx.DB {
+	t.Helper()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+	t.Setenv("local_db", "1")
+
+	db, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	return db
+}
+
+func mustExec(t *testing.T, db *sqlx.DB, query string, args ...any) {
+	t.Helper()
+	if _, err := db.Exec(query, args...); err != nil {
+		t.Fatalf("%s: %v", query, err)
+	}
+}
+
+func count(t *testing.T, db *sqlx.DB, query string, args ...any) int {
+	t.Helper()
+	var n int
+	if err := db.Get(&n, query, args...); err != nil {
+		t.Fatalf("%s: %v", query, err)
+	}
+	return n
+}
+
+// The delete paths rely on the foreign keys being enforced
+// by every connection to the vault.
+func TestForeignKeys(t *testing.T) {
+	db := newTestDB(t)
+	mustExec(t, db, "INSERT INTO categories (id, name) VALUES (1, 'work'), (2, 'sub')")
+	mustExec(t, db, "UPDATE categories SET parent_id = 1 WHERE id = 2")
+	mustExec(t, db, `INSERT INTO passwords (id, category_id, name, password)
+		VALUES (1, 1, 'github', 'secret')`)
+	mustExec(t, db, "INSERT INTO password_history (password_id, password) VALUES (1, 'old')")
+	mustExec(t, db, "INSERT INTO password_urls (password_id, url) VALUES (1, 'https://github.com')")
+	mustExec(t, db, "INSERT INTO tags (id, name) VALUES (1, 'dev')")
+	mustExec(t, db, "INSERT INTO password_tags (password_id, tag_id) VALUES (1, 1)")
+
+	t.Run("category delete", func(t *testing.T) {
+		mustExec(t, db, "DELETE FROM categories WHERE id = 1")
+		var category sql.NullInt64
+		if err := db.Get(&category, "SELECT category_id FROM passwords WHERE id = 1"); err != nil {
+			t.Fatal(err)
+		}
+		if category.Valid {
+			t.Errorf("password still in deleted category %d", category.Int64)
+		}
+		if n := count(t, db, "SELECT COUNT(*) FROM categories WHERE id = 2 AND parent_id IS NULL"); n != 1 {
+			t.Error("subcategory still points to its deleted parent")
+		}
+	})
+
+	t.Run("missing reference", func(t *testing.T) {
+		_, err := db.Exec("UPDATE passwords SET category_id = 42 WHERE id = 1")
+		if err == nil {
+			t.Error("password moved to a missing category")
+		}
+		_, err = db.Exec("INSERT INTO password_history (password_id, password) VALUES (42, 'x')")
+		if err == nil {
+			t.Error("history written for a missing password")
+		}
+	})
+
+	t.Run("password delete", func(t *testing.T) {
+		mustExec(t, db, "DELETE FROM passwords WHERE id = 1")
+		for _, table := range []string{"password_history", "password_urls", "password_tags"} {
+			if n := count(t, db, "SELECT COUNT(*) FROM "+table+" WHERE password_id = 1"); n != 0 {
+				t.Errorf("%s kept %d rows of the deleted password", table, n)
+			}
+		}
+		// Orphan tags are pruned by the application, not the schema
+		if n := count(t, db, "SELECT COUNT(*) FROM tags"); n != 1 {
+			t.Errorf("tags = %d, want 1", n)
+		}
+	})
+}
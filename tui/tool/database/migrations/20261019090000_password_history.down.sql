@@ -0,0 +1 @@
+DROP TABLE password_history;
\ No newline at end of file
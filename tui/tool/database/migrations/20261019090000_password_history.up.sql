@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS password_history(
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    password_id INTEGER NOT NULL,
+    password VARCHAR NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (password_id) REFERENCES passwords(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_history_per_password ON password_history (password_id, created_at);
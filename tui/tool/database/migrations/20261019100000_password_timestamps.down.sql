@@ -0,0 +1,3 @@
+ALTER TABLE passwords DROP COLUMN last_used_at;
+ALTER TABLE passwords DROP COLUMN updated_at;
+ALTER TABLE passwords DROP COLUMN created_at;
@@ -0,0 +1,5 @@
+ALTER TABLE passwords ADD COLUMN created_at DATETIME;
+ALTER TABLE passwords ADD COLUMN updated_at DATETIME;
+ALTER TABLE passwords ADD COLUMN last_used_at DATETIME;
+
+UPDATE passwords SET created_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP;
@@ -0,0 +1 @@
+ALTER TABLE passwords DROP COLUMN otp;
@@ -0,0 +1 @@
+ALTER TABLE passwords ADD COLUMN otp VARCHAR NOT NULL DEFAULT '';
@@ -0,0 +1,2 @@
+DROP TABLE password_fields;
+ALTER TABLE passwords DROP COLUMN notes;
@@ -0,0 +1,3 @@
+DROP INDEX idx_passwords_per_type;
+ALTER TABLE passwords DROP COLUMN data;
+ALTER TABLE passwords DROP COLUMN type;
@@ -0,0 +1,4 @@
+ALTER TABLE passwords ADD COLUMN type VARCHAR NOT NULL DEFAULT 'login';
+ALTER TABLE passwords ADD COLUMN data TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX idx_passwords_per_type ON passwords (type);
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS password_urls(
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    password_id INTEGER NOT NULL,
+    url TEXT NOT NULL,
+    match_rule VARCHAR NOT NULL DEFAULT 'domain',
+    position INTEGER NOT NULL DEFAULT 0,
+
+    FOREIGN KEY (password_id) REFERENCES passwords(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_urls_per_password ON password_urls (password_id, position);
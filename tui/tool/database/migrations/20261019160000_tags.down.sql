@@ -0,0 +1,2 @@
+DROP TABLE password_tags;
+DROP TABLE tags;
@@ -0,0 +1,5 @@
+-- Not declared as a foreign key, since SQLite cannot drop such
+-- a column. Subcategories are moved up when a category is deleted.
+ALTER TABLE categories ADD COLUMN parent_id INTEGER;
+
+CREATE INDEX idx_categories_per_parent ON categories (parent_id);
@@ -0,0 +1 @@
+ALTER TABLE passwords ADD COLUMN favorite BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,8 @@
+-- The trash is emptied, its items could clash with the restored index
+DELETE FROM passwords WHERE deleted_at IS NOT NULL;
+
+DROP INDEX idx_name_per_category;
+CREATE UNIQUE INDEX idx_name_per_category ON passwords (LOWER(name), category_id);
+
+DROP INDEX idx_passwords_deleted_at;
+ALTER TABLE passwords DROP COLUMN deleted_at;
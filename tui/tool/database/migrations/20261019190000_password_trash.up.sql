@@ -0,0 +1,8 @@
+ALTER TABLE passwords ADD COLUMN deleted_at DATETIME;
+
+CREATE INDEX idx_passwords_deleted_at ON passwords (deleted_at);
+
+-- Items in the trash no longer hold their name
+DROP INDEX idx_name_per_category;
+CREATE UNIQUE INDEX idx_name_per_category ON passwords (LOWER(name), category_id)
+    WHERE deleted_at IS NULL;
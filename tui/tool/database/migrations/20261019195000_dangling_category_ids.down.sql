@@ -0,0 +1,2 @@
+-- The categories the passwords pointed to are gone for good.
+SELECT 1;
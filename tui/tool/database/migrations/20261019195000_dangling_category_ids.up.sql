@@ -0,0 +1,6 @@
+-- Deleting a category used to leave its passwords pointing to it.
+-- Foreign keys are enforced now, so such passwords could no longer be
+-- saved: they go to Uncategorized, as deleting a category does now.
+UPDATE passwords SET category_id = NULL
+WHERE category_id IS NOT NULL
+    AND category_id NOT IN (SELECT id FROM categories);
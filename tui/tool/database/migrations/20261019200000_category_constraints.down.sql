@@ -0,0 +1,29 @@
+-- Names are unique globally again, hence fails when two categories
+-- share a name under different parents.
+PRAGMA defer_foreign_keys = ON;
+
+CREATE TEMP TABLE old_categories AS SELECT id, name, parent_id FROM categories;
+CREATE TEMP TABLE old_password_categories AS
+    SELECT id, category_id FROM passwords WHERE category_id IS NOT NULL;
+
+DROP TABLE categories;
+
+CREATE TABLE categories(
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR NOT NULL UNIQUE,
+    parent_id INTEGER
+);
+
+INSERT INTO categories (id, name, parent_id)
+SELECT id, name, parent_id FROM old_categories;
+
+UPDATE passwords SET category_id = (
+    SELECT category_id FROM old_password_categories
+    WHERE old_password_categories.id = passwords.id
+)
+WHERE id IN (SELECT id FROM old_password_categories);
+
+DROP TABLE old_categories;
+DROP TABLE old_password_categories;
+
+CREATE INDEX idx_categories_per_parent ON categories (parent_id);
@@ -0,0 +1,38 @@
+-- Rebuilds the categories so that parent_id is a real foreign key and
+-- names are unique within their parent rather than globally. Foreign
+-- keys are enforced, hence dropping the table sets the category of
+-- every password to NULL: it is put back once the table is rebuilt.
+PRAGMA defer_foreign_keys = ON;
+
+CREATE TEMP TABLE old_categories AS SELECT id, name, parent_id FROM categories;
+CREATE TEMP TABLE old_password_categories AS
+    SELECT id, category_id FROM passwords WHERE category_id IS NOT NULL;
+
+DROP TABLE categories;
+
+CREATE TABLE categories(
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR NOT NULL,
+    parent_id INTEGER,
+
+    FOREIGN KEY (parent_id) REFERENCES categories(id) ON DELETE SET NULL
+);
+
+-- Subcategories pointing to a missing parent are moved to the top level
+INSERT INTO categories (id, name, parent_id)
+SELECT id, name,
+    CASE WHEN parent_id IN (SELECT id FROM old_categories) THEN parent_id END
+FROM old_categories;
+
+UPDATE passwords SET category_id = (
+    SELECT category_id FROM old_password_categories
+    WHERE old_password_categories.id = passwords.id
+)
+WHERE id IN (SELECT id FROM old_password_categories);
+
+DROP TABLE old_categories;
+DROP TABLE old_password_categories;
+
+-- Top level categories have no parent, and NULLs are never equal
+CREATE UNIQUE INDEX idx_categories_name_per_parent
+    ON categories (IFNULL(parent_id, 0), name);
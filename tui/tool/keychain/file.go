@@ -0,0 +1,107 @@
+package keychain
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+)
+
+// FileName is the name of the file backend within the data directory.
+const FileName = "keyring.json"
+
+// filePath returns the path of the file backend, kept in
+// `$XDG_DATA_HOME/viscue` or `~/.local/share/viscue` by default,
+// apart from the database so that a copy of one is not enough.
+func filePath() (string, error) {
+	if dir, ok := os.LookupEnv("XDG_DATA_HOME"); ok && dir != "" {
+		return filepath.Join(dir, "viscue", FileName), nil
+	}
+	homedir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homedir, ".local", "share", "viscue", FileName), nil
+}
+
+// fileBackend keeps the secrets in a JSON file, by service then user.
+type fileBackend struct {
+	path string
+}
+
+func (backend fileBackend) read() (map[string]map[string]string, error) {
+	secrets := make(map[string]map[string]string)
+	data, err := os.ReadFile(backend.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return secrets, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(data, &secrets); err != nil {
+		return nil, err
+	}
+	return secrets, nil
+}
+
+// write replaces the file at once, so that it is never left half written.
+func (backend fileBackend) write(secrets map[string]map[string]string) error {
+	data, err := json.Marshal(secrets)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(backend.path), 0o700); err != nil {
+		return err
+	}
+	file, err := os.CreateTemp(filepath.Dir(backend.path), FileName+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+	if _, err = file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+	if err = file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), backend.path)
+}
+
+func (backend fileBackend) Get(service, user string) (string, error) {
+	secrets, err := backend.read()
+	if err != nil {
+		return "", err
+	}
+	secret, ok := secrets[service][user]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return secret, nil
+}
+
+func (backend fileBackend) Set(service, user, secret string) error {
+	secrets, err := backend.read()
+	if err != nil {
+		return err
+	}
+	if secrets[service] == nil {
+		secrets[service] = make(map[string]string)
+	}
+	secrets[service][user] = secret
+	return backend.write(secrets)
+}
+
+func (backend fileBackend) Delete(service, user string) error {
+	secrets, err := backend.read()
+	if err != nil {
+		return err
+	}
+	if _, ok := secrets[service][user]; !ok {
+		return ErrNotFound
+	}
+	delete(secrets[service], user)
+	if len(secrets[service]) == 0 {
+		delete(secrets, service)
+	}
+	return backend.write(secrets)
+}
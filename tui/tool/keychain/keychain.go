@@ -0,0 +1,148 @@
+// Package keychain keeps the secrets of the account, the secret key
+// and the salt, outside the database. They live either in the keyring
+// of the system or, where none is available, in a file only readable
+// by the user.
+package keychain
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/zalando/go-keyring"
+)
+
+const (
+	BackendSystem = "system"
+	BackendFile   = "file"
+)
+
+// ErrNotFound is returned when the secret is not in the keychain.
+var ErrNotFound = keyring.ErrNotFound
+
+// Backend stores secrets by service and user.
+type Backend interface {
+	Get(service, user string) (string, error)
+	Set(service, user, secret string) error
+	Delete(service, user string) error
+}
+
+// Backends returns the names of the available backends.
+func Backends() []string {
+	return []string{BackendSystem, BackendFile}
+}
+
+// Open returns the backend of the given name.
+func Open(name string) (Backend, error) {
+	switch name {
+	case BackendSystem:
+		return systemBackend{}, nil
+	case BackendFile:
+		path, err := filePath()
+		if err != nil {
+			return nil, err
+		}
+		return fileBackend{path: path}, nil
+	}
+	return nil, fmt.Errorf("unknown keyring backend %q", name)
+}
+
+var current Backend = systemBackend{}
+
+// Use makes the backend of the given name the one the
+// secrets are read from and written to.
+func Use(name string) error {
+	backend, err := Open(name)
+	if err != nil {
+		return err
+	}
+	current = backend
+	return nil
+}
+
+func Get(service, user string) (string, error) {
+	return current.Get(service, user)
+}
+
+func Set(service, user, secret string) error {
+	return current.Set(service, user, secret)
+}
+
+func Delete(service, user string) error {
+	return current.Delete(service, user)
+}
+
+// Move copies the secrets of the services from a backend to
+// another, then deletes them from the first one. Secrets missing
+// from it are skipped. Nothing is deleted when a copy fails.
+func Move(from, to Backend, user string, services ...string) error {
+	return transfer(from, to, user, user, services)
+}
+
+// Rename moves the secrets of the services from a user to another
+// within the current backend, the same way Move does.
+func Rename(from, to string, services ...string) error {
+	if from == to {
+		return nil
+	}
+	return transfer(current, current, from, to, services)
+}
+
+// Wipe deletes the secrets of the services for the user from the
+// current backend, skipping the missing ones.
+func Wipe(user string, services ...string) error {
+	var errs []error
+	for _, service := range services {
+		err := current.Delete(service, user)
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			errs = append(errs, fmt.Errorf("failed deleting %s: %w", service, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func transfer(from, to Backend, fromUser, toUser string, services []string) error {
+	var moved []string
+	for _, service := range services {
+		secret, err := from.Get(service, fromUser)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		} else if err != nil {
+			return fmt.Errorf("failed reading %s: %w", service, err)
+		}
+		if err = to.Set(service, toUser, secret); err != nil {
+			for _, service := range moved {
+				_ = to.Delete(service, toUser)
+			}
+			return fmt.Errorf("failed writing %s: %w", service, err)
+		}
+		moved = append(moved, service)
+	}
+
+	for _, service := range moved {
+		if err := from.Delete(service, fromUser); err != nil &&
+			!errors.Is(err, ErrNotFound) {
+			return fmt.Errorf("failed deleting %s: %w", service, err)
+		}
+	}
+	return nil
+}
+
+// Valid reports whether the name is the one of a backend.
+func Valid(name string) bool {
+	return slices.Contains(Backends(), name)
+}
+
+type systemBackend struct{}
+
+func (systemBackend) Get(service, user string) (string, error) {
+	return keyring.Get(service, user)
+}
+
+func (systemBackend) Set(service, user, secret string) error {
+	return keyring.Set(service, user, secret)
+}
+
+func (systemBackend) Delete(service, user string) error {
+	return keyring.Delete(service, user)
+}
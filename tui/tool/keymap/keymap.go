@@ -0,0 +1,178 @@
+// Package keymap lets the user remap the keys of the views from
+// a YAML file in the configuration directory, e.g.
+//
+//	shelf:
+//	  search: /
+//	  switch: [ctrl+w, left]
+//	sidebar:
+//	  switch: [ctrl+w, right]
+//
+// Every view is a section, every action of it a key, and its value
+// the keys triggering the action, an empty list disabling it.
+//
+// The tables of the panels (detail, history, breach, notifications,
+// settings) and the lists they embed keep fixed moves, j/↓/tab down
+// and k/↑/shift+tab up, handled by the components themselves, and
+// ctrl+c always quits.
+package keymap
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"gopkg.in/yaml.v3"
+)
+
+// FileName is the name of the keymap file within the
+// configuration directory.
+const FileName = "keymap.yaml"
+
+// Keys are the keys bound to an action, written either
+// as a single key or as a list of them.
+type Keys []string
+
+func (keys *Keys) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*keys = Keys{node.Value}
+		return nil
+	}
+	var list []string
+	if err := node.Decode(&list); err != nil {
+		return err
+	}
+	*keys = list
+	return nil
+}
+
+// Config maps the name of a section to its remapped actions.
+type Config map[string]map[string]Keys
+
+// Section holds the bindings of a view by action name.
+type Section map[string]*key.Binding
+
+// Dir returns the configuration directory of viscue,
+// `$XDG_CONFIG_HOME/viscue` or `~/.config/viscue` by default.
+func Dir() (string, error) {
+	if dir, ok := os.LookupEnv("XDG_CONFIG_HOME"); ok && dir != "" {
+		return filepath.Join(dir, "viscue"), nil
+	}
+	homedir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homedir, ".config", "viscue"), nil
+}
+
+// Load reads the keymap file, no file meaning no remapped key.
+func Load() (Config, error) {
+	dir, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(filepath.Join(dir, FileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return Config{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var config Config
+	if err = yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("%s: %w", FileName, err)
+	}
+	return config, nil
+}
+
+// Check fails on a section that is not among the known ones,
+// likely a typo that would otherwise be silently ignored.
+func (config Config) Check(sections ...string) error {
+	for name := range config {
+		if !slices.Contains(sections, name) {
+			return fmt.Errorf("unknown section %q", name)
+		}
+	}
+	return nil
+}
+
+// Apply rebinds the actions of the section remapped in the config,
+// along with their help, then makes sure that no key triggers two
+// actions of the section. Nothing is rebound when it fails.
+func (config Config) Apply(name string, section Section) error {
+	remapped := config[name]
+	keys := make(map[string][]string, len(section))
+	for action, binding := range section {
+		keys[action] = binding.Keys()
+		if !binding.Enabled() {
+			keys[action] = nil
+		}
+	}
+	for action, bound := range remapped {
+		if _, ok := section[action]; !ok {
+			return fmt.Errorf("%s: unknown action %q", name, action)
+		}
+		keys[action] = bound
+	}
+	if err := checkConflicts(name, keys); err != nil {
+		return err
+	}
+
+	for action, bound := range remapped {
+		binding := section[action]
+		if len(bound) == 0 {
+			binding.SetEnabled(false)
+			continue
+		}
+		binding.SetKeys(bound...)
+		binding.SetHelp(label(bound), binding.Help().Desc)
+		binding.SetEnabled(true)
+	}
+	return nil
+}
+
+// checkConflicts fails when a key is bound to several actions.
+func checkConflicts(name string, keys map[string][]string) error {
+	actions := make([]string, 0, len(keys))
+	for action := range keys {
+		actions = append(actions, action)
+	}
+	sort.Strings(actions)
+
+	owners := map[string]string{}
+	for _, action := range actions {
+		for _, k := range keys[action] {
+			if owner, ok := owners[k]; ok && owner != action {
+				return fmt.Errorf("%s: %q is bound to both %s and %s",
+					name, k, owner, action)
+			}
+			owners[k] = action
+		}
+	}
+	return nil
+}
+
+// label writes the keys the way the help shows them.
+func label(keys []string) string {
+	labels := make([]string, len(keys))
+	for i, k := range keys {
+		switch k {
+		case " ":
+			labels[i] = "space"
+		case "up":
+			labels[i] = "↑"
+		case "down":
+			labels[i] = "↓"
+		case "left":
+			labels[i] = "←"
+		case "right":
+			labels[i] = "→"
+		default:
+			labels[i] = k
+		}
+	}
+	return strings.Join(labels, "/")
+}
@@ -0,0 +1,254 @@
+// Package otp implements HOTP (RFC 4226) and TOTP (RFC 6238)
+// one-time passwords, along with parsing of `otpauth://` URIs
+// and bare base32 secrets.
+package otp
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Type string
+
+const (
+	HOTP Type = "hotp"
+	TOTP Type = "totp"
+)
+
+type Algorithm string
+
+const (
+	SHA1   Algorithm = "SHA1"
+	SHA256 Algorithm = "SHA256"
+	SHA512 Algorithm = "SHA512"
+)
+
+const (
+	DefaultDigits = 6
+	DefaultPeriod = 30
+)
+
+var (
+	ErrInvalidURI       = errors.New("invalid otpauth URI")
+	ErrInvalidSecret    = errors.New("invalid base32 secret")
+	ErrInvalidAlgorithm = errors.New("unsupported OTP algorithm")
+	ErrInvalidDigits    = errors.New("OTP digits must be between 6 and 8")
+	ErrInvalidPeriod    = errors.New("OTP period must be positive")
+)
+
+// Key holds everything needed to generate one-time passwords.
+type Key struct {
+	Type      Type
+	Secret    []byte
+	Algorithm Algorithm
+	Digits    int
+	Period    int    // TOTP only, in seconds
+	Counter   uint64 // HOTP only
+	Issuer    string
+	Account   string
+}
+
+// Parse reads either an `otpauth://` URI or a bare base32 secret,
+// in which case a default TOTP key is assumed.
+func Parse(value string) (Key, error) {
+	value = strings.TrimSpace(value)
+	if strings.HasPrefix(strings.ToLower(value), "otpauth://") {
+		return parseURI(value)
+	}
+
+	secret, err := decodeSecret(value)
+	if err != nil {
+		return Key{}, err
+	}
+	return Key{
+		Type:      TOTP,
+		Secret:    secret,
+		Algorithm: SHA1,
+		Digits:    DefaultDigits,
+		Period:    DefaultPeriod,
+	}, nil
+}
+
+func parseURI(value string) (Key, error) {
+	uri, err := url.Parse(value)
+	if err != nil {
+		return Key{}, ErrInvalidURI
+	}
+
+	key := Key{
+		Type:      Type(strings.ToLower(uri.Host)),
+		Algorithm: SHA1,
+		Digits:    DefaultDigits,
+		Period:    DefaultPeriod,
+	}
+	if key.Type != HOTP && key.Type != TOTP {
+		return Key{}, ErrInvalidURI
+	}
+
+	// The label is either `account` or `issuer:account`.
+	label := strings.TrimPrefix(uri.Path, "/")
+	if issuer, account, found := strings.Cut(label, ":"); found {
+		key.Issuer = strings.TrimSpace(issuer)
+		key.Account = strings.TrimSpace(account)
+	} else {
+		key.Account = strings.TrimSpace(label)
+	}
+
+	query := uri.Query()
+	if key.Secret, err = decodeSecret(query.Get("secret")); err != nil {
+		return Key{}, err
+	}
+	if issuer := query.Get("issuer"); issuer != "" {
+		key.Issuer = issuer
+	}
+	if algorithm := query.Get("algorithm"); algorithm != "" {
+		key.Algorithm = Algorithm(strings.ToUpper(algorithm))
+	}
+	if digits := query.Get("digits"); digits != "" {
+		if key.Digits, err = strconv.Atoi(digits); err != nil {
+			return Key{}, ErrInvalidDigits
+		}
+	}
+	if period := query.Get("period"); period != "" {
+		if key.Period, err = strconv.Atoi(period); err != nil {
+			return Key{}, ErrInvalidPeriod
+		}
+	}
+	if counter := query.Get("counter"); counter != "" {
+		if key.Counter, err = strconv.ParseUint(counter, 10, 64); err != nil {
+			return Key{}, ErrInvalidURI
+		}
+	}
+
+	return key, key.validate()
+}
+
+func (key Key) validate() error {
+	switch {
+	case key.Algorithm != SHA1 && key.Algorithm != SHA256 &&
+		key.Algorithm != SHA512:
+		return ErrInvalidAlgorithm
+	case key.Digits < 6 || key.Digits > 8:
+		return ErrInvalidDigits
+	case key.Type == TOTP && key.Period <= 0:
+		return ErrInvalidPeriod
+	}
+	return nil
+}
+
+// decodeSecret decodes a base32 secret, forgiving spaces,
+// lower case letters and missing padding.
+func decodeSecret(secret string) ([]byte, error) {
+	secret = strings.ToUpper(strings.ReplaceAll(secret, " ", ""))
+	secret = strings.TrimRight(secret, "=")
+	if secret == "" {
+		return nil, ErrInvalidSecret
+	}
+	decoded, err := base32.StdEncoding.WithPadding(base32.NoPadding).
+		DecodeString(secret)
+	if err != nil {
+		return nil, ErrInvalidSecret
+	}
+	return decoded, nil
+}
+
+// String encodes the key back into an `otpauth://` URI.
+func (key Key) String() string {
+	label := key.Account
+	if key.Issuer != "" {
+		label = key.Issuer + ":" + key.Account
+	}
+
+	query := url.Values{}
+	query.Set("secret", base32.StdEncoding.WithPadding(base32.NoPadding).
+		EncodeToString(key.Secret))
+	if key.Issuer != "" {
+		query.Set("issuer", key.Issuer)
+	}
+	query.Set("algorithm", string(key.Algorithm))
+	query.Set("digits", strconv.Itoa(key.Digits))
+	if key.Type == HOTP {
+		query.Set("counter", strconv.FormatUint(key.Counter, 10))
+	} else {
+		query.Set("period", strconv.Itoa(key.Period))
+	}
+
+	uri := url.URL{
+		Scheme:   "otpauth",
+		Host:     string(key.Type),
+		Path:     "/" + label,
+		RawQuery: query.Encode(),
+	}
+	return uri.String()
+}
+
+// Code returns the one-time password of the key at the given
+// time. HOTP keys ignore the time and use their counter.
+func (key Key) Code(t time.Time) string {
+	if key.Type == HOTP {
+		return GenerateHOTP(key.Secret, key.Counter, key.Digits, key.Algorithm)
+	}
+	return GenerateTOTP(key.Secret, t, key.Period, key.Digits, key.Algorithm)
+}
+
+// Remaining returns how long the TOTP code at the given time
+// stays valid. It is always zero for HOTP keys.
+func (key Key) Remaining(t time.Time) time.Duration {
+	if key.Type == HOTP {
+		return 0
+	}
+	period := int64(key.Period)
+	return time.Duration(period-t.Unix()%period) * time.Second
+}
+
+// GenerateHOTP computes the RFC 4226 one-time password.
+func GenerateHOTP(secret []byte, counter uint64, digits int,
+	algorithm Algorithm,
+) string {
+	var message [8]byte
+	binary.BigEndian.PutUint64(message[:], counter)
+
+	mac := hmac.New(algorithm.hash(), secret)
+	mac.Write(message[:])
+	sum := mac.Sum(nil)
+
+	// Dynamic truncation as described in RFC 4226 section 5.3.
+	offset := sum[len(sum)-1] & 0x0f
+	binCode := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	modulo := uint32(1)
+	for i := 0; i < digits; i++ {
+		modulo *= 10
+	}
+	return fmt.Sprintf("%0*d", digits, binCode%modulo)
+}
+
+// GenerateTOTP computes the RFC 6238 one-time password.
+func GenerateTOTP(secret []byte, t time.Time, period, digits int,
+	algorithm Algorithm,
+) string {
+	counter := uint64(t.Unix() / int64(period))
+	return GenerateHOTP(secret, counter, digits, algorithm)
+}
+
+func (algorithm Algorithm) hash() func() hash.Hash {
+	switch algorithm {
+	case SHA256:
+		return sha256.New
+	case SHA512:
+		return sha512.New
+	default:
+		return sha1.New
+	}
+}
@@ -0,0 +1,125 @@
+package otp
+
+import (
+	"encoding/base32"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// RFC 4226 appendix D
+func TestGenerateHOTP(t *testing.T) {
+	secret := []byte("12345678901234567890")
+	want := []string{
+		"755224", "287082", "359152", "969429", "338314",
+		"254676", "287922", "162583", "399871", "520489",
+	}
+	for counter, code := range want {
+		if got := GenerateHOTP(secret, uint64(counter), 6, SHA1); got != code {
+			t.Errorf("GenerateHOTP(counter %d) = %s, want %s", counter, got, code)
+		}
+	}
+}
+
+// RFC 6238 appendix B
+func TestGenerateTOTP(t *testing.T) {
+	secrets := map[Algorithm][]byte{
+		SHA1:   []byte("12345678901234567890"),
+		SHA256: []byte("12345678901234567890123456789012"),
+		SHA512: []byte(strings.Repeat("1234567890", 6) + "1234"),
+	}
+	tests := []struct {
+		unix int64
+		want map[Algorithm]string
+	}{
+		{59, map[Algorithm]string{SHA1: "94287082", SHA256: "46119246", SHA512: "90693936"}},
+		{1111111109, map[Algorithm]string{SHA1: "07081804", SHA256: "68084774", SHA512: "25091201"}},
+		{1111111111, map[Algorithm]string{SHA1: "14050471", SHA256: "67062674", SHA512: "99943326"}},
+		{1234567890, map[Algorithm]string{SHA1: "89005924", SHA256: "91819424", SHA512: "93441116"}},
+		{2000000000, map[Algorithm]string{SHA1: "69279037", SHA256: "90698825", SHA512: "38618901"}},
+		{20000000000, map[Algorithm]string{SHA1: "65353130", SHA256: "77737706", SHA512: "47863826"}},
+	}
+	for _, tt := range tests {
+		for algorithm, want := range tt.want {
+			got := GenerateTOTP(secrets[algorithm], time.Unix(tt.unix, 0), 30, 8, algorithm)
+			if got != want {
+				t.Errorf("GenerateTOTP(%d, %s) = %s, want %s", tt.unix, algorithm, got, want)
+			}
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	secret := []byte("12345678901234567890")
+	encoded := base32.StdEncoding.EncodeToString(secret)
+
+	tests := []struct {
+		name  string
+		input string
+		want  Key
+		err   error
+	}{
+		{"bare secret", strings.ToLower(encoded[:8]) + " " + encoded[8:], Key{
+			Type: TOTP, Secret: secret, Algorithm: SHA1,
+			Digits: DefaultDigits, Period: DefaultPeriod,
+		}, nil},
+		{"totp uri", "otpauth://totp/ACME:alice@example.com?secret=" + encoded +
+			"&issuer=ACME&algorithm=sha256&digits=8&period=60", Key{
+			Type: TOTP, Secret: secret, Algorithm: SHA256, Digits: 8, Period: 60,
+			Issuer: "ACME", Account: "alice@example.com",
+		}, nil},
+		{"hotp uri", "otpauth://hotp/alice?secret=" + encoded + "&counter=7", Key{
+			Type: HOTP, Secret: secret, Algorithm: SHA1, Digits: DefaultDigits,
+			Period: DefaultPeriod, Counter: 7, Account: "alice",
+		}, nil},
+		{"empty", "", Key{}, ErrInvalidSecret},
+		{"not base32", "not a secret!", Key{}, ErrInvalidSecret},
+		{"unknown type", "otpauth://motp/alice?secret=" + encoded, Key{}, ErrInvalidURI},
+		{"unknown algorithm", "otpauth://totp/a?secret=" + encoded + "&algorithm=MD5",
+			Key{}, ErrInvalidAlgorithm},
+		{"too many digits", "otpauth://totp/a?secret=" + encoded + "&digits=9",
+			Key{}, ErrInvalidDigits},
+		{"zero period", "otpauth://totp/a?secret=" + encoded + "&period=0",
+			Key{}, ErrInvalidPeriod},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.input)
+			if err != tt.err {
+				t.Fatalf("Parse() error = %v, want %v", err, tt.err)
+			}
+			if err == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKeyString(t *testing.T) {
+	key := Key{
+		Type: HOTP, Secret: []byte("12345678901234567890"), Algorithm: SHA512,
+		Digits: 7, Counter: 42, Issuer: "ACME", Account: "alice",
+	}
+	parsed, err := Parse(key.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed.Period = key.Period
+	if !reflect.DeepEqual(parsed, key) {
+		t.Errorf("Parse(String()) = %+v, want %+v", parsed, key)
+	}
+}
+
+func TestRemaining(t *testing.T) {
+	key := Key{Type: TOTP, Period: 30}
+	if got := key.Remaining(time.Unix(59, 0)); got != time.Second {
+		t.Errorf("Remaining(59) = %s, want 1s", got)
+	}
+	if got := key.Remaining(time.Unix(60, 0)); got != 30*time.Second {
+		t.Errorf("Remaining(60) = %s, want 30s", got)
+	}
+	if got := (Key{Type: HOTP}).Remaining(time.Unix(59, 0)); got != 0 {
+		t.Errorf("Remaining of HOTP = %s, want 0", got)
+	}
+}
@@ -0,0 +1,90 @@
+package query
+
+import (
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/sahilm/fuzzy"
+)
+
+// Document is what a query is matched against: the name, fuzzy
+// matched and highlighted, and the values of every other field.
+type Document struct {
+	Name   string
+	Fields map[Field][]string
+}
+
+// Match tells whether every term of the query matches the document,
+// along with the indexes of the runes of the name that were matched,
+// in increasing order. An empty query matches everything.
+func (query Query) Match(document Document) ([]int, bool) {
+	var highlights []int
+	for _, term := range query {
+		matched, ok := term.match(document)
+		if ok == term.Negated {
+			return nil, false
+		}
+		if !term.Negated {
+			highlights = append(highlights, matched...)
+		}
+	}
+	slices.Sort(highlights)
+	return slices.Compact(highlights), true
+}
+
+// match tells whether the term matches the document, along
+// with the runes of the name it matched.
+func (term Term) match(document Document) ([]int, bool) {
+	switch term.Field {
+	case FieldAny:
+		if matches := fuzzy.Find(term.Value, []string{document.Name}); len(matches) > 0 {
+			return runeIndexes(document.Name, matches[0].MatchedIndexes), true
+		}
+		for field, values := range document.Fields {
+			// Flags are only matched when asked for
+			if field != FieldIs && slices.ContainsFunc(values, term.contained) {
+				return nil, true
+			}
+		}
+		return nil, false
+	case FieldName:
+		name, value := []rune(document.Name), []rune(term.Value)
+		for start := 0; start+len(value) <= len(name); start++ {
+			if !strings.EqualFold(string(name[start:start+len(value)]),
+				term.Value) {
+				continue
+			}
+			highlights := make([]int, len(value))
+			for i := range highlights {
+				highlights[i] = start + i
+			}
+			return highlights, true
+		}
+		return nil, false
+	case FieldIs:
+		return nil, slices.ContainsFunc(document.Fields[FieldIs],
+			func(flag string) bool {
+				return strings.EqualFold(flag, term.Value)
+			})
+	default:
+		return nil, slices.ContainsFunc(document.Fields[term.Field],
+			term.contained)
+	}
+}
+
+// contained tells whether the value holds the one of the
+// term, regardless of the case.
+func (term Term) contained(value string) bool {
+	return strings.Contains(strings.ToLower(value), strings.ToLower(term.Value))
+}
+
+// runeIndexes turns the byte indexes fuzzy matching
+// gives into rune indexes.
+func runeIndexes(value string, indexes []int) []int {
+	runes := make([]int, len(indexes))
+	for i, index := range indexes {
+		runes[i] = utf8.RuneCountInString(value[:index])
+	}
+	return runes
+}
@@ -0,0 +1,74 @@
+package query
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMatch(t *testing.T) {
+	document := Document{
+		Name: "GitHub Work",
+		Fields: map[Field][]string{
+			FieldEmail:    {"dev@corp.com"},
+			FieldURL:      {"https://github.com", "https://gist.github.com"},
+			FieldTag:      {"prod", "code"},
+			FieldCategory: {"Work/Dev tools"},
+			FieldNotes:    {"recovery codes in the safe"},
+			FieldType:     {"login", "Login"},
+			FieldIs:       {"favorite", "weak"},
+		},
+	}
+
+	tests := []struct {
+		input      string
+		want       bool
+		highlights []int
+	}{
+		{"", true, nil},
+		{"ghw", true, []int{0, 3, 7}},
+		{"corp.com", true, nil},
+		{"safe", true, nil},
+		{"gitlab", false, nil},
+		{"weak", false, nil},
+		{"name:hub", true, []int{3, 4, 5}},
+		{"name:corp", false, nil},
+		{"email:@CORP.com", true, nil},
+		{"url:gist", true, nil},
+		{"tag:prod", true, nil},
+		{"tag:staging", false, nil},
+		{"-tag:staging", true, nil},
+		{"-tag:prod", false, nil},
+		{`cat:"dev tools"`, true, nil},
+		{"type:login", true, nil},
+		{"is:favorite", true, nil},
+		{"is:WEAK", true, nil},
+		{"is:fav", false, nil},
+		{"-is:weak", false, nil},
+		{"is:breached", false, nil},
+		{"-is:breached name:git", true, []int{0, 1, 2}},
+		{"unknown:github", false, nil},
+		{"tag:prod gitlab", false, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			highlights, ok := Parse(tt.input).Match(document)
+			if ok != tt.want {
+				t.Fatalf("Match(%q) = %v, want %v", tt.input, ok, tt.want)
+			}
+			if len(highlights) == 0 {
+				highlights = nil
+			}
+			if !reflect.DeepEqual(highlights, tt.highlights) {
+				t.Errorf("Match(%q) highlights = %v, want %v",
+					tt.input, highlights, tt.highlights)
+			}
+		})
+	}
+}
+
+func TestMatchHighlightsRunes(t *testing.T) {
+	highlights, ok := Parse("café").Match(Document{Name: "Le café"})
+	if !ok || !reflect.DeepEqual(highlights, []int{3, 4, 5, 6}) {
+		t.Errorf("Match = %v, %v, want [3 4 5 6], true", highlights, ok)
+	}
+}
@@ -0,0 +1,131 @@
+// Package query parses the search typed in the shelf, e.g.
+// `email:@corp.com tag:prod cat:Work -is:weak`, and matches items
+// against it.
+//
+// A query is a list of terms separated by spaces, every one of them
+// having to match. A term is either free text, fuzzy matched against
+// the name and looked up in every other field, or restricted to a
+// field with `field:value`. Prefixing a term with `-` excludes the
+// items it matches, and double quotes keep spaces within a value.
+package query
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Field is what a term is restricted to.
+type Field string
+
+const (
+	FieldAny      Field = ""
+	FieldName     Field = "name"
+	FieldEmail    Field = "email"
+	FieldUsername Field = "username"
+	FieldURL      Field = "url"
+	FieldTag      Field = "tag"
+	FieldCategory Field = "category"
+	FieldNotes    Field = "notes"
+	FieldType     Field = "type"
+	// FieldIs holds flags matched as a whole, e.g. `is:favorite`.
+	FieldIs Field = "is"
+)
+
+// aliases maps every accepted field name to its field.
+var aliases = map[string]Field{
+	"name":     FieldName,
+	"email":    FieldEmail,
+	"mail":     FieldEmail,
+	"user":     FieldUsername,
+	"username": FieldUsername,
+	"url":      FieldURL,
+	"site":     FieldURL,
+	"tag":      FieldTag,
+	"cat":      FieldCategory,
+	"category": FieldCategory,
+	"note":     FieldNotes,
+	"notes":    FieldNotes,
+	"type":     FieldType,
+	"is":       FieldIs,
+}
+
+// Term is a single condition of a query.
+type Term struct {
+	Field   Field
+	Value   string
+	Negated bool
+}
+
+// Query is a list of terms that all have to match.
+type Query []Term
+
+// Parse reads the query out of the input. It never fails so that
+// a query being typed is matched as it goes: an unknown field is
+// taken as free text, a field without value and an unterminated
+// quote are fine.
+func Parse(input string) Query {
+	var query Query
+	for _, token := range tokenize(input) {
+		term := Term{Value: token}
+		if strings.HasPrefix(term.Value, "-") {
+			term.Negated = true
+			term.Value = term.Value[1:]
+		}
+		if name, value, ok := strings.Cut(term.Value, ":"); ok {
+			if field, known := aliases[strings.ToLower(name)]; known {
+				term.Field = field
+				term.Value = value
+			}
+		}
+		term.Value = strings.ReplaceAll(term.Value, `"`, "")
+		if term.Value == "" {
+			continue
+		}
+		query = append(query, term)
+	}
+	return query
+}
+
+// tokenize splits the input on the spaces outside double quotes.
+func tokenize(input string) []string {
+	var tokens []string
+	var token strings.Builder
+	quoted := false
+	for _, r := range input {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			token.WriteRune(r)
+		case unicode.IsSpace(r) && !quoted:
+			if token.Len() > 0 {
+				tokens = append(tokens, token.String())
+				token.Reset()
+			}
+		default:
+			token.WriteRune(r)
+		}
+	}
+	if token.Len() > 0 {
+		tokens = append(tokens, token.String())
+	}
+	return tokens
+}
+
+// String writes the query back, one term after another.
+func (query Query) String() string {
+	terms := make([]string, len(query))
+	for i, term := range query {
+		value := term.Value
+		if strings.ContainsFunc(value, unicode.IsSpace) {
+			value = `"` + value + `"`
+		}
+		if term.Field != FieldAny {
+			value = string(term.Field) + ":" + value
+		}
+		if term.Negated {
+			value = "-" + value
+		}
+		terms[i] = value
+	}
+	return strings.Join(terms, " ")
+}
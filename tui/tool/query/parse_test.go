@@ -0,0 +1,56 @@
+package query
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Query
+	}{
+		{"", nil},
+		{"   ", nil},
+		{"github", Query{{Value: "github"}}},
+		{"git hub", Query{{Value: "git"}, {Value: "hub"}}},
+		{"email:@corp.com", Query{{Field: FieldEmail, Value: "@corp.com"}}},
+		{"MAIL:a user:b site:c", Query{
+			{Field: FieldEmail, Value: "a"},
+			{Field: FieldUsername, Value: "b"},
+			{Field: FieldURL, Value: "c"},
+		}},
+		{"-tag:old", Query{{Field: FieldTag, Value: "old", Negated: true}}},
+		{"-is:weak", Query{{Field: FieldIs, Value: "weak", Negated: true}}},
+		{"-old", Query{{Value: "old", Negated: true}}},
+		{`cat:"Work stuff"`, Query{{Field: FieldCategory, Value: "Work stuff"}}},
+		{`"two words" x`, Query{{Value: "two words"}, {Value: "x"}}},
+		{`note:"unterminated quote`, Query{{Field: FieldNotes, Value: "unterminated quote"}}},
+		{"foo:bar", Query{{Value: "foo:bar"}}},
+		{"https://example.com", Query{{Value: "https://example.com"}}},
+		{"tag: -", nil},
+		{"tag:", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := Parse(tt.input); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse(%q) = %#v, want %#v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQueryString(t *testing.T) {
+	tests := []struct {
+		input, want string
+	}{
+		{"github", "github"},
+		{`-cat:"Work stuff"  mail:a`, `-category:"Work stuff" email:a`},
+		{"foo:bar", "foo:bar"},
+	}
+	for _, tt := range tests {
+		if got := Parse(tt.input).String(); got != tt.want {
+			t.Errorf("Parse(%q).String() = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,231 @@
+// Package settings reads and writes the preferences kept in the
+// configurations table. Every setting knows its key, its default and
+// which values are valid, so that a malformed row falls back to the
+// default and an invalid value is never written.
+package settings
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"viscue/tui/tool/crypto"
+	"viscue/tui/tool/keychain"
+	"viscue/tui/tool/theme"
+
+	"github.com/charmbracelet/log"
+	"github.com/jmoiron/sqlx"
+)
+
+var (
+	AutoLockTimeout = Int{ // in minutes, zero never locking
+		Key: "auto_lock_timeout", Default: 0, Min: 0, Max: 24 * 60,
+	}
+	ClipboardClearDelay = Int{ // in seconds, zero never clearing
+		Key: "clipboard_clear_delay", Default: 30, Min: 0, Max: 60 * 60,
+	}
+	GeneratorLength = Int{
+		Key: "generator_length", Default: 24, Min: crypto.MinPasswordLength, Max: 128,
+	}
+	GeneratorDigits  = Bool{Key: "generator_digits", Default: true}
+	GeneratorSymbols = Bool{Key: "generator_symbols", Default: true}
+	// Theme overrides the theme picked in the theme file, unless empty
+	Theme = Choice{
+		Key: "theme",
+		Options: func() []string {
+			return append([]string{""}, theme.Names()...)
+		},
+	}
+	KeyringBackend = Choice{
+		Key: "keyring_backend", Default: keychain.BackendSystem,
+		Options: keychain.Backends,
+	}
+	// DefaultCategory is the one new items are put in when
+	// no actual category is selected
+	DefaultCategory = Category{Key: "default_category"}
+	// PasswordHistoryRetention is the number of previous values
+	// kept per password, zero disabling the history
+	PasswordHistoryRetention = Int{
+		Key: "password_history_retention", Default: 10, Min: 0, Max: 1000,
+	}
+	TrashRetention = Int{ // in days, zero keeping items until emptied
+		Key: "trash_retention", Default: 30, Min: 0, Max: 3650,
+	}
+	AttachmentSizeLimit = Int{ // in bytes
+		Key: "attachment_size_limit", Default: 10 << 20, Min: 0, Max: 1 << 30,
+	}
+)
+
+// Int is a setting holding a whole number within bounds.
+type Int struct {
+	Key      string
+	Default  int
+	Min, Max int
+}
+
+func (s Int) Get(q sqlx.Queryer) int {
+	value, ok := get(q, s.Key)
+	if !ok {
+		return s.Default
+	}
+	number, err := strconv.Atoi(value)
+	if err == nil {
+		err = s.Validate(number)
+	}
+	if err != nil {
+		log.Error("settings.Int.Get: malformed setting",
+			"key", s.Key, "value", value, "err", err)
+		return s.Default
+	}
+	return number
+}
+
+func (s Int) Validate(value int) error {
+	if value < s.Min || value > s.Max {
+		return fmt.Errorf("must be between %d and %d", s.Min, s.Max)
+	}
+	return nil
+}
+
+func (s Int) Set(e sqlx.Execer, value int) error {
+	if err := s.Validate(value); err != nil {
+		return err
+	}
+	return set(e, s.Key, strconv.Itoa(value))
+}
+
+// Bool is a setting either on or off.
+type Bool struct {
+	Key     string
+	Default bool
+}
+
+func (s Bool) Get(q sqlx.Queryer) bool {
+	value, ok := get(q, s.Key)
+	if !ok {
+		return s.Default
+	}
+	enabled, err := strconv.ParseBool(value)
+	if err != nil {
+		log.Error("settings.Bool.Get: malformed setting",
+			"key", s.Key, "value", value, "err", err)
+		return s.Default
+	}
+	return enabled
+}
+
+func (s Bool) Set(e sqlx.Execer, value bool) error {
+	return set(e, s.Key, strconv.FormatBool(value))
+}
+
+// Choice is a setting holding one of its options.
+type Choice struct {
+	Key     string
+	Default string
+	Options func() []string
+}
+
+func (s Choice) Get(q sqlx.Queryer) string {
+	value, ok := get(q, s.Key)
+	if !ok {
+		return s.Default
+	}
+	if err := s.Validate(value); err != nil {
+		log.Error("settings.Choice.Get: malformed setting",
+			"key", s.Key, "value", value, "err", err)
+		return s.Default
+	}
+	return value
+}
+
+func (s Choice) Validate(value string) error {
+	options := s.Options()
+	if !slices.Contains(options, value) {
+		return fmt.Errorf("must be one of %s", strings.Join(options, ", "))
+	}
+	return nil
+}
+
+func (s Choice) Set(e sqlx.Execer, value string) error {
+	if err := s.Validate(value); err != nil {
+		return err
+	}
+	return set(e, s.Key, value)
+}
+
+var errNoCategory = errors.New("category does not exist")
+
+// Category is a setting holding the id of a category, zero for none.
+type Category struct {
+	Key string
+}
+
+// Get returns the id of the category, zero when none has been
+// set or when the category has been deleted since.
+func (s Category) Get(q sqlx.Queryer) int64 {
+	value, ok := get(q, s.Key)
+	if !ok {
+		return 0
+	}
+	id, err := strconv.ParseInt(value, 10, 64)
+	if err == nil {
+		err = s.Validate(q, id)
+	}
+	if errors.Is(err, errNoCategory) {
+		return 0
+	} else if err != nil {
+		log.Error("settings.Category.Get: malformed setting",
+			"key", s.Key, "value", value, "err", err)
+		return 0
+	}
+	return id
+}
+
+func (s Category) Validate(q sqlx.Queryer, id int64) error {
+	if id == 0 {
+		return nil
+	}
+	var exists bool
+	err := q.QueryRowx("SELECT EXISTS (SELECT 1 FROM categories WHERE id = ?)", id).
+		Scan(&exists)
+	if err != nil {
+		return err
+	} else if !exists {
+		return errNoCategory
+	}
+	return nil
+}
+
+func (s Category) Set(e sqlx.Ext, id int64) error {
+	if err := s.Validate(e, id); err != nil {
+		return err
+	}
+	return set(e, s.Key, strconv.FormatInt(id, 10))
+}
+
+func get(q sqlx.Queryer, key string) (string, bool) {
+	var value string
+	err := q.QueryRowx("SELECT value FROM configurations WHERE key = ?", key).
+		Scan(&value)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			log.Error("settings.get: failed querying setting",
+				"key", key, "err", err)
+		}
+		return "", false
+	}
+	return value, true
+}
+
+func set(e sqlx.Execer, key, value string) error {
+	_, err := e.Exec(`INSERT INTO configurations (key, value) VALUES (?, ?)
+		ON CONFLICT (key) DO UPDATE SET value = excluded.value`, key, value)
+	if err != nil {
+		log.Error("settings.set: failed saving setting", "key", key, "err", err)
+		return errors.New("failed saving setting")
+	}
+	return nil
+}
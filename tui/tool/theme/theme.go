@@ -0,0 +1,222 @@
+// Package theme picks the colors of the interface from a YAML file
+// in the configuration directory, e.g.
+//
+//	theme: mine
+//	themes:
+//	  mine:
+//	    base: solarized
+//	    accent: "#D33682"
+//	    muted: {light: "#93A1A1", dark: "#586E75"}
+//
+// The theme, unless picked in the settings, is either a built-in one
+// or a custom one, which starts from a built-in base and overrides
+// some of its colors. A color is a hex code, an ANSI number, a light
+// and dark pair or `none`. Setting NO_COLOR forces the no-color theme.
+package theme
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"viscue/tui/style"
+	"viscue/tui/tool/keymap"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/log"
+	"gopkg.in/yaml.v3"
+)
+
+// FileName is the name of the theme file within the
+// configuration directory.
+const FileName = "theme.yaml"
+
+var hexColor = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// Color is a color written in the theme file.
+type Color struct {
+	lipgloss.TerminalColor
+}
+
+func (color *Color) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		value, err := parseColor(node.Value)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", node.Line, err)
+		}
+		color.TerminalColor = value
+		return nil
+	}
+
+	var pair struct {
+		Light string `yaml:"light"`
+		Dark  string `yaml:"dark"`
+	}
+	if err := node.Decode(&pair); err != nil {
+		return err
+	}
+	for _, value := range []string{pair.Light, pair.Dark} {
+		if value == "" {
+			return fmt.Errorf("line %d: a color needs both light and dark", node.Line)
+		}
+		if _, err := parseColor(value); err != nil {
+			return fmt.Errorf("line %d: %w", node.Line, err)
+		}
+	}
+	color.TerminalColor = lipgloss.AdaptiveColor{Light: pair.Light, Dark: pair.Dark}
+	return nil
+}
+
+func parseColor(value string) (lipgloss.TerminalColor, error) {
+	if value == "none" {
+		return lipgloss.NoColor{}, nil
+	}
+	if hexColor.MatchString(value) {
+		return lipgloss.Color(value), nil
+	}
+	if n, err := strconv.Atoi(value); err == nil && n >= 0 && n <= 255 {
+		return lipgloss.Color(value), nil
+	}
+	return nil, fmt.Errorf("invalid color %q", value)
+}
+
+// Custom is a theme defined in the theme file.
+type Custom struct {
+	Base   string
+	Colors map[string]Color
+}
+
+func (custom *Custom) UnmarshalYAML(node *yaml.Node) error {
+	var fields map[string]yaml.Node
+	if err := node.Decode(&fields); err != nil {
+		return err
+	}
+	custom.Colors = make(map[string]Color, len(fields))
+	for name, value := range fields {
+		if name == "base" {
+			if err := value.Decode(&custom.Base); err != nil {
+				return err
+			}
+			continue
+		}
+		var color Color
+		if err := value.Decode(&color); err != nil {
+			return err
+		}
+		custom.Colors[name] = color
+	}
+	return nil
+}
+
+// Config is the content of the theme file.
+type Config struct {
+	Theme  string            `yaml:"theme"`
+	Themes map[string]Custom `yaml:"themes"`
+}
+
+// Path returns the path of the theme file.
+func Path() (string, error) {
+	dir, err := keymap.Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, FileName), nil
+}
+
+// Load reads the theme file and resolves the theme picked by name,
+// or by the file when the name is empty. No file means the default
+// theme and no custom one.
+func Load(name string) (style.Theme, error) {
+	config, err := read()
+	if err != nil {
+		return style.Theme{}, err
+	}
+	if name != "" {
+		config.Theme = name
+	}
+	return config.Resolve()
+}
+
+// Names returns the names of the built-in themes followed by
+// the ones of the custom themes defined in the theme file.
+func Names() []string {
+	names := style.ThemeNames()
+	config, err := read()
+	if err != nil {
+		log.Error("theme.Names: failed reading theme file", "err", err)
+		return names
+	}
+	custom := slices.Sorted(maps.Keys(config.Themes))
+	for _, name := range custom {
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func read() (Config, error) {
+	path, err := Path()
+	if err != nil {
+		return Config{}, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return Config{}, nil
+	} else if err != nil {
+		return Config{}, err
+	}
+	var config Config
+	if err = yaml.Unmarshal(data, &config); err != nil {
+		return Config{}, fmt.Errorf("%s: %w", FileName, err)
+	}
+	return config, nil
+}
+
+// Resolve returns the theme the config picks, filling a custom theme
+// from its base. NO_COLOR takes precedence over any theme.
+func (config Config) Resolve() (style.Theme, error) {
+	builtIn := style.Themes()
+	if os.Getenv("NO_COLOR") != "" {
+		return builtIn[style.ThemeNoColor], nil
+	}
+
+	name := config.Theme
+	if name == "" {
+		name = style.ThemeAuto
+	}
+	custom, ok := config.Themes[name]
+	if !ok {
+		theme, ok := builtIn[name]
+		if !ok {
+			return style.Theme{}, fmt.Errorf("unknown theme %q, pick one of %s "+
+				"or define it under themes", name, strings.Join(style.ThemeNames(), ", "))
+		}
+		return theme, nil
+	}
+
+	base := custom.Base
+	if base == "" {
+		base = style.ThemeAuto
+	}
+	theme, ok := builtIn[base]
+	if !ok {
+		return style.Theme{}, fmt.Errorf("%s: unknown base theme %q", name, base)
+	}
+	theme.Name = name
+	colors := theme.Colors()
+	for color, value := range custom.Colors {
+		slot, ok := colors[color]
+		if !ok {
+			return style.Theme{}, fmt.Errorf("%s: unknown color %q", name, color)
+		}
+		*slot = value.TerminalColor
+	}
+	return theme, nil
+}
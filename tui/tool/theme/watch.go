@@ -0,0 +1,98 @@
+package theme
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"viscue/tui/style"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/log"
+	"github.com/fsnotify/fsnotify"
+)
+
+// settleDelay lets an editor finish saving, often in several
+// writes, before the theme file is read again.
+const settleDelay = 100 * time.Millisecond
+
+// ChangedMsg is sent when the theme file has changed, holding the
+// theme it now picks, or why it could not be loaded.
+type ChangedMsg struct {
+	Theme style.Theme
+	Err   error
+}
+
+// Watch sends a ChangedMsg whenever the theme file changes, until
+// stop is called. The theme is picked by name as Load does. The configuration directory is watched rather
+// than the file, which editors often replace when saving. Nothing
+// is watched when the directory does not exist or NO_COLOR is set.
+func Watch(send func(tea.Msg), name func() string) (stop func(), err error) {
+	stop = func() {}
+	if os.Getenv("NO_COLOR") != "" {
+		return stop, nil
+	}
+	path, err := Path()
+	if err != nil {
+		return stop, err
+	}
+	if _, err = os.Stat(filepath.Dir(path)); errors.Is(err, os.ErrNotExist) {
+		return stop, nil
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return stop, err
+	}
+	if err = watcher.Add(filepath.Dir(path)); err != nil {
+		watcher.Close()
+		return stop, err
+	}
+
+	var (
+		mu    sync.Mutex
+		timer *time.Timer
+	)
+	reload := func() {
+		theme, err := Load(name())
+		if err != nil {
+			log.Error("theme.Watch: failed reloading theme", "err", err)
+		}
+		send(ChangedMsg{Theme: theme, Err: err})
+	}
+	go func() {
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if filepath.Base(event.Name) != FileName || event.Op == fsnotify.Chmod {
+					continue
+				}
+				mu.Lock()
+				if timer != nil {
+					timer.Stop()
+				}
+				timer = time.AfterFunc(settleDelay, reload)
+				mu.Unlock()
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				log.Error("theme.Watch: failed watching theme file", "err", err)
+			}
+		}
+	}()
+
+	return func() {
+		watcher.Close()
+		mu.Lock()
+		if timer != nil {
+			timer.Stop()
+		}
+		mu.Unlock()
+	}, nil
+}
@@ -0,0 +1,252 @@
+package undo
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// row is a stored row by column.
+type row map[string]any
+
+// snapshot is an entity as stored along with the rows depending on it.
+type snapshot struct {
+	row  row
+	tags []string
+	// owned are the rows edited along with the entity, replaced
+	// whenever it is restored
+	owned map[string][]row
+	// kept are the rows gathered over time, only brought back
+	// along with an entity deleted for good
+	kept map[string][]row
+}
+
+var (
+	ownedTables = map[Table][]string{
+		Passwords: {"password_fields", "password_urls"},
+	}
+	keptTables = map[Table][]string{
+		Passwords: {"password_history", "attachments"},
+	}
+)
+
+// capture reads the entity, returning nil when it does not exist.
+// The kept rows, attachments included, are only read when asked.
+// Table names never come from the user.
+func capture(q sqlx.Queryer, table Table, id int64, withKept bool) (*snapshot, error) {
+	rows, err := selectRows(q, fmt.Sprintf("SELECT * FROM %s WHERE id = ?", table), id)
+	if err != nil || len(rows) == 0 {
+		return nil, err
+	}
+
+	s := &snapshot{
+		row:   rows[0],
+		owned: make(map[string][]row),
+		kept:  make(map[string][]row),
+	}
+	for _, child := range ownedTables[table] {
+		if s.owned[child], err = selectChildren(q, child, id); err != nil {
+			return nil, err
+		}
+	}
+	for _, child := range keptTables[table] {
+		if !withKept {
+			break
+		}
+		if s.kept[child], err = selectChildren(q, child, id); err != nil {
+			return nil, err
+		}
+	}
+	if table == Passwords {
+		err = sqlx.Select(q, &s.tags,
+			`SELECT t.name FROM tags t
+			JOIN password_tags pt ON pt.tag_id = t.id
+			WHERE pt.password_id = ? ORDER BY t.name`, id)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+func selectChildren(q sqlx.Queryer, table string, passwordId int64) ([]row, error) {
+	return selectRows(q,
+		fmt.Sprintf("SELECT * FROM %s WHERE password_id = ?", table), passwordId)
+}
+
+func selectRows(q sqlx.Queryer, query string, args ...any) ([]row, error) {
+	rows, err := q.Queryx(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []row
+	for rows.Next() {
+		r := make(row)
+		if err = rows.MapScan(r); err != nil {
+			return nil, err
+		}
+		result = append(result, r)
+	}
+	return result, rows.Err()
+}
+
+// restore writes the entity back as it was in the target snapshot,
+// deleting it when the target is nil. When the entity still exists,
+// only what differs from the current snapshot, the state the
+// operation left it in, is written back. Whatever changed apart
+// from the operation, such as the last use of a password or the
+// counter of its one-time password, is left alone.
+func restore(tx *sqlx.Tx, table Table, id int64, target, current *snapshot) error {
+	if target == nil {
+		_, err := tx.Exec(fmt.Sprintf("DELETE FROM %s WHERE id = ?", table), id)
+		return err
+	}
+
+	var exists bool
+	err := tx.Get(&exists,
+		fmt.Sprintf("SELECT EXISTS (SELECT 1 FROM %s WHERE id = ?)", table), id)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return recreate(tx, table, id, target)
+	}
+	if current == nil {
+		current = &snapshot{}
+	}
+
+	if err = update(tx, string(table), id, changedColumns(target.row, current.row)); err != nil {
+		return err
+	}
+	for child, rows := range target.owned {
+		if reflect.DeepEqual(rows, current.owned[child]) {
+			continue
+		}
+		if err = replaceChildren(tx, child, id, rows); err != nil {
+			return err
+		}
+	}
+	if table == Passwords && !slices.Equal(target.tags, current.tags) {
+		return replaceTags(tx, id, target.tags)
+	}
+	return nil
+}
+
+// recreate inserts the entity deleted since the snapshot along with
+// every row depending on it.
+func recreate(tx *sqlx.Tx, table Table, id int64, s *snapshot) error {
+	if err := insert(tx, string(table), s.row); err != nil {
+		return err
+	}
+	for _, children := range []map[string][]row{s.owned, s.kept} {
+		for child, rows := range children {
+			if err := replaceChildren(tx, child, id, rows); err != nil {
+				return err
+			}
+		}
+	}
+	if table == Passwords {
+		return replaceTags(tx, id, s.tags)
+	}
+	return nil
+}
+
+// changedColumns returns the columns of the target row having
+// another value in the current one.
+func changedColumns(target, current row) row {
+	changed := make(row)
+	for column, value := range target {
+		if column == "id" {
+			continue
+		}
+		if old, ok := current[column]; !ok || !sameValue(value, old) {
+			changed[column] = value
+		}
+	}
+	return changed
+}
+
+func sameValue(a, b any) bool {
+	if t, ok := a.(time.Time); ok {
+		u, ok := b.(time.Time)
+		return ok && t.Equal(u)
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func replaceChildren(tx *sqlx.Tx, table string, passwordId int64, rows []row) error {
+	_, err := tx.Exec(
+		fmt.Sprintf("DELETE FROM %s WHERE password_id = ?", table), passwordId)
+	if err != nil {
+		return err
+	}
+	for _, r := range rows {
+		if err = insert(tx, table, r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func replaceTags(tx *sqlx.Tx, passwordId int64, tags []string) error {
+	_, err := tx.Exec("DELETE FROM password_tags WHERE password_id = ?", passwordId)
+	if err != nil {
+		return err
+	}
+	for _, tag := range tags {
+		_, err = tx.Exec("INSERT OR IGNORE INTO tags (name) VALUES (?)", tag)
+		if err == nil {
+			_, err = tx.Exec(
+				`INSERT OR IGNORE INTO password_tags (password_id, tag_id)
+				SELECT ?, id FROM tags WHERE name = ?`,
+				passwordId, tag,
+			)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func insert(tx *sqlx.Tx, table string, r row) error {
+	columns := slices.Sorted(maps.Keys(r))
+	args := make([]any, len(columns))
+	for i, column := range columns {
+		args[i] = r[column]
+	}
+	_, err := tx.Exec(fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
+		table,
+		strings.Join(columns, ", "),
+		strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", "),
+	), args...)
+	return err
+}
+
+// update writes the given columns, doing nothing when there are none.
+func update(tx *sqlx.Tx, table string, id int64, r row) error {
+	var (
+		assignments []string
+		args        []any
+	)
+	for _, column := range slices.Sorted(maps.Keys(r)) {
+		assignments = append(assignments, column+" = ?")
+		args = append(args, r[column])
+	}
+	if len(assignments) == 0 {
+		return nil
+	}
+	_, err := tx.Exec(
+		fmt.Sprintf("UPDATE %s SET %s WHERE id = ?", table,
+			strings.Join(assignments, ", ")),
+		append(args, id)...,
+	)
+	return err
+}
@@ -0,0 +1,221 @@
+// Package undo keeps the changes made to the passwords and the
+// categories so that they can be reverted and applied again. Every
+// change holds the rows of the entity as stored, before and after,
+// so the secrets stay encrypted while on the stack.
+package undo
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/charmbracelet/log"
+	"github.com/jmoiron/sqlx"
+)
+
+type Table string
+
+const (
+	Passwords  Table = "passwords"
+	Categories Table = "categories"
+)
+
+// limit is the number of operations kept, the oldest being dropped.
+const limit = 100
+
+var (
+	ErrNothingToUndo = errors.New("nothing to undo")
+	ErrNothingToRedo = errors.New("nothing to redo")
+)
+
+// change is an entity as it was before and after an operation,
+// nil when it did not exist.
+type change struct {
+	table         Table
+	id            int64
+	before, after *snapshot
+}
+
+// Operation is what the user did in one go, such as saving a
+// password or moving several of them to another category.
+type Operation struct {
+	Label   string
+	changes []change
+}
+
+var (
+	done, undone []Operation
+	mutex        sync.Mutex
+)
+
+// Recorder collects the changes of an operation while it is being
+// done. Entities are tracked before they change, and Done reads
+// them again once they have.
+type Recorder struct {
+	operation Operation
+}
+
+// Record starts recording the operation named by the label, which
+// is shown once it gets undone or redone.
+func Record(label string) *Recorder {
+	return &Recorder{operation: Operation{Label: label}}
+}
+
+// Track reads the entities as they are before being changed.
+func (r *Recorder) Track(q sqlx.Queryer, table Table, ids ...int64) error {
+	for _, id := range ids {
+		if r.tracks(table, id) {
+			continue
+		}
+		before, err := capture(q, table, id, true)
+		if err != nil {
+			return fmt.Errorf("failed reading %s %d: %w", table, id, err)
+		}
+		r.operation.changes = append(r.operation.changes,
+			change{table: table, id: id, before: before})
+	}
+	return nil
+}
+
+// Created tracks an entity that did not exist before the operation.
+func (r *Recorder) Created(table Table, id int64) {
+	if !r.tracks(table, id) {
+		r.operation.changes = append(r.operation.changes,
+			change{table: table, id: id})
+	}
+}
+
+func (r *Recorder) tracks(table Table, id int64) bool {
+	return slices.ContainsFunc(r.operation.changes, func(c change) bool {
+		return c.table == table && c.id == id
+	})
+}
+
+// Done reads the tracked entities as they are after being changed.
+// It is meant to be called within the transaction of the operation,
+// right before committing it.
+func (r *Recorder) Done(q sqlx.Queryer) error {
+	for i, c := range r.operation.changes {
+		// The kept rows are only needed to bring back an entity
+		// deleted for good, whereas an entity brought back by a redo
+		// was created by the operation and has none yet
+		after, err := capture(q, c.table, c.id, false)
+		if err != nil {
+			return fmt.Errorf("failed reading %s %d: %w", c.table, c.id, err)
+		}
+		r.operation.changes[i].after = after
+		if after != nil && c.before != nil {
+			c.before.kept = nil
+		}
+	}
+	return nil
+}
+
+// Push puts the operation onto the stack once it has been committed,
+// forgetting the undone operations.
+func (r *Recorder) Push() {
+	push(r.operation)
+}
+
+func push(operation Operation) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	done = append(done, operation)
+	if len(done) > limit {
+		done = slices.Delete(done, 0, len(done)-limit)
+	}
+	undone = nil
+}
+
+// Undo reverts the last operation, returning its label.
+func Undo(db *sqlx.DB) (string, error) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	if len(done) == 0 {
+		return "", ErrNothingToUndo
+	}
+
+	operation := done[len(done)-1]
+	changes := slices.Clone(operation.changes)
+	slices.Reverse(changes)
+	if err := apply(db, changes, func(c change) (*snapshot, *snapshot) {
+		return c.before, c.after
+	}); err != nil {
+		return operation.Label, err
+	}
+	done = done[:len(done)-1]
+	undone = append(undone, operation)
+	return operation.Label, nil
+}
+
+// Redo does again the last undone operation, returning its label.
+func Redo(db *sqlx.DB) (string, error) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	if len(undone) == 0 {
+		return "", ErrNothingToRedo
+	}
+
+	operation := undone[len(undone)-1]
+	if err := apply(db, operation.changes, func(c change) (*snapshot, *snapshot) {
+		return c.after, c.before
+	}); err != nil {
+		return operation.Label, err
+	}
+	undone = undone[:len(undone)-1]
+	done = append(done, operation)
+	return operation.Label, nil
+}
+
+// Clear forgets every operation, such as when the library is locked.
+func Clear() {
+	mutex.Lock()
+	defer mutex.Unlock()
+	done, undone = nil, nil
+}
+
+// apply writes the state picked from every change within a single
+// transaction, moving away from the other one. Foreign keys are
+// checked once all of them are written, since a category may be
+// brought back after the passwords within it.
+func apply(
+	db *sqlx.DB,
+	changes []change,
+	states func(change) (target, current *snapshot),
+) error {
+	tx, err := db.Beginx()
+	if err != nil {
+		log.Error("undo.apply: failed to start transaction", "err", err)
+		return err
+	}
+	if _, err = tx.Exec("PRAGMA defer_foreign_keys = ON"); err != nil {
+		log.Error("undo.apply: failed deferring foreign keys", "err", err)
+		_ = tx.Rollback()
+		return err
+	}
+
+	for _, c := range changes {
+		target, current := states(c)
+		if err = restore(tx, c.table, c.id, target, current); err != nil {
+			log.Error("undo.apply: failed restoring entity",
+				"table", c.table, "id", c.id, "err", err)
+			_ = tx.Rollback()
+			return err
+		}
+	}
+	if _, err = tx.Exec(
+		`DELETE FROM tags
+		WHERE id NOT IN (SELECT DISTINCT tag_id FROM password_tags)`,
+	); err != nil {
+		log.Error("undo.apply: failed pruning tags", "err", err)
+		_ = tx.Rollback()
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error("undo.apply: failed to commit transaction", "err", err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+// Package urlmatch decides whether a URL saved on an item applies
+// to a visited URL, and ranks the items matching a given URL.
+package urlmatch
+
+import (
+	"errors"
+	"net"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"golang.org/x/net/publicsuffix"
+)
+
+// Rule tells how a saved URL is compared to a visited one.
+type Rule string
+
+const (
+	// Exact requires the same scheme, host, port and path.
+	Exact Rule = "exact"
+	// Host requires the same scheme, host and port.
+	Host Rule = "host"
+	// Domain requires the same scheme and registrable domain, e.g.
+	// `example.co.uk` for `login.example.co.uk`. It is the default rule.
+	Domain Rule = "domain"
+	// Regex treats the saved URL as a regular expression that
+	// has to match the whole visited URL.
+	Regex Rule = "regex"
+)
+
+// Rules lists every rule in the order they are cycled through.
+var Rules = []Rule{Domain, Host, Exact, Regex}
+
+var ErrUnknownRule = errors.New("unknown url match rule")
+
+// Pattern is a saved URL along with its rule.
+type Pattern struct {
+	URL  string
+	Rule Rule
+}
+
+// ParseRule returns the rule with the given name, an empty
+// name giving the default one.
+func ParseRule(name string) (Rule, error) {
+	switch rule := Rule(strings.ToLower(strings.TrimSpace(name))); rule {
+	case "":
+		return Domain, nil
+	case Exact, Host, Domain, Regex:
+		return rule, nil
+	default:
+		return "", ErrUnknownRule
+	}
+}
+
+// Next returns the following rule, wrapping around.
+func (rule Rule) Next() Rule {
+	for i, r := range Rules {
+		if r == rule {
+			return Rules[(i+1)%len(Rules)]
+		}
+	}
+	return Domain
+}
+
+// Parse parses a URL, assuming https when the scheme is missing
+// so that bare hosts such as `github.com` can be saved.
+func Parse(raw string) (*url.URL, error) {
+	raw = strings.TrimSpace(raw)
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, err
+	}
+	if u.Host == "" {
+		return nil, errors.New("url has no host")
+	}
+	return u, nil
+}
+
+// Match tells whether the pattern applies to the target URL.
+func (pattern Pattern) Match(target string) bool {
+	if pattern.Rule == Regex {
+		re, err := regexp.Compile(`^(?:` + pattern.URL + `)$`)
+		if err != nil {
+			return false
+		}
+		return re.MatchString(strings.TrimSpace(target))
+	}
+
+	saved, err := Parse(pattern.URL)
+	if err != nil {
+		return false
+	}
+	visited, err := Parse(target)
+	if err != nil {
+		return false
+	}
+
+	// An https entry must never be sent to a plain http site
+	if !strings.EqualFold(saved.Scheme, visited.Scheme) {
+		return false
+	}
+	switch pattern.Rule {
+	case Exact:
+		return strings.EqualFold(saved.Host, visited.Host) &&
+			strings.TrimSuffix(saved.Path, "/") ==
+				strings.TrimSuffix(visited.Path, "/")
+	case Host:
+		return strings.EqualFold(saved.Host, visited.Host)
+	default:
+		return BaseDomain(saved.Hostname()) == BaseDomain(visited.Hostname())
+	}
+}
+
+// specificity orders the rules from the most to the least precise.
+var specificity = map[Rule]int{Exact: 0, Regex: 1, Host: 2, Domain: 3}
+
+// Lookup returns the items having a pattern matching the target
+// URL, the ones matched by the most precise rule coming first.
+func Lookup[T any](target string, items []T, patterns func(T) []Pattern) []T {
+	type candidate struct {
+		item T
+		rank int
+	}
+
+	var candidates []candidate
+	for _, item := range items {
+		rank := -1
+		for _, pattern := range patterns(item) {
+			if !pattern.Match(target) {
+				continue
+			}
+			if r := specificity[pattern.Rule]; rank < 0 || r < rank {
+				rank = r
+			}
+		}
+		if rank >= 0 {
+			candidates = append(candidates, candidate{item, rank})
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].rank < candidates[j].rank
+	})
+	matches := make([]T, len(candidates))
+	for i, c := range candidates {
+		matches[i] = c.item
+	}
+	return matches
+}
+
+// BaseDomain returns the registrable part of a host name according
+// to the public suffix list, e.g. `example.co.uk` for
+// `login.example.co.uk` but `alice.github.io` as is, since anyone can
+// register under `github.io`. IP addresses, public suffixes and
+// single labels such as `localhost` are returned as is.
+func BaseDomain(host string) string {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	if net.ParseIP(host) != nil {
+		return host
+	}
+	domain, err := publicsuffix.EffectiveTLDPlusOne(host)
+	if err != nil {
+		return host
+	}
+	return domain
+}
@@ -0,0 +1,81 @@
+package urlmatch
+
+import "testing"
+
+func TestPatternMatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern Pattern
+		target  string
+		want    bool
+	}{
+		{"domain same host", Pattern{"https://github.com", Domain}, "https://github.com/login", true},
+		{"domain subdomain", Pattern{"github.com", Domain}, "https://gist.github.com", true},
+		{"domain country code", Pattern{"https://example.co.uk", Domain}, "https://login.example.co.uk", true},
+		{"domain other country code", Pattern{"https://example.co.uk", Domain}, "https://other.co.uk", false},
+		{"domain sibling subdomain", Pattern{"https://alice.github.io", Domain}, "https://mallory.github.io", false},
+		{"domain sibling subdomain path", Pattern{"https://alice.github.io/app", Domain}, "https://alice.github.io/other", true},
+		{"domain cross scheme", Pattern{"https://example.com", Domain}, "http://example.com", false},
+		{"domain bare host is https", Pattern{"example.com", Domain}, "http://example.com", false},
+		{"domain ip", Pattern{"https://192.168.1.1", Domain}, "https://192.168.1.1:8443", true},
+		{"domain localhost", Pattern{"http://localhost:3000", Domain}, "http://localhost:8080", true},
+		{"host same", Pattern{"https://example.com", Host}, "https://EXAMPLE.com/path", true},
+		{"host subdomain", Pattern{"https://example.com", Host}, "https://www.example.com", false},
+		{"host other port", Pattern{"https://example.com:8443", Host}, "https://example.com", false},
+		{"host cross scheme", Pattern{"https://example.com", Host}, "http://example.com", false},
+		{"exact same", Pattern{"https://example.com/login/", Exact}, "https://example.com/login", true},
+		{"exact other path", Pattern{"https://example.com/login", Exact}, "https://example.com/logout", false},
+		{"exact cross scheme", Pattern{"https://example.com/login", Exact}, "http://example.com/login", false},
+		{"regex whole url", Pattern{`https://(www\.)?example\.com/.*`, Regex}, "https://www.example.com/a", true},
+		{"regex partial", Pattern{`example\.com`, Regex}, "https://example.com", false},
+		{"regex invalid", Pattern{`(`, Regex}, "https://example.com", false},
+		{"invalid target", Pattern{"https://example.com", Domain}, "https://", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.pattern.Match(tt.target); got != tt.want {
+				t.Errorf("Match(%q) = %v, want %v", tt.target, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBaseDomain(t *testing.T) {
+	tests := []struct {
+		host, want string
+	}{
+		{"example.com", "example.com"},
+		{"login.example.com.", "example.com"},
+		{"a.b.example.co.uk", "example.co.uk"},
+		{"alice.github.io", "alice.github.io"},
+		{"github.io", "github.io"},
+		{"localhost", "localhost"},
+		{"10.0.0.1", "10.0.0.1"},
+		{"::1", "::1"},
+	}
+	for _, tt := range tests {
+		if got := BaseDomain(tt.host); got != tt.want {
+			t.Errorf("BaseDomain(%q) = %q, want %q", tt.host, got, tt.want)
+		}
+	}
+}
+
+func TestLookup(t *testing.T) {
+	items := []Pattern{
+		{"https://example.com", Domain},
+		{"https://www.example.com/login", Exact},
+		{"http://www.example.com", Host},
+		{"https://www.example.com", Host},
+	}
+	got := Lookup("https://www.example.com/login", items,
+		func(p Pattern) []Pattern { return []Pattern{p} })
+	want := []Pattern{items[1], items[3], items[0]}
+	if len(got) != len(want) {
+		t.Fatalf("Lookup returned %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Lookup()[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
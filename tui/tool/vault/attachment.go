@@ -0,0 +1,78 @@
+package vault
+
+import (
+	"crypto/rsa"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"viscue/tui/entity"
+	"viscue/tui/tool/cache"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Attachments returns the attachments of a password with their
+// names decrypted. Their content is left out.
+func Attachments(db *sqlx.DB, passwordId int64) ([]entity.Attachment, error) {
+	var attachments []entity.Attachment
+	err := db.Select(&attachments,
+		`SELECT id, password_id, name, size, created_at FROM attachments
+		WHERE password_id = ? ORDER BY created_at, id`,
+		passwordId,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	privateKey := cache.Get[*rsa.PrivateKey](cache.PrivateKey)
+	for i := range attachments {
+		if err = attachments[i].Decrypt(privateKey); err != nil {
+			return nil, err
+		}
+	}
+	return attachments, nil
+}
+
+// OpenAttachment returns the attachment with its content decrypted.
+func OpenAttachment(db *sqlx.DB, id int64) (entity.Attachment, error) {
+	var attachment entity.Attachment
+	err := db.Get(&attachment,
+		`SELECT id, password_id, name, size, data, created_at
+		FROM attachments WHERE id = ?`,
+		id,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity.Attachment{}, errors.New("attachment not found")
+	} else if err != nil {
+		return entity.Attachment{}, err
+	}
+
+	err = attachment.Decrypt(cache.Get[*rsa.PrivateKey](cache.PrivateKey))
+	return attachment, err
+}
+
+// WriteAttachment writes the content of the attachment to path,
+// readable by the current user only, and returns the path written.
+// Existing files are never overwritten.
+func WriteAttachment(attachment entity.Attachment, path string) (string, error) {
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		path = filepath.Join(path, filepath.Base(attachment.Name))
+	}
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		if errors.Is(err, os.ErrExist) {
+			return "", fmt.Errorf("%s already exists", path)
+		}
+		return "", fmt.Errorf("failed writing file: %w", err)
+	}
+	defer file.Close()
+
+	if _, err = file.Write(attachment.Data); err != nil {
+		return "", fmt.Errorf("failed writing file: %w", err)
+	}
+	return path, nil
+}
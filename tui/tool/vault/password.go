@@ -0,0 +1,136 @@
+package vault
+
+import (
+	"crypto/rsa"
+
+	"viscue/tui/entity"
+	"viscue/tui/tool/cache"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Passwords returns every password decrypted, along
+// with their custom fields and URLs, the trashed ones included.
+func Passwords(db *sqlx.DB) ([]entity.Password, error) {
+	rows, err := db.Queryx(
+		`SELECT id, category_id, type, name, email, username, password, otp,
+			notes, data, created_at, updated_at, last_used_at, favorite, deleted_at
+		FROM passwords`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	privateKey := cache.Get[*rsa.PrivateKey](cache.PrivateKey)
+	var passwords []entity.Password
+	for rows.Next() {
+		var password entity.Password
+		err = rows.StructScan(&password)
+		if err != nil {
+			return nil, err
+		}
+		err = password.Decrypt(privateKey)
+		if err != nil {
+			return nil, err
+		}
+		passwords = append(passwords, password)
+	}
+
+	fields, err := loadFields(db, privateKey)
+	if err != nil {
+		return nil, err
+	}
+	urls, err := loadURLs(db, privateKey)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := loadTags(db)
+	if err != nil {
+		return nil, err
+	}
+	for i := range passwords {
+		passwords[i].Fields = fields[passwords[i].Id]
+		passwords[i].URLs = urls[passwords[i].Id]
+		passwords[i].Tags = tags[passwords[i].Id]
+	}
+	return passwords, nil
+}
+
+// loadFields returns the decrypted custom fields grouped by password.
+func loadFields(
+	db *sqlx.DB,
+	privateKey *rsa.PrivateKey,
+) (map[int64][]entity.Field, error) {
+	var fields []entity.Field
+	err := db.Select(
+		&fields,
+		`SELECT id, password_id, name, value, concealed, position
+		FROM password_fields
+		ORDER BY password_id, position`,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	grouped := make(map[int64][]entity.Field)
+	for _, field := range fields {
+		if err = field.Decrypt(privateKey); err != nil {
+			return nil, err
+		}
+		grouped[field.PasswordId] = append(grouped[field.PasswordId], field)
+	}
+	return grouped, nil
+}
+
+// loadURLs returns the decrypted URLs grouped by password.
+func loadURLs(
+	db *sqlx.DB,
+	privateKey *rsa.PrivateKey,
+) (map[int64][]entity.URL, error) {
+	var urls []entity.URL
+	err := db.Select(
+		&urls,
+		`SELECT id, password_id, url, match_rule, position
+		FROM password_urls
+		ORDER BY password_id, position`,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	grouped := make(map[int64][]entity.URL)
+	for _, url := range urls {
+		if err = url.Decrypt(privateKey); err != nil {
+			return nil, err
+		}
+		grouped[url.PasswordId] = append(grouped[url.PasswordId], url)
+	}
+	return grouped, nil
+}
+
+// loadTags returns the tag names grouped by password.
+func loadTags(db *sqlx.DB) (map[int64][]string, error) {
+	rows, err := db.Queryx(
+		`SELECT password_tags.password_id, tags.name
+		FROM password_tags JOIN tags ON tags.id = password_tags.tag_id
+		ORDER BY tags.name`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	grouped := make(map[int64][]string)
+	for rows.Next() {
+		var (
+			passwordId int64
+			name       string
+		)
+		if err = rows.Scan(&passwordId, &name); err != nil {
+			return nil, err
+		}
+		grouped[passwordId] = append(grouped[passwordId], name)
+	}
+	return grouped, rows.Err()
+}
@@ -0,0 +1,75 @@
+// Package vault reads the unlocked vault, shared by the terminal
+// user interface and the command line interface.
+package vault
+
+import (
+	"encoding/hex"
+	"errors"
+
+	"viscue/tui/tool/cache"
+	"viscue/tui/tool/crypto"
+	"viscue/tui/tool/keychain"
+
+	"github.com/charmbracelet/log"
+	"github.com/jmoiron/sqlx"
+)
+
+// Unlock authenticates the user and loads the decrypted keys
+// into the cache.
+func Unlock(db *sqlx.DB, username, password string) error {
+	var hashedPassword string
+	err := db.QueryRowx(
+		"SELECT value FROM configurations WHERE key = ?", "password").
+		Scan(&hashedPassword)
+	if err != nil {
+		log.Error("failed querying password from database", "err", err)
+		return errors.New("failed querying password from database")
+	}
+
+	match, err := crypto.MatchPassword(password, hashedPassword)
+	if err != nil {
+		return err
+	} else if !match {
+		return errors.New("authentication failed password mismatched")
+	}
+
+	sc, err := keychain.Get(crypto.SecretKeyStorageName, username)
+	if err != nil {
+		log.Error("failed to find secret key in keyring", "err", err)
+		return errors.New("secret key was not found")
+	}
+
+	auc, err := crypto.GenerateAccountUnlockKey(password, sc, username)
+	if err != nil {
+		log.Error("failed to generate account unlock key", "err", err)
+		return errors.New("failed generating account unlock key")
+	}
+
+	var encodedEncryptedPrivateKey string
+	err = db.QueryRowx("SELECT value FROM configurations WHERE key = ?",
+		"encrypted_private_key").Scan(&encodedEncryptedPrivateKey)
+	if err != nil {
+		log.Error("failed querying encrypted private key from database", "err",
+			err)
+		return errors.New("failed querying encrypted private key from database")
+	}
+
+	encryptedPrivateKey, err := hex.DecodeString(encodedEncryptedPrivateKey)
+	if err != nil {
+		log.Error("failed decoding encrypted private key", "err", err)
+		return errors.New("failed decoding encrypted private key")
+	}
+
+	privateKey, err := crypto.DecryptRsaKey(encryptedPrivateKey, auc)
+	if err != nil {
+		log.Error("failed decrypting private key", "err", err)
+		return errors.New("failed decrypting private key")
+	}
+
+	// Store necessary values in cache
+	cache.Set(cache.AccountUnlockKey, auc)
+	cache.Set(cache.PrivateKey, privateKey)
+	cache.Set(cache.PublicKey, &privateKey.PublicKey)
+
+	return nil
+}
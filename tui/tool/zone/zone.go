@@ -0,0 +1,185 @@
+// Package zone finds where parts of the view end up on the screen,
+// for mouse events to be matched against them. Parts are marked with
+// zero-width escape sequences while rendering, which Scan records and
+// strips once the whole view has been rendered.
+package zone
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// DoubleClickInterval is the longest time between the two
+// clicks of a double click.
+const DoubleClickInterval = 400 * time.Millisecond
+
+// Zone is the area a marked part covers, assuming the part is a
+// block. The start is its first cell and line, and the end is the
+// column and the line right after its last ones, as with slices.
+type Zone struct {
+	StartX, StartY int
+	EndX, EndY     int
+}
+
+// InBounds reports whether the mouse is within the zone.
+func (z Zone) InBounds(msg tea.MouseMsg) bool {
+	return msg.X >= z.StartX && msg.X < z.EndX &&
+		msg.Y >= z.StartY && msg.Y < z.EndY
+}
+
+// Pos returns the position of the mouse relative to the zone.
+func (z Zone) Pos(msg tea.MouseMsg) (x, y int) {
+	return msg.X - z.StartX, msg.Y - z.StartY
+}
+
+var (
+	mutex   sync.RWMutex
+	numbers = make(map[string]int) // id to the number of its markers
+	ids     []string               // number to id
+	zones   = make(map[string]Zone)
+	prefix  int
+
+	markerPattern = regexp.MustCompile(`\x1b\[(\d+);([12])z`)
+)
+
+// NewPrefix returns a prefix unique to the caller, for the ids of
+// a component not to clash with the ones of another instance.
+func NewPrefix() string {
+	mutex.Lock()
+	defer mutex.Unlock()
+	prefix++
+	return "zone" + strconv.Itoa(prefix) + ":"
+}
+
+// Mark surrounds the rendered part with the markers of the id.
+func Mark(id, s string) string {
+	mutex.Lock()
+	number, ok := numbers[id]
+	if !ok {
+		number = len(ids)
+		numbers[id] = number
+		ids = append(ids, id)
+	}
+	mutex.Unlock()
+	return fmt.Sprintf("\x1b[%d;1z%s\x1b[%d;2z", number, s, number)
+}
+
+// Scan records where the marked parts of the view are, forgetting
+// the ones of the previous view, and returns it without markers.
+func Scan(view string) string {
+	type position struct{ x, y int }
+	starts := make(map[int]position)
+	found := make(map[string]Zone)
+
+	mutex.RLock()
+	for y, line := range strings.Split(view, "\n") {
+		x, previous := 0, 0
+		for _, match := range markerPattern.FindAllStringSubmatchIndex(line, -1) {
+			x += ansi.StringWidth(line[previous:match[0]])
+			previous = match[1]
+			number, _ := strconv.Atoi(line[match[2]:match[3]])
+			if number >= len(ids) {
+				continue
+			}
+			if line[match[4]:match[5]] == "1" {
+				if _, ok := starts[number]; !ok {
+					starts[number] = position{x, y}
+				}
+				continue
+			}
+			// An end without its start has been cut out of the view
+			start, ok := starts[number]
+			if _, done := found[ids[number]]; ok && !done {
+				found[ids[number]] = Zone{
+					StartX: start.x, StartY: start.y, EndX: x, EndY: y + 1,
+				}
+			}
+		}
+	}
+	mutex.RUnlock()
+
+	mutex.Lock()
+	zones = found
+	mutex.Unlock()
+	return markerPattern.ReplaceAllString(view, "")
+}
+
+// Get returns the zone of the id, if it was part of the last view.
+func Get(id string) (Zone, bool) {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	zone, ok := zones[id]
+	return zone, ok
+}
+
+// Hit returns the zone of the id when the mouse is within it.
+func Hit(id string, msg tea.MouseMsg) (Zone, bool) {
+	zone, ok := Get(id)
+	return zone, ok && zone.InBounds(msg)
+}
+
+// Clip removes the markers of the line outside the cells from and to,
+// both included, leaving everything else as is. It keeps parts drawn
+// over the line from carrying the markers underneath, as truncating
+// keeps every escape sequence of the line.
+func Clip(line string, from, to int) string {
+	var b strings.Builder
+	x, previous := 0, 0
+	for _, match := range markerPattern.FindAllStringIndex(line, -1) {
+		x += ansi.StringWidth(line[previous:match[0]])
+		b.WriteString(line[previous:match[0]])
+		if x >= from && x <= to {
+			b.WriteString(line[match[0]:match[1]])
+		}
+		previous = match[1]
+	}
+	b.WriteString(line[previous:])
+	return b.String()
+}
+
+// Clicks tells double clicks apart, i.e. two clicks
+// on the same target within DoubleClickInterval.
+type Clicks struct {
+	target string
+	at     time.Time
+}
+
+// Register records a click on the target, reporting whether
+// it completes a double click.
+func (c *Clicks) Register(target string) bool {
+	now := time.Now()
+	double := target == c.target && now.Sub(c.at) <= DoubleClickInterval
+	if double {
+		// A third click starts over
+		c.target = ""
+	} else {
+		c.target, c.at = target, now
+	}
+	return double
+}
+
+// IsClick reports whether the message is a press of the left button.
+func IsClick(msg tea.MouseMsg) bool {
+	return msg.Action == tea.MouseActionPress && msg.Button == tea.MouseButtonLeft
+}
+
+// IsWheel reports whether the message scrolls the wheel, and which way.
+func IsWheel(msg tea.MouseMsg) (up, ok bool) {
+	if msg.Action != tea.MouseActionPress {
+		return false, false
+	}
+	switch msg.Button {
+	case tea.MouseButtonWheelUp:
+		return true, true
+	case tea.MouseButtonWheelDown:
+		return false, true
+	}
+	return false, false
+}
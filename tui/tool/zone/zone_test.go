@@ -0,0 +1,40 @@
+package zone
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestScanInBounds(t *testing.T) {
+	id := NewPrefix() + "block"
+	view := "header\n  " + Mark(id, "abc\n  def") + "  \nfooter"
+	if got, want := Scan(view), "header\n  abc\n  def  \nfooter"; got != want {
+		t.Fatalf("Scan() = %q, want %q", got, want)
+	}
+
+	z, ok := Get(id)
+	if !ok {
+		t.Fatal("zone not found")
+	}
+	if want := (Zone{StartX: 2, StartY: 1, EndX: 5, EndY: 3}); z != want {
+		t.Errorf("zone = %+v, want %+v", z, want)
+	}
+
+	tests := []struct {
+		x, y int
+		want bool
+	}{
+		{2, 1, true},
+		{4, 2, true},
+		{1, 1, false},
+		{5, 2, false},
+		{2, 0, false},
+		{2, 3, false},
+	}
+	for _, tt := range tests {
+		if got := z.InBounds(tea.MouseMsg{X: tt.x, Y: tt.y}); got != tt.want {
+			t.Errorf("InBounds(%d, %d) = %v, want %v", tt.x, tt.y, got, tt.want)
+		}
+	}
+}
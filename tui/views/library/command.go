@@ -0,0 +1,221 @@
+package library
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"viscue/tui/component/notification"
+	"viscue/tui/tool/cache"
+	"viscue/tui/tool/crypto"
+	"viscue/tui/tool/settings"
+	"viscue/tui/tool/undo"
+	"viscue/tui/views/library/message"
+	"viscue/tui/views/library/submodel/notifications"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/log"
+	"golang.design/x/clipboard"
+)
+
+// clipboardNotificationKey lets the countdown steps replace each
+// other, in the toast as in the history, rather than queue up.
+const clipboardNotificationKey = "clipboard"
+
+// themeNotificationKey keeps a single notification about
+// the theme when the theme file is saved several times.
+const themeNotificationKey = "theme"
+
+// clipboardState tracks the value waiting to be cleared from the clipboard.
+type clipboardState struct {
+	value     string
+	remaining int
+	// generation increases on every copy, so the ticks of an
+	// earlier countdown are told apart and dropped
+	generation int
+}
+
+// ClipboardTickMsg counts one second down on the clipboard countdown.
+type ClipboardTickMsg struct {
+	generation int
+}
+
+func clipboardTick(generation int) tea.Cmd {
+	return tea.Tick(time.Second, func(time.Time) tea.Msg {
+		return ClipboardTickMsg{generation: generation}
+	})
+}
+
+// startClipboardCountdown notifies about the copy and, unless the
+// delay is disabled, starts counting down to clearing the clipboard.
+func (m *Model) startClipboardCountdown(msg message.CopiedMsg) tea.Cmd {
+	m.clipboard.generation++
+	delay := settings.ClipboardClearDelay.Get(m.db)
+	if delay <= 0 {
+		m.clipboard.value = ""
+		m.clipboard.remaining = 0
+		return m.notification.Show(notification.ShowMsg{
+			Message: msg.Label + " is copied to clipboard",
+			Level:   notification.LevelSuccess,
+		})
+	}
+	m.clipboard.value = msg.Value
+	m.clipboard.remaining = delay
+	return tea.Batch(
+		m.notification.Show(notification.ShowMsg{
+			Message: msg.Label + " is copied, clearing clipboard in " +
+				strconv.Itoa(delay) + "s",
+			Level: notification.LevelSuccess,
+		}),
+		clipboardTick(m.clipboard.generation),
+	)
+}
+
+// tickClipboardCountdown moves the countdown one second forward and
+// clears the clipboard once it is over, provided that it still holds
+// the copied value.
+func (m *Model) tickClipboardCountdown(msg ClipboardTickMsg) tea.Cmd {
+	if msg.generation != m.clipboard.generation || m.clipboard.remaining <= 0 {
+		return nil
+	}
+	m.clipboard.remaining--
+	if m.clipboard.remaining > 0 {
+		return tea.Batch(
+			m.notification.Show(notification.ShowMsg{
+				Message: "Clipboard clears in " +
+					strconv.Itoa(m.clipboard.remaining) + "s",
+				Key: clipboardNotificationKey,
+			}),
+			clipboardTick(msg.generation),
+		)
+	}
+
+	value := m.clipboard.value
+	m.clipboard.value = ""
+	if !bytes.Equal(clipboard.Read(clipboard.FmtText), []byte(value)) {
+		// Something else has been copied since, leave it alone
+		return nil
+	}
+	clipboard.Write(clipboard.FmtText, []byte{})
+	return func() tea.Msg {
+		return notification.ShowMsg{
+			Message: "Clipboard cleared",
+			Key:     clipboardNotificationKey,
+		}
+	}
+}
+
+// syncNotifications keeps the notifications panel, when
+// open, in line with the history.
+func (m *Model) syncNotifications() {
+	if panel, ok := m.panel.(notifications.Model); ok {
+		panel.SetEntries(m.notification.History())
+		m.panel = panel
+	}
+}
+
+// autoLockInterval is how often the library checks for how long it
+// has been left idle, so that a shorter timeout is soon followed.
+const autoLockInterval = 30 * time.Second
+
+// AutoLockTickMsg asks the library to check whether it has been
+// left idle for longer than the auto-lock timeout.
+type AutoLockTickMsg struct {
+	openedAt time.Time
+}
+
+func autoLockTick(openedAt time.Time, delay time.Duration) tea.Cmd {
+	return tea.Tick(delay, func(time.Time) tea.Msg {
+		return AutoLockTickMsg{openedAt: openedAt}
+	})
+}
+
+// checkAutoLock locks the library once it has been idle for
+// the configured timeout, or checks again later.
+func (m Model) checkAutoLock() tea.Cmd {
+	timeout := time.Duration(settings.AutoLockTimeout.Get(m.db)) * time.Minute
+	if timeout <= 0 {
+		return autoLockTick(m.openedAt, autoLockInterval)
+	}
+	idle := time.Since(m.lastActivity)
+	if idle >= timeout {
+		return m.Lock
+	}
+	return autoLockTick(m.openedAt, min(timeout-idle, autoLockInterval))
+}
+
+// Locked is an event when the user has locked the library,
+// asking to log in again.
+type Locked struct{}
+
+// Lock forgets the keys of the account, along with the copied value
+// still waiting on the clipboard, and locks the library.
+func (m Model) Lock() tea.Msg {
+	if m.clipboard.value != "" &&
+		bytes.Equal(clipboard.Read(clipboard.FmtText), []byte(m.clipboard.value)) {
+		clipboard.Write(clipboard.FmtText, []byte{})
+	}
+	cache.Delete(cache.AccountUnlockKey, cache.PrivateKey, cache.PublicKey)
+	undo.Clear()
+	return Locked{}
+}
+
+// UndoneMsg tells that a change has been reverted or done again,
+// so that the panes reload what it touched.
+type UndoneMsg struct {
+	Message string
+}
+
+// Undo reverts the last change to the passwords or the categories.
+func (m Model) Undo() tea.Msg {
+	label, err := undo.Undo(m.db)
+	return undone("Undid", "Failed undoing", label, err)
+}
+
+// Redo does again the last undone change.
+func (m Model) Redo() tea.Msg {
+	label, err := undo.Redo(m.db)
+	return undone("Redid", "Failed redoing", label, err)
+}
+
+// undone tells how the change went, the label starting with its verb.
+func undone(done, failed, label string, err error) tea.Msg {
+	switch {
+	case errors.Is(err, undo.ErrNothingToUndo):
+		return notification.ShowMsg{Message: "Nothing to undo"}
+	case errors.Is(err, undo.ErrNothingToRedo):
+		return notification.ShowMsg{Message: "Nothing to redo"}
+	}
+
+	if label != "" {
+		label = " " + strings.ToLower(label[:1]) + label[1:]
+	}
+	if err != nil {
+		// Such as another item having taken the name since
+		return notification.ShowMsg{
+			Message: failed + label,
+			Level:   notification.LevelError,
+		}
+	}
+	return UndoneMsg{Message: done + label}
+}
+
+// Generate copies a new random password to the clipboard.
+func (m Model) Generate() tea.Msg {
+	password, err := crypto.GeneratePassword(
+		settings.GeneratorLength.Get(m.db),
+		settings.GeneratorDigits.Get(m.db),
+		settings.GeneratorSymbols.Get(m.db),
+	)
+	if err != nil {
+		log.Error("library.(Model).Generate: failed", "err", err)
+		return notification.ShowMsg{
+			Message: "Failed generating password",
+			Level:   notification.LevelError,
+		}
+	}
+	clipboard.Write(clipboard.FmtText, []byte(password))
+	return message.CopiedMsg{Label: "Generated password", Value: password}
+}
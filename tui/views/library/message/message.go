@@ -12,10 +12,18 @@ var (
 	SidebarFocused = SwitchFocusMsg(0)
 	ShelfFocused   = SwitchFocusMsg(1)
 	PromptFocused  = SwitchFocusMsg(2)
+	PanelFocused   = SwitchFocusMsg(3)
 )
 
 type ShouldReloadMsg struct{}
 
+// UndoMsg asks the library to revert the last change
+// to the passwords or the categories.
+type UndoMsg struct{}
+
+// RedoMsg asks the library to do again the last undone change.
+type RedoMsg struct{}
+
 type OpenPromptMsg[T interface {
 	entity.Category | entity.Password
 }] struct {
@@ -23,6 +31,25 @@ type OpenPromptMsg[T interface {
 	IsDeletion bool
 }
 
+// BulkAction is what is done at once to the marked passwords.
+type BulkAction int
+
+const (
+	BulkMove BulkAction = iota
+	BulkTag
+	BulkDelete // to the trash, or out of it for good
+	BulkRestore
+	BulkExport
+	BulkRegenerate
+)
+
+// OpenBulkPromptMsg asks the library to confirm the
+// action about to be done to the passwords.
+type OpenBulkPromptMsg struct {
+	Action    BulkAction
+	Passwords []entity.Password
+}
+
 type ClosePromptMsg[T interface {
 	entity.Category | entity.Password
 }] struct{}
@@ -30,11 +57,67 @@ type ClosePromptMsg[T interface {
 // CategorySelectedMsg passes the category ID of
 // the currently selected (first) in the list. It
 // could be nil because during filter, it is possible
-// that no category is selected at all.
-type CategorySelectedMsg *int64
+// that no category is selected at all. Descendants
+// holds the categories nested under it, whose
+// passwords are shown as well.
+type CategorySelectedMsg struct {
+	Id          *int64
+	Descendants []int64
+}
 
 type SetHelpKeysMsg struct {
 	Keys help.KeyMap
 }
 
 type ClearFilter struct{}
+
+// TagsSelectedMsg passes the names of the tags selected in the
+// sidebar. Passwords need any of them, or all of them when
+// MatchAll is set. No tags means no filtering at all.
+type TagsSelectedMsg struct {
+	Tags     []string
+	MatchAll bool
+}
+
+// OpenBreachReportMsg asks the library to show the passwords
+// found in a breach along with how many times each was seen.
+type OpenBreachReportMsg struct {
+	Passwords []entity.Password
+	Counts    map[int64]int
+}
+
+// OpenHistoryMsg asks the library to show the
+// previous values of the given password.
+type OpenHistoryMsg struct {
+	Payload entity.Password
+}
+
+// OpenDetailMsg asks the library to show every
+// field of the given password.
+type OpenDetailMsg struct {
+	Payload entity.Password
+}
+
+// OpenNotificationsMsg asks the library to list
+// the notifications shown so far.
+type OpenNotificationsMsg struct{}
+
+// OpenSettingsMsg asks the library to show the settings.
+type OpenSettingsMsg struct{}
+
+// OpenAccountMsg asks the library to show the account.
+type OpenAccountMsg struct{}
+
+// AccountDeletedMsg tells the library that the account is gone
+// along with the whole vault, so it has to be locked.
+type AccountDeletedMsg struct{}
+
+// ClosePanelMsg closes the panel currently shown by the library.
+type ClosePanelMsg struct{}
+
+// CopiedMsg tells that a value has been written to the clipboard,
+// which the library clears once the configured delay is over.
+type CopiedMsg struct {
+	Label string
+	Value string
+}
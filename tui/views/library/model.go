@@ -1,14 +1,27 @@
 package library
 
 import (
+	"time"
+
+	"viscue/tui/component/notification"
 	"viscue/tui/entity"
 	"viscue/tui/style"
+	"viscue/tui/tool/cache"
+	"viscue/tui/tool/theme"
 	"viscue/tui/views/library/message"
+	"viscue/tui/views/library/submodel/account"
+	"viscue/tui/views/library/submodel/breach"
+	"viscue/tui/views/library/submodel/detail"
+	"viscue/tui/views/library/submodel/history"
+	"viscue/tui/views/library/submodel/notifications"
+	"viscue/tui/views/library/submodel/palette"
+	"viscue/tui/views/library/submodel/preferences"
 	"viscue/tui/views/library/submodel/prompt"
 	"viscue/tui/views/library/submodel/shelf"
 	"viscue/tui/views/library/submodel/sidebar"
 
 	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/jmoiron/sqlx"
@@ -19,11 +32,14 @@ type Model struct {
 
 	// Submodels
 	prompt  tea.Model
+	panel   tea.Model // holds secondary views, e.g. the breach report
+	palette tea.Model // drawn over the rest while open
 	sidebar tea.Model
 	shelf   tea.Model
 
 	// Component
-	help help.Model
+	help         help.Model
+	notification notification.Model
 
 	// States
 	keys help.KeyMap
@@ -31,7 +47,11 @@ type Model struct {
 	// `0` indicates sidebar
 	// `1` indicates shelf
 	// `2` indicates prompt
+	// `3` indicates panel
 	focusedSubmodel int8
+	clipboard       clipboardState
+	lastActivity    time.Time // of the user, for the auto-lock
+	openedAt        time.Time // tells the auto-lock ticks of earlier sessions apart
 }
 
 func New(db *sqlx.DB) tea.Model {
@@ -41,6 +61,16 @@ func New(db *sqlx.DB) tea.Model {
 		sidebar:         sidebar.New(db),
 		help:            help.New(),
 		focusedSubmodel: 1,
+		lastActivity:    time.Now(),
+		openedAt:        time.Now(),
+		// A slim box drawn over the bottom right corner of the panes
+		notification: notification.New(
+			notification.WithDuration(2*time.Second),
+			notification.WithStyle(lipgloss.NewStyle().
+				Border(lipgloss.RoundedBorder()).
+				BorderForeground(style.ColorAccent).
+				Padding(0, 2)),
+		),
 	}
 	m.help.ShowAll = true
 
@@ -54,16 +84,53 @@ func (m Model) Init() tea.Cmd {
 		},
 		m.shelf.Init(),
 		m.sidebar.Init(),
+		autoLockTick(m.openedAt, autoLockInterval),
 	)
 }
 
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if mouseMsg, ok := msg.(tea.MouseMsg); ok {
+		m.lastActivity = time.Now()
+		if m.palette != nil {
+			// The palette covers what the mouse points at
+			return m, nil
+		}
+		if b, ok := m.sidebar.(sidebar.Model); ok && style.IsCompact() &&
+			m.prompt == nil && m.panel == nil && b.Focused() && b.Covers(mouseMsg) {
+			// The dropdown covers the shelf underneath
+			var cmd tea.Cmd
+			m.sidebar, cmd = m.sidebar.Update(msg)
+			return m, cmd
+		}
+	}
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		m.lastActivity = time.Now()
+		if m.palette != nil {
+			// The palette takes every key while open
+			var cmd tea.Cmd
+			m.palette, cmd = m.palette.Update(keyMsg)
+			return m, cmd
+		}
+		if m.prompt == nil && (key.Matches(keyMsg, shelf.Keys.Palette) ||
+			key.Matches(keyMsg, sidebar.Keys.Palette)) {
+			m.palette = palette.New(m.paletteEntries())
+			return m, m.palette.Init()
+		}
+	}
+
 	switch msg := msg.(type) {
+	case palette.ClosedMsg:
+		m.palette = nil
+		return m, nil
 	case message.SwitchFocusMsg:
 		m.focusedSubmodel = int8(msg)
 	case message.OpenPromptMsg[entity.Password]:
+		m.panel = nil
 		m.prompt = prompt.New(m.db, msg.Payload,
 			prompt.IsDeletion(msg.IsDeletion))
+	case message.OpenBulkPromptMsg:
+		m.panel = nil
+		m.prompt = prompt.NewBulk(m.db, msg.Action, msg.Passwords)
 	case message.OpenPromptMsg[entity.Category]:
 		m.prompt = prompt.New(m.db, msg.Payload,
 			prompt.IsDeletion(msg.IsDeletion))
@@ -79,25 +146,100 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		)
 	case message.ClosePromptMsg[entity.Password]:
 		m.prompt = nil
-		return m, tea.Sequence(
-			func() tea.Msg {
-				return message.ShelfFocused
-			},
+		// The shelf sets its keys once focused, depending on the marks
+		return m, func() tea.Msg {
+			return message.ShelfFocused
+		}
+	case message.OpenBreachReportMsg:
+		m.panel = breach.New(msg.Passwords, msg.Counts)
+		return m, m.panel.Init()
+	case message.OpenHistoryMsg:
+		m.panel = history.New(m.db, msg.Payload)
+		return m, m.panel.Init()
+	case message.OpenDetailMsg:
+		m.panel = detail.New(m.db, msg.Payload)
+		return m, m.panel.Init()
+	case message.ClosePanelMsg:
+		m.panel = nil
+		return m, func() tea.Msg {
+			return message.ShelfFocused
+		}
+	case message.SetHelpKeysMsg:
+		m.keys = msg.Keys
+		return m, nil
+	case message.UndoMsg:
+		return m, m.Undo
+	case message.RedoMsg:
+		return m, m.Redo
+	case UndoneMsg:
+		return m, tea.Batch(
+			func() tea.Msg { return message.ShouldReloadMsg{} },
 			func() tea.Msg {
-				return message.SetHelpKeysMsg{Keys: shelf.Keys}
+				return notification.ShowMsg{
+					Message: msg.Message,
+					Level:   notification.LevelInfo,
+				}
 			},
 		)
-	case message.SetHelpKeysMsg:
-		m.keys = msg.Keys
+	case message.OpenNotificationsMsg:
+		m.panel = notifications.New(m.notification.History())
+		return m, m.panel.Init()
+	case message.OpenSettingsMsg:
+		m.panel = preferences.New(m.db)
+		return m, m.panel.Init()
+	case message.OpenAccountMsg:
+		m.panel = account.New(m.db)
+		return m, m.panel.Init()
+	case message.AccountDeletedMsg:
+		return m, tea.Batch(m.Lock, func() tea.Msg {
+			// The theme setting is gone along with the others
+			current, err := theme.Load("")
+			return theme.ChangedMsg{Theme: current, Err: err}
+		})
+	case notifications.ClearedMsg:
+		m.notification.ClearHistory()
+		m.syncNotifications()
 		return m, nil
+	case notification.ShowMsg:
+		cmd := m.notification.Show(msg)
+		m.syncNotifications()
+		return m, cmd
+	case notification.TickMsg:
+		var cmd tea.Cmd
+		m.notification, cmd = m.notification.Update(msg)
+		return m, cmd
+	case message.CopiedMsg:
+		return m, m.startClipboardCountdown(msg)
+	case ClipboardTickMsg:
+		return m, m.tickClipboardCountdown(msg)
+	case AutoLockTickMsg:
+		if !msg.openedAt.Equal(m.openedAt) {
+			return m, nil
+		}
+		return m, m.checkAutoLock()
+	case theme.ChangedMsg:
+		show := notification.ShowMsg{
+			Message: "Theme " + msg.Theme.Name + " applied",
+			Key:     themeNotificationKey,
+		}
+		if msg.Err != nil {
+			show.Message = "Theme not reloaded: " + msg.Err.Error()
+			show.Level = notification.LevelError
+		}
+		cmd := m.notification.Show(show)
+		m.syncNotifications()
+		return m, cmd
 	}
 
-	cmds := make([]tea.Cmd, 3)
+	cmds := make([]tea.Cmd, 4)
 	if m.prompt != nil {
 		m.prompt, cmds[0] = m.prompt.Update(msg)
 	}
-	m.sidebar, cmds[1] = m.sidebar.Update(msg)
-	m.shelf, cmds[2] = m.shelf.Update(msg)
+	if m.panel != nil {
+		m.panel, cmds[1] = m.panel.Update(msg)
+	}
+	m.sidebar, cmds[2] = m.sidebar.Update(msg)
+	m.shelf, cmds[3] = m.shelf.Update(msg)
 
 	return m, tea.Batch(cmds...)
 }
@@ -106,6 +248,10 @@ func (m Model) View() string {
 	var submodelView string
 	if m.prompt != nil {
 		submodelView = m.prompt.View()
+	} else if m.panel != nil {
+		submodelView = m.panel.View()
+	} else if style.IsCompact() {
+		submodelView = m.compactView()
 	} else {
 		submodelView = lipgloss.JoinHorizontal(
 			lipgloss.Top,
@@ -114,22 +260,29 @@ func (m Model) View() string {
 		)
 	}
 
+	// Columns that do not fit are cut rather than wrapped
+	m.help.Width = cache.Get[int](cache.TerminalWidth)
+	if style.IsCompact() {
+		m.help.ShowAll = false // a single line is left for the help
+	}
 	var helpView string
-	if m.keys != nil {
+	if m.palette != nil {
+		helpView = style.HelpContainer(m.help.View(palette.Keys))
+	} else if m.keys != nil {
 		helpView = style.HelpContainer(m.help.View(m.keys))
 	} else {
 		switch m.focusedSubmodel {
 		case 0:
-			helpView = style.HelpContainer(help.New().View(sidebar.Keys))
+			helpView = style.HelpContainer(m.help.View(sidebar.Keys))
 		case 1:
-			helpView = style.HelpContainer(help.New().View(shelf.Keys))
+			helpView = style.HelpContainer(m.help.View(shelf.Keys))
 		case 2:
-			helpView = style.HelpContainer(help.New().View(prompt.BaseKeys))
+			helpView = style.HelpContainer(m.help.View(prompt.BaseKeys))
 		}
 	}
 	return lipgloss.JoinVertical(
 		lipgloss.Center,
-		submodelView,
+		m.notification.Overlay(m.overlayPalette(submodelView)),
 		helpView,
 	)
 }
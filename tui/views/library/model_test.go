@@ -0,0 +1,138 @@
+package library
+
+import (
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"viscue/tui/entity"
+	"viscue/tui/tool/cache"
+	"viscue/tui/tool/database"
+	"viscue/tui/views/library/message"
+	"viscue/tui/views/library/submodel/shelf"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// settle runs the commands the way the program would, feeding the
+// messages back to the model until none is left. Commands taking
+// longer than a moment, such as ticks, are dropped.
+func settle(t *testing.T, m tea.Model, msg tea.Msg) (tea.Model, []tea.Msg) {
+	t.Helper()
+	var seen []tea.Msg
+	queue := []tea.Msg{msg}
+	for len(queue) > 0 && len(seen) < 500 {
+		msg, queue = queue[0], queue[1:]
+		if msg == nil {
+			continue
+		}
+		if cmds, ok := commands(msg); ok {
+			queue = append(queue, runAll(cmds)...)
+			continue
+		}
+		seen = append(seen, msg)
+
+		var cmd tea.Cmd
+		m, cmd = m.Update(msg)
+		queue = append(queue, runAll([]tea.Cmd{cmd})...)
+	}
+	return m, seen
+}
+
+// commands unwraps batches and sequences, the latter having an
+// unexported type.
+func commands(msg tea.Msg) ([]tea.Cmd, bool) {
+	v := reflect.ValueOf(msg)
+	if v.Kind() != reflect.Slice ||
+		v.Type().Elem() != reflect.TypeOf((*tea.Cmd)(nil)).Elem() {
+		return nil, false
+	}
+	cmds := make([]tea.Cmd, v.Len())
+	for i := range cmds {
+		cmds[i], _ = v.Index(i).Interface().(tea.Cmd)
+	}
+	return cmds, true
+}
+
+func runAll(cmds []tea.Cmd) []tea.Msg {
+	var msgs []tea.Msg
+	for _, cmd := range cmds {
+		if cmd == nil {
+			continue
+		}
+		done := make(chan tea.Msg, 1)
+		go func() { done <- cmd() }()
+		select {
+		case msg := <-done:
+			msgs = append(msgs, msg)
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+	return msgs
+}
+
+func keyPress(keys string) tea.KeyMsg {
+	switch keys {
+	case "enter":
+		return tea.KeyMsg{Type: tea.KeyEnter}
+	case "ctrl+k":
+		return tea.KeyMsg{Type: tea.KeyCtrlK}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(keys)}
+}
+
+func newLibrary(t *testing.T) tea.Model {
+	t.Helper()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+	t.Setenv("local_db", "1")
+
+	db, err := database.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	cache.Set(cache.TerminalWidth, 120)
+	cache.Set(cache.TerminalHeight, 40)
+
+	m, _ := settle(t, New(db), message.ShelfFocused)
+	m, _ = settle(t, m, shelf.DataLoadedMsg{Data: []entity.Password{
+		{Id: 1, Name: "github", Type: entity.TypeLogin, Password: "secret"},
+	}})
+	return m
+}
+
+// Panels opened from the palette take the focus, so that the keys
+// meant for them never reach the shelf underneath.
+func TestPalettePanelTakesFocus(t *testing.T) {
+	m := newLibrary(t)
+	for _, title := range []string{"Settings", "Account", "Notifications"} {
+		t.Run(title, func(t *testing.T) {
+			m, _ := settle(t, m, keyPress("ctrl+k"))
+			for _, r := range title {
+				m, _ = settle(t, m, keyPress(string(r)))
+			}
+			m, _ = settle(t, m, keyPress("enter"))
+			if m.(Model).panel == nil {
+				t.Fatalf("%s was not opened", title)
+			}
+
+			m, msgs := settle(t, m, keyPress("enter"))
+			for _, msg := range msgs {
+				if _, ok := msg.(message.OpenPromptMsg[entity.Password]); ok {
+					t.Fatal("enter reached the shelf, which opened the edit prompt")
+				}
+			}
+			if m.(Model).prompt != nil || m.(Model).panel == nil {
+				t.Errorf("%s was replaced by a prompt", title)
+			}
+		})
+	}
+}
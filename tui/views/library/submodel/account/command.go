@@ -0,0 +1,278 @@
+package account
+
+import (
+	"crypto/rsa"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"viscue/tui/component/notification"
+	"viscue/tui/tool/cache"
+	"viscue/tui/tool/crypto"
+	"viscue/tui/tool/database"
+	"viscue/tui/tool/keychain"
+	"viscue/tui/tool/settings"
+	"viscue/tui/views/library/message"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/log"
+	"github.com/jmoiron/sqlx"
+)
+
+// services are the secrets of the account kept in the keyring,
+// under the username.
+var services = []string{crypto.SecretKeyStorageName, crypto.SaltStorageName}
+
+// Info is what the panel shows of the account.
+type Info struct {
+	Username    string
+	Vault       string
+	CreatedAt   time.Time // zero for accounts created before it was kept
+	Backend     string
+	Fingerprint string
+}
+
+type LoadedMsg Info
+
+type RenamedMsg struct {
+	Username string
+}
+
+type ErrorMsg error
+
+func (m Model) SendSetKeysMsg() tea.Msg {
+	return message.SetHelpKeysMsg{Keys: Keys}
+}
+
+func (m Model) Close() tea.Msg {
+	return message.ClosePanelMsg{}
+}
+
+func (m Model) Load() tea.Msg {
+	var rows []struct {
+		Key   string `db:"key"`
+		Value string `db:"value"`
+	}
+	err := m.db.Select(&rows,
+		"SELECT key, value FROM configurations WHERE key IN (?, ?)",
+		"username", "created_at")
+	if err != nil {
+		log.Error("account.(Model).Load: failed querying account", "err", err)
+		return ErrorMsg(errors.New("failed loading account"))
+	}
+
+	info := Info{Backend: settings.KeyringBackend.Get(m.db)}
+	for _, row := range rows {
+		switch row.Key {
+		case "username":
+			info.Username = row.Value
+		case "created_at":
+			info.CreatedAt, err = time.Parse(time.RFC3339, row.Value)
+			if err != nil {
+				log.Error("account.(Model).Load: malformed creation date",
+					"value", row.Value, "err", err)
+			}
+		}
+	}
+
+	if path, err := database.Path(); err == nil {
+		info.Vault, _ = filepath.Abs(path)
+	}
+	if key := cache.Get[*rsa.PublicKey](cache.PublicKey); key != nil {
+		info.Fingerprint, err = crypto.Fingerprint(key)
+		if err != nil {
+			log.Error("account.(Model).Load: failed fingerprinting key",
+				"err", err)
+		}
+	}
+	return LoadedMsg(info)
+}
+
+// Rename changes the username of the account. The secrets of the
+// keyring are kept under the username, which is also part of the
+// account unlock key, so the private key is encrypted again with
+// the key derived from the new username.
+func (m Model) Rename(username, password string) tea.Cmd {
+	current := m.info.Username
+	return func() tea.Msg {
+		username = strings.TrimSpace(username)
+		if username == "" {
+			return ErrorMsg(errors.New("username cannot be blank"))
+		} else if username == current {
+			return ErrorMsg(errors.New("username is unchanged"))
+		}
+		if err := authenticate(m.db, password); err != nil {
+			return ErrorMsg(err)
+		}
+		if err := rename(m.db, current, username, password); err != nil {
+			return ErrorMsg(err)
+		}
+		return RenamedMsg{Username: username}
+	}
+}
+
+func rename(db *sqlx.DB, current, username, password string) error {
+	privateKey := cache.Get[*rsa.PrivateKey](cache.PrivateKey)
+	if privateKey == nil {
+		return errors.New("account is locked")
+	}
+
+	sc, err := keychain.Get(crypto.SecretKeyStorageName, current)
+	if err != nil {
+		log.Error("account.rename: failed to find secret key in keyring",
+			"err", err)
+		return errors.New("secret key was not found")
+	}
+	salt, err := keychain.Get(crypto.SaltStorageName, current)
+	if err != nil {
+		log.Error("account.rename: failed to find salt in keyring", "err", err)
+		return errors.New("salt was not found")
+	}
+
+	auc, err := crypto.DeriveAccountUnlockKey(password, sc, salt, username)
+	if err != nil {
+		log.Error("account.rename: failed generating account unlock key",
+			"err", err)
+		return errors.New("failed generating account unlock key")
+	}
+	encPrivateKey, err := crypto.EncryptRsaKey(privateKey, auc)
+	if err != nil {
+		log.Error("account.rename: failed encrypting private key", "err", err)
+		return errors.New("failed encrypting private key")
+	}
+
+	tx, err := db.Beginx()
+	if err != nil {
+		log.Error("account.rename: failed to start transaction", "err", err)
+		return errors.New("something went wrong with sqlite database")
+	}
+
+	for key, value := range map[string]string{
+		"username":              username,
+		"encrypted_private_key": hex.EncodeToString(encPrivateKey),
+	} {
+		_, err = tx.Exec("UPDATE configurations SET value = ? WHERE key = ?",
+			value, key)
+		if err != nil {
+			log.Error("account.rename: failed updating configuration",
+				"key", key, "err", err)
+			_ = tx.Rollback()
+			return errors.New("failed saving account")
+		}
+	}
+
+	if err = keychain.Rename(current, username, services...); err != nil {
+		log.Error("account.rename: failed moving keyring entries", "err", err)
+		_ = tx.Rollback()
+		return errors.New("failed moving the secrets of the keyring")
+	}
+	if err = tx.Commit(); err != nil {
+		log.Error("account.rename: failed to commit transaction", "err", err)
+		if err := keychain.Rename(username, current, services...); err != nil {
+			log.Error("account.rename: failed moving keyring entries back",
+				"err", err)
+		}
+		_ = tx.Rollback()
+		return errors.New("failed saving account")
+	}
+
+	cache.Set(cache.AccountUnlockKey, auc)
+	return nil
+}
+
+// Delete erases every row of the vault and the secrets of the
+// account in the keyring, after which a new account can be created.
+func (m Model) Delete(password string) tea.Cmd {
+	username := m.info.Username
+	return func() tea.Msg {
+		if err := authenticate(m.db, password); err != nil {
+			return ErrorMsg(err)
+		}
+
+		tx, err := m.db.Beginx()
+		if err != nil {
+			log.Error("account.(Model).Delete: failed to start transaction",
+				"err", err)
+			return ErrorMsg(errors.New("something went wrong with sqlite database"))
+		}
+
+		var tables []string
+		err = tx.Select(&tables, `SELECT name FROM sqlite_master
+			WHERE type = 'table' AND name NOT LIKE 'sqlite_%'
+			AND name != 'schema_migrations'`)
+		if err != nil {
+			log.Error("account.(Model).Delete: failed listing tables", "err", err)
+			_ = tx.Rollback()
+			return ErrorMsg(errors.New("failed deleting account"))
+		}
+		// Foreign keys are checked once every table is empty
+		if _, err = tx.Exec("PRAGMA defer_foreign_keys = ON"); err != nil {
+			log.Error("account.(Model).Delete: failed deferring foreign keys",
+				"err", err)
+			_ = tx.Rollback()
+			return ErrorMsg(errors.New("failed deleting account"))
+		}
+		for _, table := range tables {
+			if _, err = tx.Exec(`DELETE FROM "` + table + `"`); err != nil {
+				log.Error("account.(Model).Delete: failed emptying table",
+					"table", table, "err", err)
+				_ = tx.Rollback()
+				return ErrorMsg(errors.New("failed deleting account"))
+			}
+		}
+		if err = tx.Commit(); err != nil {
+			log.Error("account.(Model).Delete: failed to commit transaction",
+				"err", err)
+			_ = tx.Rollback()
+			return ErrorMsg(errors.New("failed deleting account"))
+		}
+
+		if err = keychain.Wipe(username, services...); err != nil {
+			log.Error("account.(Model).Delete: failed wiping keyring entries",
+				"err", err)
+		}
+		// The keyring backend setting is gone along with the others
+		if err = keychain.Use(settings.KeyringBackend.Default); err != nil {
+			log.Error("account.(Model).Delete: failed resetting keyring",
+				"err", err)
+		}
+		return message.AccountDeletedMsg{}
+	}
+}
+
+// authenticate checks the password against the one of the account.
+func authenticate(db *sqlx.DB, password string) error {
+	if password == "" {
+		return errors.New("password is required")
+	}
+	var hashedPassword string
+	err := db.QueryRowx("SELECT value FROM configurations WHERE key = ?",
+		"password").Scan(&hashedPassword)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			log.Error("account.authenticate: failed querying password",
+				"err", err)
+		}
+		return errors.New("failed querying password from database")
+	}
+
+	match, err := crypto.MatchPassword(password, hashedPassword)
+	if err != nil {
+		return err
+	} else if !match {
+		return errors.New("authentication failed password mismatched")
+	}
+	return nil
+}
+
+func (m Model) notifyRenamed(msg RenamedMsg) tea.Cmd {
+	return func() tea.Msg {
+		return notification.ShowMsg{
+			Message: "Username changed to " + msg.Username,
+			Level:   notification.LevelSuccess,
+		}
+	}
+}
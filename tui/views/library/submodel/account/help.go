@@ -0,0 +1,60 @@
+package account
+
+import "github.com/charmbracelet/bubbles/key"
+
+type KeyMap struct {
+	Rename, Delete, Close key.Binding
+}
+
+func (k KeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Rename, k.Delete, k.Close}
+}
+
+func (k KeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.Rename, k.Delete}, // first column
+		{k.Close},            // second column
+	}
+}
+
+var Keys = KeyMap{
+	Rename: key.NewBinding(
+		key.WithKeys("r"),
+		key.WithHelp("r", "change username"),
+	),
+	Delete: key.NewBinding(
+		key.WithKeys("D"),
+		key.WithHelp("D", "delete account"),
+	),
+	Close: key.NewBinding(
+		key.WithKeys("esc", "q"),
+		key.WithHelp("esc", "close"),
+	),
+}
+
+type FormKeyMap struct {
+	Next, Submit, Cancel key.Binding
+}
+
+func (k FormKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Next, k.Submit, k.Cancel}
+}
+
+func (k FormKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{{k.Next, k.Submit, k.Cancel}}
+}
+
+var FormKeys = FormKeyMap{
+	Next: key.NewBinding(
+		key.WithKeys("tab", "shift+tab"),
+		key.WithHelp("tab", "next field"),
+	),
+	Submit: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("enter", "confirm"),
+	),
+	Cancel: key.NewBinding(
+		key.WithKeys("esc"),
+		key.WithHelp("esc", "cancel"),
+	),
+}
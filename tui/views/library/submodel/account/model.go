@@ -0,0 +1,148 @@
+package account
+
+import (
+	"viscue/tui/style"
+	"viscue/tui/tool/cache"
+
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jmoiron/sqlx"
+)
+
+// action is what the form is asked for.
+type action int
+
+const (
+	actionNone action = iota
+	actionRename
+	actionDelete
+)
+
+// Model is the panel showing the account, from which its
+// username can be changed and the account deleted.
+type Model struct {
+	db *sqlx.DB
+
+	// Component
+	username textinput.Model
+	password textinput.Model
+
+	// State
+	info   Info
+	action action
+	err    error
+
+	// Style
+	paneBorder lipgloss.Style
+}
+
+func New(db *sqlx.DB) tea.Model {
+	username := textinput.New()
+	username.Prompt = "New username"
+	username.PromptStyle = style.TextInputPromptStyle.Width(labelWidth)
+	username.Cursor.SetMode(cursor.CursorBlink)
+
+	password := textinput.New()
+	password.Prompt = "Password"
+	password.PromptStyle = style.TextInputPromptStyle.Width(labelWidth)
+	password.EchoMode = textinput.EchoPassword
+	password.EchoCharacter = '•'
+	password.Cursor.SetMode(cursor.CursorBlink)
+
+	m := Model{
+		db:         db,
+		username:   username,
+		password:   password,
+		paneBorder: style.PaneBorderStyle.BorderForeground(style.ColorAccent),
+	}
+
+	m.calculateDimension()
+	return m
+}
+
+func (m Model) Init() tea.Cmd {
+	return tea.Batch(m.SendSetKeysMsg, m.Load)
+}
+
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case LoadedMsg:
+		m.info = Info(msg)
+		return m, nil
+	case RenamedMsg:
+		m.closeForm()
+		return m, tea.Batch(m.SendSetKeysMsg, m.Load, m.notifyRenamed(msg))
+	case ErrorMsg:
+		m.err = msg
+		return m, nil
+	case tea.WindowSizeMsg:
+		m.calculateDimension()
+		return m, nil
+	case tea.KeyMsg:
+		if m.action != actionNone {
+			return m.updateForm(msg)
+		}
+		switch {
+		case key.Matches(msg, Keys.Rename):
+			return m, m.openForm(actionRename)
+		case key.Matches(msg, Keys.Delete):
+			return m, m.openForm(actionDelete)
+		case key.Matches(msg, Keys.Close):
+			return m, m.Close
+		}
+	case cursor.BlinkMsg:
+		var cmds [2]tea.Cmd
+		m.username, cmds[0] = m.username.Update(msg)
+		m.password, cmds[1] = m.password.Update(msg)
+		return m, tea.Batch(cmds[:]...)
+	}
+	return m, nil
+}
+
+func (m Model) View() string {
+	sections := []string{
+		style.ModelTitleFocusedStyle.Render("Account"),
+		m.infoView(),
+	}
+	switch m.action {
+	case actionRename:
+		sections = append(sections, "",
+			style.ModelTitleStyle.Render("Change username"),
+			m.username.View(),
+			m.password.View(),
+		)
+	case actionDelete:
+		sections = append(sections, "",
+			lipgloss.NewStyle().
+				Foreground(style.ColorError).
+				Width(m.paneBorder.GetWidth()-4).
+				Render("Deleting the account erases every item of the vault "+
+					"along with the secrets kept in the keyring. "+
+					"Type your password to confirm."),
+			m.password.View(),
+		)
+	}
+	view := m.paneBorder.Render(lipgloss.JoinVertical(
+		lipgloss.Left,
+		sections...,
+	))
+
+	if m.err != nil {
+		view = lipgloss.JoinVertical(
+			lipgloss.Center,
+			view,
+			style.ErrorText(m.err.Error()),
+		)
+	}
+
+	return lipgloss.Place(
+		cache.Get[int](cache.TerminalWidth),
+		style.CalculateAppHeight(),
+		lipgloss.Center,
+		lipgloss.Center,
+		view,
+	)
+}
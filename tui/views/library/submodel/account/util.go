@@ -0,0 +1,123 @@
+package account
+
+import (
+	"fmt"
+	"strings"
+
+	"viscue/tui/style"
+	"viscue/tui/tool/cache"
+	"viscue/tui/tool/crypto"
+	"viscue/tui/views/library/message"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"golang.org/x/crypto/argon2"
+)
+
+const labelWidth = 14
+
+func (m *Model) calculateDimension() {
+	appHeight := style.CalculateAppHeight() - 2
+	appWidth := cache.Get[int](cache.TerminalWidth) - 6
+	panelWidth := style.PanelWidth(appWidth, 60)
+	m.username.Width = panelWidth - labelWidth - 4
+	m.password.Width = panelWidth - labelWidth - 4
+	m.paneBorder = m.paneBorder.Height(appHeight).
+		MaxHeight(appHeight + 2).
+		Width(panelWidth + 4)
+}
+
+// infoView renders what is known of the account along with
+// the parameters its keys are derived with.
+func (m Model) infoView() string {
+	labelStyle := lipgloss.NewStyle().
+		Foreground(style.ColorMuted).
+		Width(labelWidth)
+	valueStyle := lipgloss.NewStyle().
+		Width(m.paneBorder.GetWidth() - labelWidth - 4)
+
+	created := "unknown"
+	if !m.info.CreatedAt.IsZero() {
+		created = m.info.CreatedAt.Local().Format("02 Jan 2006 15:04")
+	}
+
+	var rows []string
+	for _, row := range [][2]string{
+		{"Username", m.info.Username},
+		{"Vault", m.info.Vault},
+		{"Created", created},
+		{"Keyring", m.info.Backend},
+		{"Fingerprint", m.info.Fingerprint},
+		{"Password", fmt.Sprintf("Argon2id v%d, %d MiB, %d iterations, %d threads",
+			argon2.Version, crypto.ArgonMemory/1024, crypto.ArgonIterations,
+			crypto.ArgonThreads)},
+		{"Unlock key", fmt.Sprintf("PBKDF2-SHA256, %d iterations, HKDF salt",
+			crypto.AucIterations)},
+		{"Private key", fmt.Sprintf("RSA %d bits, AES-256-GCM", crypto.RsaKeyBits)},
+	} {
+		rows = append(rows, lipgloss.JoinHorizontal(
+			lipgloss.Top,
+			labelStyle.Render(row[0]),
+			valueStyle.Render(row[1]),
+		))
+	}
+	return strings.Join(rows, "\n")
+}
+
+func (m *Model) openForm(action action) tea.Cmd {
+	m.err = nil
+	m.action = action
+	m.username.SetValue("")
+	m.password.SetValue("")
+	m.password.Blur()
+	focus := m.password.Focus
+	if action == actionRename {
+		focus = m.username.Focus
+	}
+	return tea.Batch(
+		focus(),
+		func() tea.Msg { return message.SetHelpKeysMsg{Keys: FormKeys} },
+	)
+}
+
+func (m *Model) closeForm() {
+	m.action = actionNone
+	m.err = nil
+	m.username.Blur()
+	m.username.SetValue("")
+	m.password.Blur()
+	m.password.SetValue("")
+}
+
+func (m Model) updateForm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, FormKeys.Cancel):
+		m.closeForm()
+		return m, m.SendSetKeysMsg
+	case key.Matches(msg, FormKeys.Next):
+		if m.action != actionRename {
+			return m, nil
+		}
+		if m.username.Focused() {
+			m.username.Blur()
+			return m, m.password.Focus()
+		}
+		m.password.Blur()
+		return m, m.username.Focus()
+	case key.Matches(msg, FormKeys.Submit):
+		m.err = nil
+		if m.action == actionRename {
+			return m, m.Rename(m.username.Value(), m.password.Value())
+		}
+		return m, m.Delete(m.password.Value())
+	}
+
+	var cmd tea.Cmd
+	if m.username.Focused() {
+		m.username, cmd = m.username.Update(msg)
+	} else {
+		m.password, cmd = m.password.Update(msg)
+	}
+	return m, cmd
+}
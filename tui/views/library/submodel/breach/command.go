@@ -0,0 +1,33 @@
+package breach
+
+import (
+	"viscue/tui/entity"
+	"viscue/tui/views/library/message"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func (m Model) SendSetKeysMsg() tea.Msg {
+	return message.SetHelpKeysMsg{Keys: Keys}
+}
+
+func (m Model) Close() tea.Msg {
+	return message.ClosePanelMsg{}
+}
+
+func (m Model) EditPasswordPromptMsg() tea.Cmd {
+	password, ok := m.selectedPassword()
+	if !ok {
+		return nil
+	}
+	return tea.Sequence(
+		func() tea.Msg {
+			return message.OpenPromptMsg[entity.Password]{
+				Payload: password,
+			}
+		},
+		func() tea.Msg {
+			return message.PromptFocused
+		},
+	)
+}
@@ -0,0 +1,119 @@
+package breach
+
+import (
+	"sort"
+	"strconv"
+
+	"viscue/tui/component/table"
+	"viscue/tui/entity"
+	"viscue/tui/style"
+	"viscue/tui/tool/cache"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/samber/lo"
+)
+
+// Model is the breach report listing every password that
+// was found in the Pwned Passwords dataset, the most seen
+// password first.
+type Model struct {
+	// Component
+	table table.Model
+
+	// State
+	passwords []entity.Password
+
+	// Style
+	paneBorder lipgloss.Style
+}
+
+func New(passwords []entity.Password, counts map[int64]int) tea.Model {
+	breached := lo.Filter(passwords,
+		func(password entity.Password, _ int) bool {
+			return counts[password.Id] > 0
+		})
+	sort.SliceStable(breached, func(i, j int) bool {
+		return counts[breached[i].Id] > counts[breached[j].Id]
+	})
+
+	m := Model{
+		table: table.New(
+			table.WithColumns(
+				[]table.Column{
+					{Title: "Id", Width: 0},
+					{Title: "Name", Width: 24},
+					{Title: "Email", Width: 24},
+					{Title: "Seen", Width: 10},
+				}),
+			table.WithRows(
+				lo.Map(breached,
+					func(password entity.Password, _ int) table.Row {
+						return table.Row{
+							strconv.FormatInt(password.Id, 10),
+							password.Name,
+							password.Email,
+							strconv.Itoa(counts[password.Id]),
+						}
+					}),
+			),
+			table.WithFocused(true),
+		),
+		passwords:  breached,
+		paneBorder: style.PaneBorderStyle.BorderForeground(style.ColorAccent),
+	}
+
+	m.calculateDimension()
+	return m
+}
+
+func (m Model) Init() tea.Cmd {
+	return m.SendSetKeysMsg
+}
+
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.calculateDimension()
+		return m, nil
+	case tea.MouseMsg:
+		var cmd tea.Cmd
+		m.table, cmd = m.table.Update(msg)
+		return m, cmd
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, Keys.Up), key.Matches(msg, Keys.Down):
+			var cmd tea.Cmd
+			m.table, cmd = m.table.Update(msg)
+			return m, cmd
+		case key.Matches(msg, Keys.Edit):
+			return m, m.EditPasswordPromptMsg()
+		case key.Matches(msg, Keys.Close):
+			return m, m.Close
+		}
+	}
+	return m, nil
+}
+
+func (m Model) View() string {
+	content := m.table.View()
+	if len(m.passwords) == 0 {
+		content = lipgloss.NewStyle().Foreground(style.ColorMuted).
+			Render("None of your passwords were found in a breach.")
+	}
+
+	view := m.paneBorder.Render(lipgloss.JoinVertical(
+		lipgloss.Left,
+		style.ModelTitleFocusedStyle.Render("Breach Report"),
+		content,
+	))
+
+	return lipgloss.Place(
+		cache.Get[int](cache.TerminalWidth),
+		style.CalculateAppHeight(),
+		lipgloss.Center,
+		lipgloss.Center,
+		view,
+	)
+}
@@ -0,0 +1,38 @@
+package breach
+
+import (
+	"strconv"
+
+	"viscue/tui/entity"
+	"viscue/tui/style"
+	"viscue/tui/tool/cache"
+
+	"github.com/samber/lo"
+)
+
+func (m *Model) calculateDimension() {
+	appHeight := style.CalculateAppHeight() - 2
+	appWidth := cache.Get[int](cache.TerminalWidth) - 6
+	reportWidth := style.PanelWidth(appWidth, 80)
+	columnWidth := (reportWidth - 12) / 2
+	m.table.SetHeight(appHeight - 6)
+	m.table.SetWidth(reportWidth)
+	m.table.SetColumnsWidth(0, columnWidth, columnWidth, 10)
+	m.paneBorder = m.paneBorder.Height(appHeight).
+		MaxHeight(appHeight + 2).
+		Width(reportWidth + 4)
+}
+
+func (m Model) selectedPassword() (entity.Password, bool) {
+	row := m.table.SelectedRow()
+	if row == nil {
+		return entity.Password{}, false
+	}
+	id, err := strconv.ParseInt(row[0], 10, 64)
+	if err != nil {
+		return entity.Password{}, false
+	}
+	return lo.Find(m.passwords, func(password entity.Password) bool {
+		return password.Id == id
+	})
+}
@@ -0,0 +1,180 @@
+package detail
+
+import (
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"viscue/tui/component/notification"
+	"viscue/tui/entity"
+	"viscue/tui/tool/cache"
+	"viscue/tui/tool/settings"
+	"viscue/tui/tool/vault"
+	"viscue/tui/views/library/message"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/log"
+)
+
+type AttachmentsLoadedMsg struct {
+	Data []entity.Attachment
+}
+
+type AttachedMsg struct {
+	Attachment entity.Attachment
+}
+
+type RemovedMsg struct {
+	Id int64
+}
+
+type ErrorMsg error
+
+func (m Model) SendSetKeysMsg() tea.Msg {
+	return message.SetHelpKeysMsg{Keys: Keys}
+}
+
+func (m Model) Close() tea.Msg {
+	return message.ClosePanelMsg{}
+}
+
+func (m Model) LoadAttachments() tea.Msg {
+	attachments, err := vault.Attachments(m.db, m.password.Id)
+	if err != nil {
+		log.Error("detail.(Model).LoadAttachments: failed loading attachments",
+			"err", err)
+		return ErrorMsg(errors.New("failed loading attachments"))
+	}
+	return AttachmentsLoadedMsg{Data: attachments}
+}
+
+// Attach reads the file at path and stores it encrypted.
+func (m Model) Attach(path string) tea.Cmd {
+	return func() tea.Msg {
+		path, err := expandPath(path)
+		if err != nil {
+			return ErrorMsg(err)
+		}
+		file, err := os.Open(path)
+		if err != nil {
+			return ErrorMsg(fmt.Errorf("failed reading file: %w", err))
+		}
+		defer file.Close()
+		info, err := file.Stat()
+		if err != nil {
+			return ErrorMsg(fmt.Errorf("failed reading file: %w", err))
+		}
+		if info.IsDir() {
+			return ErrorMsg(errors.New("only files can be attached"))
+		}
+
+		// The file may grow after being checked, hence the limit
+		// applies to what is read rather than to its size
+		limit := int64(settings.AttachmentSizeLimit.Get(m.db))
+		exceeded := ErrorMsg(fmt.Errorf("file exceeds the %s size limit",
+			humanSize(limit)))
+		if info.Size() > limit {
+			return exceeded
+		}
+		data, err := io.ReadAll(io.LimitReader(file, limit+1))
+		if err != nil {
+			return ErrorMsg(fmt.Errorf("failed reading file: %w", err))
+		}
+		if int64(len(data)) > limit {
+			return exceeded
+		}
+
+		attachment := entity.Attachment{
+			PasswordId: m.password.Id,
+			Name:       filepath.Base(path),
+			Size:       int64(len(data)),
+			Data:       data,
+			CreatedAt:  time.Now(),
+		}
+		enc := attachment
+		if err = enc.Encrypt(cache.Get[*rsa.PublicKey](cache.PublicKey)); err != nil {
+			return ErrorMsg(fmt.Errorf("failed to encrypt attachment: %w", err))
+		}
+		res, err := m.db.NamedExec(
+			`INSERT INTO attachments (password_id, name, size, data, created_at)
+			VALUES (:password_id, :name, :size, :data, :created_at)`,
+			&enc,
+		)
+		if err == nil {
+			attachment.Id, err = res.LastInsertId()
+		}
+		if err != nil {
+			log.Error("detail.(Model).Attach: failed inserting attachment",
+				"err", err)
+			return ErrorMsg(errors.New("failed saving attachment"))
+		}
+
+		attachment.Data = nil // Only kept while saving to disk
+		return AttachedMsg{Attachment: attachment}
+	}
+}
+
+// Save writes the selected attachment to path. When path is a
+// directory the attachment keeps its name.
+func (m Model) Save(path string) tea.Cmd {
+	selected, ok := m.selectedAttachment()
+	if !ok {
+		return nil
+	}
+	return func() tea.Msg {
+		path, err := expandPath(path)
+		if err != nil {
+			return ErrorMsg(err)
+		}
+		attachment, err := vault.OpenAttachment(m.db, selected.Id)
+		if err != nil {
+			log.Error("detail.(Model).Save: failed opening attachment",
+				"err", err)
+			return ErrorMsg(errors.New("failed decrypting attachment"))
+		}
+		path, err = vault.WriteAttachment(attachment, path)
+		if err != nil {
+			return ErrorMsg(err)
+		}
+		return notification.ShowMsg{
+			Message: "Attachment saved to " + path,
+			Level:   notification.LevelSuccess,
+		}
+	}
+}
+
+// Remove deletes the selected attachment.
+func (m Model) Remove() tea.Msg {
+	attachment, ok := m.selectedAttachment()
+	if !ok {
+		return nil
+	}
+	_, err := m.db.Exec("DELETE FROM attachments WHERE id = ?", attachment.Id)
+	if err != nil {
+		log.Error("detail.(Model).Remove: failed deleting attachment",
+			"err", err)
+		return ErrorMsg(errors.New("failed removing attachment"))
+	}
+	return RemovedMsg{Id: attachment.Id}
+}
+
+// expandPath resolves a leading `~` to the home directory.
+func expandPath(path string) (string, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return "", errors.New("path is empty")
+	}
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed resolving home directory: %w", err)
+	}
+	return filepath.Join(home, strings.TrimPrefix(path, "~")), nil
+}
@@ -0,0 +1,74 @@
+package detail
+
+import "github.com/charmbracelet/bubbles/key"
+
+type KeyMap struct {
+	Up, Down, Reveal, Attach, Save, Remove, Close key.Binding
+}
+
+func (k KeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Reveal, k.Attach, k.Save, k.Close}
+}
+
+func (k KeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.Up, k.Down, k.Reveal},     // first column
+		{k.Attach, k.Save, k.Remove}, // second column
+		{k.Close},                    // third column
+	}
+}
+
+var Keys = KeyMap{
+	Up: key.NewBinding(
+		key.WithKeys("up", "k"),
+		key.WithHelp("↑/k", "up"),
+	),
+	Down: key.NewBinding(
+		key.WithKeys("down", "j"),
+		key.WithHelp("↓/j", "down"),
+	),
+	Reveal: key.NewBinding(
+		key.WithKeys("ctrl+p"),
+		key.WithHelp("ctrl+p", "toggle concealed fields"),
+	),
+	Attach: key.NewBinding(
+		key.WithKeys("a"),
+		key.WithHelp("a", "attach file"),
+	),
+	Save: key.NewBinding(
+		key.WithKeys("s"),
+		key.WithHelp("s", "save attachment"),
+	),
+	Remove: key.NewBinding(
+		key.WithKeys("x"),
+		key.WithHelp("x", "remove attachment"),
+	),
+	Close: key.NewBinding(
+		key.WithKeys("esc", "q"),
+		key.WithHelp("esc", "close"),
+	),
+}
+
+// PathKeyMap is used while typing the path of a file.
+type PathKeyMap struct {
+	Submit, Cancel key.Binding
+}
+
+func (k PathKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Submit, k.Cancel}
+}
+
+func (k PathKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{{k.Submit, k.Cancel}}
+}
+
+var PathKeys = PathKeyMap{
+	Submit: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("enter", "confirm"),
+	),
+	Cancel: key.NewBinding(
+		key.WithKeys("esc"),
+		key.WithHelp("esc", "cancel"),
+	),
+}
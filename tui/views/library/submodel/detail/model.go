@@ -0,0 +1,192 @@
+package detail
+
+import (
+	"fmt"
+
+	"viscue/tui/component/table"
+	"viscue/tui/entity"
+	"viscue/tui/style"
+	"viscue/tui/tool/cache"
+
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jmoiron/sqlx"
+)
+
+// action is what the path input is asked for.
+type action int
+
+const (
+	actionNone action = iota
+	actionAttach
+	actionSave
+)
+
+// Model is the panel showing every field of a password,
+// including its notes and custom fields, along with its
+// attachments which can be added or saved to disk.
+type Model struct {
+	db *sqlx.DB
+
+	// Component
+	table table.Model
+	path  textinput.Model
+
+	// State
+	password       entity.Password
+	attachments    []entity.Attachment
+	showConcealed  bool
+	action         action
+	confirmRemoval bool
+	err            error
+
+	// Style
+	paneBorder lipgloss.Style
+}
+
+func New(db *sqlx.DB, password entity.Password) tea.Model {
+	path := textinput.New()
+	path.Prompt = "Path: "
+	path.PromptStyle = style.TextInputPromptStyle
+	path.Cursor.SetMode(cursor.CursorBlink)
+
+	m := Model{
+		db:       db,
+		password: password,
+		path:     path,
+		table: table.New(
+			table.WithColumns(
+				[]table.Column{
+					{Title: "Id", Width: 0},
+					{Title: "Name", Width: 24},
+					{Title: "Size", Width: 10},
+					{Title: "Added At", Width: 18},
+				}),
+			table.WithFocused(true),
+		),
+		paneBorder: style.PaneBorderStyle.BorderForeground(style.ColorAccent),
+	}
+
+	m.calculateDimension()
+	return m
+}
+
+func (m Model) Init() tea.Cmd {
+	return tea.Batch(m.SendSetKeysMsg, m.LoadAttachments)
+}
+
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case AttachmentsLoadedMsg:
+		m.attachments = msg.Data
+		m.sync()
+		return m, nil
+	case AttachedMsg:
+		m.attachments = append(m.attachments, msg.Attachment)
+		m.sync()
+		m.table.SetIndex(len(m.attachments) - 1)
+		return m, nil
+	case RemovedMsg:
+		m.removeAttachment(msg.Id)
+		return m, nil
+	case ErrorMsg:
+		m.err = msg
+		return m, nil
+	case tea.WindowSizeMsg:
+		m.calculateDimension()
+		return m, nil
+	case tea.MouseMsg:
+		var cmd tea.Cmd
+		m.table, cmd = m.table.Update(msg)
+		return m, cmd
+	case tea.KeyMsg:
+		if m.action != actionNone {
+			return m.updatePath(msg)
+		}
+
+		if !key.Matches(msg, Keys.Remove) {
+			m.confirmRemoval = false
+		}
+		switch {
+		case key.Matches(msg, Keys.Up), key.Matches(msg, Keys.Down):
+			var cmd tea.Cmd
+			m.table, cmd = m.table.Update(msg)
+			return m, cmd
+		case key.Matches(msg, Keys.Reveal):
+			m.showConcealed = !m.showConcealed
+			return m, nil
+		case key.Matches(msg, Keys.Attach):
+			return m, m.askPath(actionAttach, "")
+		case key.Matches(msg, Keys.Save):
+			attachment, ok := m.selectedAttachment()
+			if !ok {
+				return m, nil
+			}
+			return m, m.askPath(actionSave, attachment.Name)
+		case key.Matches(msg, Keys.Remove):
+			if _, ok := m.selectedAttachment(); !ok {
+				return m, nil
+			}
+			if !m.confirmRemoval {
+				m.confirmRemoval = true
+				return m, nil
+			}
+			m.confirmRemoval = false
+			return m, m.Remove
+		case key.Matches(msg, Keys.Close):
+			return m, m.Close
+		}
+	case cursor.BlinkMsg:
+		var cmd tea.Cmd
+		m.path, cmd = m.path.Update(msg)
+		return m, cmd
+	}
+	return m, nil
+}
+
+func (m Model) View() string {
+	attachments := m.table.View()
+	if len(m.attachments) == 0 {
+		attachments = lipgloss.NewStyle().Foreground(style.ColorMuted).
+			Render("No attachments yet.")
+	}
+
+	sections := []string{
+		style.ModelTitleFocusedStyle.Render(
+			fmt.Sprintf("Details: %s", m.password.Name)),
+		m.fieldsView(),
+		"",
+		style.ModelTitleStyle.Render("Attachments"),
+		attachments,
+	}
+	if m.action != actionNone {
+		sections = append(sections, m.path.View())
+	} else if m.confirmRemoval {
+		sections = append(sections, lipgloss.NewStyle().
+			Foreground(style.ColorError).
+			Render("Press x again to remove the attachment"))
+	}
+	view := m.paneBorder.Render(lipgloss.JoinVertical(
+		lipgloss.Left,
+		sections...,
+	))
+
+	if m.err != nil {
+		view = lipgloss.JoinVertical(
+			lipgloss.Center,
+			view,
+			style.ErrorText(m.err.Error()),
+		)
+	}
+
+	return lipgloss.Place(
+		cache.Get[int](cache.TerminalWidth),
+		style.CalculateAppHeight(),
+		lipgloss.Center,
+		lipgloss.Center,
+		view,
+	)
+}
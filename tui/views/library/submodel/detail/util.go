@@ -0,0 +1,148 @@
+package detail
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"viscue/tui/component/table"
+	"viscue/tui/entity"
+	"viscue/tui/style"
+	"viscue/tui/tool/cache"
+	"viscue/tui/views/library/message"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/samber/lo"
+)
+
+const (
+	labelWidth       = 12
+	attachmentsShown = 5
+)
+
+func (m *Model) calculateDimension() {
+	appHeight := style.CalculateAppHeight() - 2
+	appWidth := cache.Get[int](cache.TerminalWidth) - 6
+	panelWidth := style.PanelWidth(appWidth, 60)
+	m.table.SetHeight(attachmentsShown + 1)
+	m.table.SetWidth(panelWidth)
+	m.table.SetColumnsWidth(0, panelWidth-30, 10, 18)
+	m.path.Width = panelWidth - 10
+	m.paneBorder = m.paneBorder.Height(appHeight).
+		MaxHeight(appHeight + 2).
+		Width(panelWidth + 4)
+}
+
+// fieldsView renders every non-empty field of the password,
+// masking the concealed ones unless they are revealed.
+func (m Model) fieldsView() string {
+	labelStyle := lipgloss.NewStyle().
+		Foreground(style.ColorMuted).
+		Width(labelWidth)
+	valueStyle := lipgloss.NewStyle().
+		Width(m.paneBorder.GetWidth() - labelWidth - 4)
+
+	var rows []string
+	row := func(label, value string) {
+		rows = append(rows, lipgloss.JoinHorizontal(
+			lipgloss.Top,
+			labelStyle.Render(label),
+			valueStyle.Render(value),
+		))
+	}
+
+	row("Type", m.password.Type.Schema().Label)
+	if len(m.password.Tags) > 0 {
+		row("Tags", strings.Join(m.password.Tags, ", "))
+	}
+	for _, entry := range m.password.Entries() {
+		value := entry.Display(m.showConcealed)
+		if entry.Rule != "" {
+			value += " [" + string(entry.Rule) + "]"
+		}
+		row(entry.Label, value)
+	}
+	return strings.Join(rows, "\n")
+}
+
+func (m *Model) sync() {
+	index := m.table.Index()
+	m.table.SetRows(
+		lo.Map(m.attachments,
+			func(attachment entity.Attachment, _ int) table.Row {
+				return table.Row{
+					strconv.FormatInt(attachment.Id, 10),
+					attachment.Name,
+					humanSize(attachment.Size),
+					attachment.CreatedAt.Local().Format("2006-01-02 15:04"),
+				}
+			}),
+	)
+	m.table.SetIndex(index)
+}
+
+func (m Model) selectedAttachment() (entity.Attachment, bool) {
+	if len(m.attachments) == 0 {
+		return entity.Attachment{}, false
+	}
+	return m.attachments[m.table.Index()], true
+}
+
+func (m *Model) removeAttachment(id int64) {
+	m.attachments = lo.Filter(m.attachments,
+		func(attachment entity.Attachment, _ int) bool {
+			return attachment.Id != id
+		})
+	m.sync()
+}
+
+// askPath shows the path input for the given action.
+func (m *Model) askPath(action action, value string) tea.Cmd {
+	m.err = nil
+	m.action = action
+	m.path.SetValue(value)
+	m.path.CursorEnd()
+	return tea.Batch(
+		m.path.Focus(),
+		func() tea.Msg { return message.SetHelpKeysMsg{Keys: PathKeys} },
+	)
+}
+
+func (m Model) updatePath(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, PathKeys.Cancel), key.Matches(msg, PathKeys.Submit):
+		action, path := m.action, m.path.Value()
+		m.action = actionNone
+		m.path.Blur()
+		cmds := []tea.Cmd{m.SendSetKeysMsg}
+		if key.Matches(msg, PathKeys.Submit) {
+			switch action {
+			case actionAttach:
+				cmds = append(cmds, m.Attach(path))
+			case actionSave:
+				cmds = append(cmds, m.Save(path))
+			}
+		}
+		return m, tea.Batch(cmds...)
+	}
+
+	var cmd tea.Cmd
+	m.path, cmd = m.path.Update(msg)
+	return m, cmd
+}
+
+// humanSize formats a number of bytes, e.g. `1.5 KiB`.
+func humanSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,183 @@
+package history
+
+import (
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"time"
+
+	"viscue/tui/entity"
+	"viscue/tui/tool/cache"
+	"viscue/tui/tool/settings"
+	"viscue/tui/tool/undo"
+	"viscue/tui/views/library/message"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/log"
+	"github.com/jmoiron/sqlx"
+	"golang.design/x/clipboard"
+)
+
+type DataLoadedMsg struct {
+	Data []entity.PasswordHistory
+}
+
+type ErrorMsg error
+
+// RestoredMsg is sent once a previous value has been restored
+// as the current password.
+type RestoredMsg struct {
+	Password entity.Password
+}
+
+func (m Model) SendSetKeysMsg() tea.Msg {
+	return message.SetHelpKeysMsg{Keys: Keys}
+}
+
+func (m Model) Close() tea.Msg {
+	return message.ClosePanelMsg{}
+}
+
+func (m Model) LoadItems() tea.Msg {
+	rows, err := m.db.Queryx(
+		`SELECT id, password_id, password, created_at FROM password_history
+		WHERE password_id = ? ORDER BY created_at DESC, id DESC`,
+		m.password.Id,
+	)
+	if err != nil {
+		log.Error("history.(Model).LoadItems: failed querying history",
+			"err", err)
+		return ErrorMsg(errors.New("failed loading password history"))
+	}
+	defer rows.Close()
+
+	privateKey := cache.Get[*rsa.PrivateKey](cache.PrivateKey)
+	var entries []entity.PasswordHistory
+	for rows.Next() {
+		var entry entity.PasswordHistory
+		if err = rows.StructScan(&entry); err != nil {
+			return ErrorMsg(err)
+		}
+		if err = entry.Decrypt(privateKey); err != nil {
+			return ErrorMsg(err)
+		}
+		entries = append(entries, entry)
+	}
+
+	return DataLoadedMsg{Data: entries}
+}
+
+func (m Model) CopyToClipboard() tea.Msg {
+	entry, ok := m.selectedEntry()
+	if !ok {
+		return nil
+	}
+	clipboard.Write(clipboard.FmtText, []byte(entry.Password))
+	return message.CopiedMsg{
+		Label: "Previous password",
+		Value: entry.Password,
+	}
+}
+
+// Restore sets the selected previous value as the current
+// password, archiving the current one along the way.
+func (m Model) Restore() tea.Msg {
+	entry, ok := m.selectedEntry()
+	if !ok {
+		return nil
+	}
+
+	password := m.password.Copy()
+	password.Password = entry.Password
+	password.UpdatedAt = time.Now()
+	enc := password.Copy()
+	if err := enc.Encrypt(cache.Get[*rsa.PublicKey](cache.PublicKey)); err != nil {
+		return ErrorMsg(fmt.Errorf("failed to encrypt entity: %w", err))
+	}
+
+	tx, err := m.db.Beginx()
+	if err != nil {
+		log.Error("history.(Model).Restore: failed to start transaction",
+			"err", err)
+		return ErrorMsg(errors.New("something went wrong with sqlite database"))
+	}
+
+	record := undo.Record("Restore previous password of " + password.Name)
+	if err = record.Track(tx, undo.Passwords, password.Id); err != nil {
+		log.Error("history.(Model).Restore: failed recording change", "err", err)
+		_ = tx.Rollback()
+		return ErrorMsg(errors.New("failed restoring password"))
+	}
+
+	if err = Archive(tx, m.password.Id, m.password.Password); err != nil {
+		_ = tx.Rollback()
+		return ErrorMsg(err)
+	}
+
+	_, err = tx.Exec(
+		"UPDATE passwords SET password = ?, updated_at = ? WHERE id = ?",
+		enc.Password, password.UpdatedAt, password.Id)
+	if err != nil {
+		log.Error("history.(Model).Restore: failed updating password",
+			"err", err)
+		_ = tx.Rollback()
+		return ErrorMsg(errors.New("failed restoring password"))
+	}
+	if err = record.Done(tx); err != nil {
+		log.Error("history.(Model).Restore: failed recording change", "err", err)
+		_ = tx.Rollback()
+		return ErrorMsg(errors.New("failed restoring password"))
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error("history.(Model).Restore: failed to commit transaction",
+			"err", err)
+		return ErrorMsg(errors.New("failed restoring password"))
+	}
+	record.Push()
+
+	return RestoredMsg{Password: password}
+}
+
+// Archive keeps the previous value of a password in its history
+// and prunes the oldest entries beyond the configured retention.
+// A retention of zero disables the history altogether.
+func Archive(tx *sqlx.Tx, passwordId int64, previous string) error {
+	retention := settings.PasswordHistoryRetention.Get(tx)
+	if retention <= 0 || previous == "" {
+		return nil
+	}
+
+	entry := entity.PasswordHistory{
+		PasswordId: passwordId,
+		Password:   previous,
+	}
+	if err := entry.Encrypt(cache.Get[*rsa.PublicKey](cache.PublicKey)); err != nil {
+		return fmt.Errorf("failed to encrypt previous password: %w", err)
+	}
+
+	_, err := tx.NamedExec(
+		`INSERT INTO password_history (password_id, password)
+		VALUES (:password_id, :password)`,
+		&entry,
+	)
+	if err != nil {
+		log.Error("history.Archive: failed inserting history", "err", err)
+		return errors.New("failed saving previous password")
+	}
+
+	_, err = tx.Exec(
+		`DELETE FROM password_history
+		WHERE password_id = ? AND id NOT IN (
+			SELECT id FROM password_history WHERE password_id = ?
+			ORDER BY created_at DESC, id DESC LIMIT ?
+		)`,
+		passwordId, passwordId, retention,
+	)
+	if err != nil {
+		log.Error("history.Archive: failed pruning history", "err", err)
+		return errors.New("failed pruning password history")
+	}
+
+	return nil
+}
@@ -0,0 +1,125 @@
+package history
+
+import (
+	"fmt"
+
+	"viscue/tui/component/table"
+	"viscue/tui/entity"
+	"viscue/tui/style"
+	"viscue/tui/tool/cache"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jmoiron/sqlx"
+)
+
+// Model is the panel listing the previous values of a
+// password, allowing to reveal, copy or restore them.
+type Model struct {
+	db *sqlx.DB
+
+	// Component
+	table table.Model
+
+	// State
+	password     entity.Password
+	entries      []entity.PasswordHistory
+	showPassword bool
+	err          error
+
+	// Style
+	paneBorder lipgloss.Style
+}
+
+func New(db *sqlx.DB, password entity.Password) tea.Model {
+	m := Model{
+		db:       db,
+		password: password,
+		table: table.New(
+			table.WithColumns(
+				[]table.Column{
+					{Title: "Id", Width: 0},
+					{Title: "Changed At", Width: 20},
+					{Title: "Password", Width: 24},
+				}),
+			table.WithFocused(true),
+		),
+		paneBorder: style.PaneBorderStyle.BorderForeground(style.ColorAccent),
+	}
+
+	m.calculateDimension()
+	return m
+}
+
+func (m Model) Init() tea.Cmd {
+	return tea.Batch(m.SendSetKeysMsg, m.LoadItems)
+}
+
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case DataLoadedMsg:
+		m.entries = msg.Data
+		m.sync()
+		return m, nil
+	case ErrorMsg:
+		m.err = msg
+		return m, nil
+	case tea.WindowSizeMsg:
+		m.calculateDimension()
+		return m, nil
+	case tea.MouseMsg:
+		var cmd tea.Cmd
+		m.table, cmd = m.table.Update(msg)
+		return m, cmd
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, Keys.Up), key.Matches(msg, Keys.Down):
+			var cmd tea.Cmd
+			m.table, cmd = m.table.Update(msg)
+			return m, cmd
+		case key.Matches(msg, Keys.Reveal):
+			m.showPassword = !m.showPassword
+			m.sync()
+			return m, nil
+		case key.Matches(msg, Keys.Copy):
+			return m, m.CopyToClipboard
+		case key.Matches(msg, Keys.Restore):
+			return m, m.Restore
+		case key.Matches(msg, Keys.Close):
+			return m, m.Close
+		}
+	}
+	return m, nil
+}
+
+func (m Model) View() string {
+	content := m.table.View()
+	if len(m.entries) == 0 {
+		content = lipgloss.NewStyle().Foreground(style.ColorMuted).
+			Render("This password has never been changed.")
+	}
+
+	view := m.paneBorder.Render(lipgloss.JoinVertical(
+		lipgloss.Left,
+		style.ModelTitleFocusedStyle.Render(
+			fmt.Sprintf("Password History: %s", m.password.Name)),
+		content,
+	))
+
+	if m.err != nil {
+		view = lipgloss.JoinVertical(
+			lipgloss.Center,
+			view,
+			style.ErrorText(m.err.Error()),
+		)
+	}
+
+	return lipgloss.Place(
+		cache.Get[int](cache.TerminalWidth),
+		style.CalculateAppHeight(),
+		lipgloss.Center,
+		lipgloss.Center,
+		view,
+	)
+}
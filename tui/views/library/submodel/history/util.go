@@ -0,0 +1,51 @@
+package history
+
+import (
+	"strconv"
+	"strings"
+
+	"viscue/tui/component/table"
+	"viscue/tui/entity"
+	"viscue/tui/style"
+	"viscue/tui/tool/cache"
+
+	"github.com/samber/lo"
+)
+
+func (m *Model) calculateDimension() {
+	appHeight := style.CalculateAppHeight() - 2
+	appWidth := cache.Get[int](cache.TerminalWidth) - 6
+	panelWidth := style.PanelWidth(appWidth, 60)
+	m.table.SetHeight(appHeight - 8)
+	m.table.SetWidth(panelWidth)
+	m.table.SetColumnsWidth(0, 20, panelWidth-22)
+	m.paneBorder = m.paneBorder.Height(appHeight).
+		MaxHeight(appHeight + 2).
+		Width(panelWidth + 4)
+}
+
+func (m *Model) sync() {
+	index := m.table.Index()
+	m.table.SetRows(
+		lo.Map(m.entries,
+			func(entry entity.PasswordHistory, _ int) table.Row {
+				password := strings.Repeat("•", 12)
+				if m.showPassword {
+					password = entry.Password
+				}
+				return table.Row{
+					strconv.FormatInt(entry.Id, 10),
+					entry.CreatedAt.Local().Format("2006-01-02 15:04"),
+					password,
+				}
+			}),
+	)
+	m.table.SetIndex(index)
+}
+
+func (m Model) selectedEntry() (entity.PasswordHistory, bool) {
+	if len(m.entries) == 0 {
+		return entity.PasswordHistory{}, false
+	}
+	return m.entries[m.table.Index()], true
+}
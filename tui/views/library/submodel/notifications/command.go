@@ -0,0 +1,22 @@
+package notifications
+
+import (
+	"viscue/tui/views/library/message"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ClearedMsg asks the library to forget the notifications shown so far.
+type ClearedMsg struct{}
+
+func (m Model) SendSetKeysMsg() tea.Msg {
+	return message.SetHelpKeysMsg{Keys: Keys}
+}
+
+func (m Model) Clear() tea.Msg {
+	return ClearedMsg{}
+}
+
+func (m Model) Close() tea.Msg {
+	return message.ClosePanelMsg{}
+}
@@ -0,0 +1,100 @@
+package notifications
+
+import (
+	"viscue/tui/component/notification"
+	"viscue/tui/component/table"
+	"viscue/tui/style"
+	"viscue/tui/tool/cache"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Model is the panel listing the notifications shown
+// since the library was opened, the latest first.
+type Model struct {
+	// Component
+	table table.Model
+
+	// State
+	entries []notification.Entry
+
+	// Style
+	paneBorder lipgloss.Style
+}
+
+func New(entries []notification.Entry) tea.Model {
+	m := Model{
+		table: table.New(
+			table.WithColumns(
+				[]table.Column{
+					{Title: "Time", Width: 10},
+					{Title: "Level", Width: 10},
+					{Title: "Message", Width: 24},
+				}),
+			table.WithFocused(true),
+		),
+		paneBorder: style.PaneBorderStyle.BorderForeground(style.ColorAccent),
+	}
+
+	m.calculateDimension()
+	m.SetEntries(entries)
+	return m
+}
+
+func (m Model) Init() tea.Cmd {
+	return m.SendSetKeysMsg
+}
+
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.calculateDimension()
+		return m, nil
+	case tea.MouseMsg:
+		var cmd tea.Cmd
+		m.table, cmd = m.table.Update(msg)
+		return m, cmd
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, Keys.Up), key.Matches(msg, Keys.Down):
+			var cmd tea.Cmd
+			m.table, cmd = m.table.Update(msg)
+			return m, cmd
+		case key.Matches(msg, Keys.Clear):
+			return m, m.Clear
+		case key.Matches(msg, Keys.Close):
+			return m, m.Close
+		}
+	}
+	return m, nil
+}
+
+func (m Model) View() string {
+	content := m.table.View()
+	if len(m.entries) == 0 {
+		content = lipgloss.NewStyle().Foreground(style.ColorMuted).
+			Render("No notification has been shown yet.")
+	}
+
+	view := m.paneBorder.Render(lipgloss.JoinVertical(
+		lipgloss.Left,
+		style.ModelTitleFocusedStyle.Render("Notifications"),
+		content,
+	))
+
+	return lipgloss.Place(
+		cache.Get[int](cache.TerminalWidth),
+		style.CalculateAppHeight(),
+		lipgloss.Center,
+		lipgloss.Center,
+		view,
+	)
+}
+
+// SetEntries replaces the listed notifications, given oldest first.
+func (m *Model) SetEntries(entries []notification.Entry) {
+	m.entries = entries
+	m.sync()
+}
@@ -0,0 +1,40 @@
+package notifications
+
+import (
+	"slices"
+
+	"viscue/tui/component/notification"
+	"viscue/tui/component/table"
+	"viscue/tui/style"
+	"viscue/tui/tool/cache"
+
+	"github.com/samber/lo"
+)
+
+func (m *Model) calculateDimension() {
+	appHeight := style.CalculateAppHeight() - 2
+	appWidth := cache.Get[int](cache.TerminalWidth) - 6
+	panelWidth := style.PanelWidth(appWidth, 60)
+	m.table.SetHeight(appHeight - 8)
+	m.table.SetWidth(panelWidth)
+	m.table.SetColumnsWidth(10, 10, panelWidth-20)
+	m.paneBorder = m.paneBorder.Height(appHeight).
+		MaxHeight(appHeight + 2).
+		Width(panelWidth + 4)
+}
+
+func (m *Model) sync() {
+	entries := slices.Clone(m.entries)
+	slices.Reverse(entries)
+	m.table.SetRows(
+		lo.Map(entries,
+			func(entry notification.Entry, _ int) table.Row {
+				return table.Row{
+					entry.Time.Local().Format("15:04:05"),
+					entry.Level.Icon() + " " + entry.Level.String(),
+					entry.Message,
+				}
+			}),
+	)
+	m.table.SetIndex(0)
+}
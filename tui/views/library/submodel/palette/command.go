@@ -0,0 +1,20 @@
+package palette
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ClosedMsg asks the library to close the palette.
+type ClosedMsg struct{}
+
+func (m Model) Close() tea.Msg {
+	return ClosedMsg{}
+}
+
+// Select closes the palette and runs the entry under the cursor.
+func (m Model) Select() tea.Cmd {
+	if len(m.matches) == 0 {
+		return nil
+	}
+	return tea.Sequence(m.Close, m.matches[m.cursor].entry.Cmd)
+}
@@ -0,0 +1,38 @@
+package palette
+
+import "github.com/charmbracelet/bubbles/key"
+
+type KeyMap struct {
+	Up, Down, Select, Close key.Binding
+}
+
+func (k KeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Up, k.Down, k.Select, k.Close}
+}
+
+func (k KeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.Up, k.Down}, // first column
+		{k.Select},     // second column
+		{k.Close},      // third column
+	}
+}
+
+var Keys = KeyMap{
+	Up: key.NewBinding(
+		key.WithKeys("up", "ctrl+p", "shift+tab"),
+		key.WithHelp("↑/ctrl+p", "up"),
+	),
+	Down: key.NewBinding(
+		key.WithKeys("down", "ctrl+n", "tab"),
+		key.WithHelp("↓/ctrl+n", "down"),
+	),
+	Select: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("enter", "run"),
+	),
+	Close: key.NewBinding(
+		key.WithKeys("esc", "ctrl+k"),
+		key.WithHelp("esc", "close"),
+	),
+}
@@ -0,0 +1,113 @@
+package palette
+
+import (
+	"strings"
+
+	"viscue/tui/style"
+	"viscue/tui/tool/cache"
+
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// visibleEntries is the number of entries listed at once.
+const visibleEntries = 10
+
+// Entry is an action or an item the palette offers.
+type Entry struct {
+	Title string
+	Hint  string // shown on the right, e.g. the key doing the same
+	Cmd   tea.Cmd
+}
+
+// Model is the command palette drawn over the library, fuzzy
+// searching every action and item and running the one picked.
+type Model struct {
+	// Component
+	input textinput.Model
+
+	// State
+	entries []Entry
+	matches []match
+	cursor  int
+	offset  int // index of the first listed match
+
+	// Style
+	border lipgloss.Style
+}
+
+func New(entries []Entry) tea.Model {
+	input := textinput.New()
+	input.Prompt = "> "
+	input.Placeholder = "Type a command or an item name..."
+	input.Cursor.SetMode(cursor.CursorStatic)
+	input.Focus()
+
+	m := Model{
+		input:   input,
+		entries: entries,
+		border: lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).
+			BorderForeground(style.ColorAccent).Padding(0, 1),
+	}
+	m.filter()
+	return m
+}
+
+func (m Model) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, Keys.Up):
+			m.move(-1)
+			return m, nil
+		case key.Matches(msg, Keys.Down):
+			m.move(1)
+			return m, nil
+		case key.Matches(msg, Keys.Select):
+			return m, m.Select()
+		case key.Matches(msg, Keys.Close):
+			return m, m.Close
+		}
+		var cmd tea.Cmd
+		value := m.input.Value()
+		m.input, cmd = m.input.Update(msg)
+		if m.input.Value() != value {
+			m.filter()
+		}
+		return m, cmd
+	}
+	return m, nil
+}
+
+func (m Model) View() string {
+	width := m.width()
+	m.input.Width = width - 3
+
+	rows := []string{
+		style.ModelTitleFocusedStyle.MarginBottom(0).Render("Commands"),
+		m.input.View(),
+		lipgloss.NewStyle().Foreground(style.ColorMuted).
+			Render(strings.Repeat("─", width)),
+	}
+	if len(m.matches) == 0 {
+		rows = append(rows, lipgloss.NewStyle().Foreground(style.ColorMuted).
+			Render("No matching command"))
+	}
+	end := min(m.offset+visibleEntries, len(m.matches))
+	for i := m.offset; i < end; i++ {
+		rows = append(rows, m.renderMatch(m.matches[i], i == m.cursor, width))
+	}
+	return m.border.Width(width + 2).Render(
+		lipgloss.JoinVertical(lipgloss.Left, rows...))
+}
+
+func (m Model) width() int {
+	return min(72, cache.Get[int](cache.TerminalWidth)*60/100)
+}
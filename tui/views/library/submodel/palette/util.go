@@ -0,0 +1,108 @@
+package palette
+
+import (
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"viscue/tui/style"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/sahilm/fuzzy"
+	"github.com/samber/lo"
+)
+
+// match is an entry kept by the search, along with
+// the indexes of the runes of its title that matched.
+type match struct {
+	entry      Entry
+	highlights []int
+}
+
+// filter keeps the entries matching the input, the best first.
+func (m *Model) filter() {
+	m.cursor, m.offset = 0, 0
+	value := strings.TrimSpace(m.input.Value())
+	if value == "" {
+		m.matches = lo.Map(m.entries, func(entry Entry, _ int) match {
+			return match{entry: entry}
+		})
+		return
+	}
+
+	found := fuzzy.Find(value, lo.Map(m.entries,
+		func(entry Entry, _ int) string {
+			return entry.Title
+		}))
+	m.matches = lo.Map(found, func(result fuzzy.Match, _ int) match {
+		title := m.entries[result.Index].Title
+		return match{
+			entry: m.entries[result.Index],
+			highlights: lo.Map(result.MatchedIndexes,
+				func(index int, _ int) int {
+					return utf8.RuneCountInString(title[:index])
+				}),
+		}
+	})
+}
+
+// move shifts the cursor by the delta, wrapping around and
+// scrolling the list so that the cursor stays visible.
+func (m *Model) move(delta int) {
+	if len(m.matches) == 0 {
+		return
+	}
+	m.cursor = (m.cursor + delta + len(m.matches)) % len(m.matches)
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	} else if m.cursor >= m.offset+visibleEntries {
+		m.offset = m.cursor - visibleEntries + 1
+	}
+}
+
+// renderMatch renders the entry on a single line, its
+// title on the left with the matched runes emphasized.
+func (m Model) renderMatch(match match, selected bool, width int) string {
+	text := lipgloss.NewStyle().Foreground(style.ColorNormal)
+	if selected {
+		text = style.Emphasize(text.Foreground(style.ColorContrast).
+			Background(style.ColorAccent))
+	}
+	emphasized := text.Bold(true).Underline(true)
+	hint := text.Foreground(style.ColorMuted)
+	if selected {
+		hint = text
+	}
+
+	hintWidth := min(lipgloss.Width(match.entry.Hint), width/3)
+	titleWidth := width - hintWidth - 1
+	runes := []rune(match.entry.Title)
+	if len(runes) > titleWidth {
+		runes = append(runes[:titleWidth-1], '…')
+	}
+
+	var b strings.Builder
+	for i, r := range runes {
+		if slices.Contains(match.highlights, i) {
+			b.WriteString(emphasized.Render(string(r)))
+		} else {
+			b.WriteString(text.Render(string(r)))
+		}
+	}
+	gap := width - len(runes) - hintWidth
+	b.WriteString(text.Render(strings.Repeat(" ", max(gap, 1))))
+	b.WriteString(hint.Render(truncate(match.entry.Hint, hintWidth)))
+	return b.String()
+}
+
+// truncate cuts the value to the width, marking the cut.
+func truncate(value string, width int) string {
+	runes := []rune(value)
+	if len(runes) <= width {
+		return value
+	}
+	if width <= 1 {
+		return string(runes[:width])
+	}
+	return string(runes[:width-1]) + "…"
+}
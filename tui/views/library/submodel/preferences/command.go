@@ -0,0 +1,144 @@
+package preferences
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"viscue/tui/component/notification"
+	"viscue/tui/entity"
+	"viscue/tui/tool/crypto"
+	"viscue/tui/tool/keychain"
+	"viscue/tui/tool/settings"
+	"viscue/tui/views/library/message"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/log"
+	"github.com/jmoiron/sqlx"
+)
+
+var errNotANumber = errors.New("must be a number")
+
+type LoadedMsg struct {
+	Values     []string
+	Categories []entity.Category
+}
+
+// SavedMsg tells that a setting has been saved, along with
+// the message to send for the change to take effect, if any.
+type SavedMsg struct {
+	Title  string
+	Effect tea.Msg
+}
+
+type ErrorMsg error
+
+func (m Model) SendSetKeysMsg() tea.Msg {
+	return message.SetHelpKeysMsg{Keys: Keys}
+}
+
+func (m Model) Close() tea.Msg {
+	return message.ClosePanelMsg{}
+}
+
+func (m Model) Load() tea.Msg {
+	var categories []entity.Category
+	err := m.db.Select(&categories,
+		"SELECT id, name, parent_id FROM categories ORDER BY name")
+	if err != nil {
+		log.Error("preferences.(Model).Load: failed loading categories",
+			"err", err)
+		return ErrorMsg(errors.New("failed loading categories"))
+	}
+
+	values := make([]string, len(m.entries))
+	for i, entry := range m.entries {
+		values[i] = entry.get(m.db)
+	}
+	return LoadedMsg{Values: values, Categories: categories}
+}
+
+// Save validates and saves the value of the setting at index.
+func (m Model) Save(index int, value string) tea.Cmd {
+	entry := m.entries[index]
+	return func() tea.Msg {
+		if err := entry.set(m.db, value); err != nil {
+			return ErrorMsg(fmt.Errorf("%s %w", entry.title, err))
+		}
+		saved := SavedMsg{Title: entry.title}
+		if entry.effect != nil {
+			saved.Effect = entry.effect(value)
+		}
+		return saved
+	}
+}
+
+func (m Model) notifySaved(msg SavedMsg) tea.Cmd {
+	return func() tea.Msg {
+		return notification.ShowMsg{
+			Message: msg.Title + " saved",
+			Level:   notification.LevelSuccess,
+			Key:     "settings",
+		}
+	}
+}
+
+// categoryOptions lists none, as zero, followed by every category.
+func categoryOptions(db *sqlx.DB) []string {
+	var ids []int64
+	err := db.Select(&ids, "SELECT id FROM categories ORDER BY name")
+	if err != nil {
+		log.Error("preferences.categoryOptions: failed loading categories",
+			"err", err)
+	}
+	options := []string{"0"}
+	for _, id := range ids {
+		options = append(options, strconv.FormatInt(id, 10))
+	}
+	return options
+}
+
+// setKeyringBackend moves the secrets of the account to the new
+// backend before saving it, and back if saving fails, so that they
+// are always found where the setting tells.
+func setKeyringBackend(db *sqlx.DB, value string) error {
+	current := settings.KeyringBackend.Get(db)
+	if value == current {
+		return nil
+	}
+	if err := settings.KeyringBackend.Validate(value); err != nil {
+		return err
+	}
+	from, err := keychain.Open(current)
+	if err != nil {
+		return err
+	}
+	to, err := keychain.Open(value)
+	if err != nil {
+		return err
+	}
+
+	var username string
+	err = db.QueryRowx("SELECT value FROM configurations WHERE key = ?",
+		"username").Scan(&username)
+	if err != nil {
+		log.Error("preferences.setKeyringBackend: failed querying username",
+			"err", err)
+		return errors.New("could not be changed, no account found")
+	}
+
+	services := []string{crypto.SecretKeyStorageName, crypto.SaltStorageName}
+	if err = keychain.Move(from, to, username, services...); err != nil {
+		log.Error("preferences.setKeyringBackend: failed moving secrets",
+			"from", current, "to", value, "err", err)
+		return errors.New("could not be changed, failed moving the secrets")
+	}
+	if err = settings.KeyringBackend.Set(db, value); err != nil {
+		if err := keychain.Move(to, from, username, services...); err != nil {
+			log.Error("preferences.setKeyringBackend: failed moving secrets back",
+				"from", value, "to", current, "err", err)
+		}
+		return err
+	}
+	return keychain.Use(value)
+}
@@ -0,0 +1,158 @@
+package preferences
+
+import (
+	"viscue/tui/component/table"
+	"viscue/tui/entity"
+	"viscue/tui/style"
+	"viscue/tui/tool/cache"
+
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jmoiron/sqlx"
+)
+
+// Model is the panel listing the settings of viscue, each one
+// saved as soon as it is changed.
+type Model struct {
+	db *sqlx.DB
+
+	// Component
+	table table.Model
+	input textinput.Model
+
+	// State
+	entries    []entry
+	values     []string
+	categories []entity.Category
+	editing    bool
+	err        error
+
+	// Style
+	paneBorder lipgloss.Style
+}
+
+func New(db *sqlx.DB) tea.Model {
+	input := textinput.New()
+	input.PromptStyle = style.TextInputPromptStyle
+	input.Cursor.SetMode(cursor.CursorBlink)
+
+	m := Model{
+		db:      db,
+		input:   input,
+		entries: entries(),
+		table: table.New(
+			table.WithColumns(
+				[]table.Column{
+					{Title: "Setting", Width: 24},
+					{Title: "Value", Width: 24},
+				}),
+			table.WithFocused(true),
+		),
+		paneBorder: style.PaneBorderStyle.BorderForeground(style.ColorAccent),
+	}
+
+	m.calculateDimension()
+	return m
+}
+
+func (m Model) Init() tea.Cmd {
+	return tea.Batch(m.SendSetKeysMsg, m.Load)
+}
+
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case LoadedMsg:
+		m.values = msg.Values
+		m.categories = msg.Categories
+		m.sync()
+		return m, nil
+	case SavedMsg:
+		m.err = nil
+		cmds := []tea.Cmd{m.Load, m.notifySaved(msg)}
+		if effect := msg.Effect; effect != nil {
+			cmds = append(cmds, func() tea.Msg { return effect })
+		}
+		return m, tea.Batch(cmds...)
+	case ErrorMsg:
+		m.err = msg
+		return m, nil
+	case tea.WindowSizeMsg:
+		m.calculateDimension()
+		return m, nil
+	case tea.MouseMsg:
+		if m.editing {
+			return m, nil
+		}
+		if double, ok := m.table.Click(msg); ok {
+			m.err = nil
+			if double {
+				return m, m.edit()
+			}
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.table, cmd = m.table.Update(msg)
+		return m, cmd
+	case tea.KeyMsg:
+		if m.editing {
+			return m.updateInput(msg)
+		}
+		switch {
+		case key.Matches(msg, Keys.Up), key.Matches(msg, Keys.Down):
+			var cmd tea.Cmd
+			m.table, cmd = m.table.Update(msg)
+			m.err = nil
+			return m, cmd
+		case key.Matches(msg, Keys.Previous):
+			return m, m.step(-1)
+		case key.Matches(msg, Keys.Next):
+			return m, m.step(1)
+		case key.Matches(msg, Keys.Edit):
+			return m, m.edit()
+		case key.Matches(msg, Keys.Close):
+			return m, m.Close
+		}
+	case cursor.BlinkMsg:
+		var cmd tea.Cmd
+		m.input, cmd = m.input.Update(msg)
+		return m, cmd
+	}
+	return m, nil
+}
+
+func (m Model) View() string {
+	sections := []string{
+		style.ModelTitleFocusedStyle.Render("Settings"),
+		m.table.View(),
+		"",
+		lipgloss.NewStyle().Foreground(style.ColorMuted).
+			Width(m.table.Width()).
+			Render(m.entries[m.table.Index()].description),
+	}
+	if m.editing {
+		sections = append(sections, "", m.input.View())
+	}
+	view := m.paneBorder.Render(lipgloss.JoinVertical(
+		lipgloss.Left,
+		sections...,
+	))
+
+	if m.err != nil {
+		view = lipgloss.JoinVertical(
+			lipgloss.Center,
+			view,
+			style.ErrorText(m.err.Error()),
+		)
+	}
+
+	return lipgloss.Place(
+		cache.Get[int](cache.TerminalWidth),
+		style.CalculateAppHeight(),
+		lipgloss.Center,
+		lipgloss.Center,
+		view,
+	)
+}
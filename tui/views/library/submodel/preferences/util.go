@@ -0,0 +1,258 @@
+package preferences
+
+import (
+	"slices"
+	"strconv"
+
+	"viscue/tui/component/table"
+	"viscue/tui/entity"
+	"viscue/tui/style"
+	"viscue/tui/tool/cache"
+	"viscue/tui/tool/keychain"
+	"viscue/tui/tool/settings"
+	"viscue/tui/tool/theme"
+	"viscue/tui/views/library/message"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jmoiron/sqlx"
+	"github.com/samber/lo"
+)
+
+// entry is a setting as listed in the panel, its value
+// handled as text whatever its type.
+type entry struct {
+	title       string
+	description string
+	get         func(q sqlx.Queryer) string
+	set         func(db *sqlx.DB, value string) error
+	// options lists the values to cycle through, nil for
+	// numbers which are typed in or stepped
+	options func(db *sqlx.DB) []string
+	step    int
+	format  func(value string, categories []entity.Category) string
+	// effect, when set, gives a message to send once the value is saved
+	effect func(value string) tea.Msg
+}
+
+func entries() []entry {
+	return []entry{
+		intEntry("Auto-lock timeout",
+			"Minutes without a key pressed or a click before the library locks itself, 0 never locking.",
+			settings.AutoLockTimeout, 1, "min", "never"),
+		intEntry("Clipboard clear delay",
+			"Seconds a copied value stays on the clipboard, 0 never clearing it.",
+			settings.ClipboardClearDelay, 5, "s", "never"),
+		intEntry("Generator length",
+			"Number of characters of the generated passwords.",
+			settings.GeneratorLength, 1, "characters", ""),
+		boolEntry("Generator digits",
+			"Whether generated passwords contain digits.",
+			settings.GeneratorDigits),
+		boolEntry("Generator symbols",
+			"Whether generated passwords contain special characters.",
+			settings.GeneratorSymbols),
+		{
+			title: "Theme",
+			description: "Colors of the interface, custom themes being defined in " +
+				theme.FileName + ". The theme file picks it when unset.",
+			get: settings.Theme.Get,
+			set: func(db *sqlx.DB, value string) error {
+				return settings.Theme.Set(db, value)
+			},
+			options: func(*sqlx.DB) []string { return settings.Theme.Options() },
+			format: func(value string, _ []entity.Category) string {
+				if value == "" {
+					return "theme file"
+				}
+				return value
+			},
+			effect: func(value string) tea.Msg {
+				current, err := theme.Load(value)
+				return theme.ChangedMsg{Theme: current, Err: err}
+			},
+		},
+		{
+			title: "Keyring backend",
+			description: "Where the secret key and the salt of the account are kept, " +
+				"the keyring of the system or a file only readable by you.",
+			get:     settings.KeyringBackend.Get,
+			set:     setKeyringBackend,
+			options: func(*sqlx.DB) []string { return keychain.Backends() },
+		},
+		{
+			title:       "Default category",
+			description: "Category of the items added while no category is selected.",
+			get: func(q sqlx.Queryer) string {
+				return strconv.FormatInt(settings.DefaultCategory.Get(q), 10)
+			},
+			set: func(db *sqlx.DB, value string) error {
+				id, err := strconv.ParseInt(value, 10, 64)
+				if err != nil {
+					return err
+				}
+				return settings.DefaultCategory.Set(db, id)
+			},
+			options: categoryOptions,
+			format: func(value string, categories []entity.Category) string {
+				id, _ := strconv.ParseInt(value, 10, 64)
+				if id == 0 {
+					return "none"
+				}
+				return entity.Path(categories, id)
+			},
+		},
+		intEntry("Password history",
+			"Number of previous values kept per password, 0 keeping none.",
+			settings.PasswordHistoryRetention, 1, "values", "none"),
+		intEntry("Trash retention",
+			"Days deleted items stay in the trash, 0 keeping them until emptied.",
+			settings.TrashRetention, 1, "days", "forever"),
+	}
+}
+
+// intEntry lists a number, shown with its unit, or
+// with the zero label when zero has a meaning of its own.
+func intEntry(title, description string, setting settings.Int,
+	step int, unit, zero string) entry {
+	return entry{
+		title:       title,
+		description: description,
+		get: func(q sqlx.Queryer) string {
+			return strconv.Itoa(setting.Get(q))
+		},
+		set: func(db *sqlx.DB, value string) error {
+			number, err := strconv.Atoi(value)
+			if err != nil {
+				return errNotANumber
+			}
+			return setting.Set(db, number)
+		},
+		step: step,
+		format: func(value string, _ []entity.Category) string {
+			if value == "0" && zero != "" {
+				return zero
+			}
+			return value + " " + unit
+		},
+	}
+}
+
+func boolEntry(title, description string, setting settings.Bool) entry {
+	return entry{
+		title:       title,
+		description: description,
+		get: func(q sqlx.Queryer) string {
+			return strconv.FormatBool(setting.Get(q))
+		},
+		set: func(db *sqlx.DB, value string) error {
+			enabled, err := strconv.ParseBool(value)
+			if err != nil {
+				return err
+			}
+			return setting.Set(db, enabled)
+		},
+		options: func(*sqlx.DB) []string { return []string{"true", "false"} },
+		format: func(value string, _ []entity.Category) string {
+			if value == "true" {
+				return "on"
+			}
+			return "off"
+		},
+	}
+}
+
+func (m *Model) calculateDimension() {
+	appHeight := style.CalculateAppHeight() - 2
+	appWidth := cache.Get[int](cache.TerminalWidth) - 6
+	panelWidth := style.PanelWidth(appWidth, 60)
+	m.table.SetHeight(len(m.entries) + 1)
+	m.table.SetWidth(panelWidth)
+	m.table.SetColumnsWidth(panelWidth/2, panelWidth-panelWidth/2)
+	m.input.Width = panelWidth - 10
+	m.paneBorder = m.paneBorder.Height(appHeight).
+		MaxHeight(appHeight + 2).
+		Width(panelWidth + 4)
+}
+
+func (m *Model) sync() {
+	index := m.table.Index()
+	m.table.SetRows(
+		lo.Map(m.entries, func(entry entry, i int) table.Row {
+			value := ""
+			if i < len(m.values) {
+				value = m.values[i]
+				if entry.format != nil {
+					value = entry.format(value, m.categories)
+				}
+			}
+			return table.Row{entry.title, value}
+		}),
+	)
+	m.table.SetIndex(index)
+}
+
+// step moves the selected setting to its next or previous value.
+func (m Model) step(delta int) tea.Cmd {
+	index := m.table.Index()
+	if index >= len(m.values) {
+		return nil
+	}
+	entry, value := m.entries[index], m.values[index]
+
+	if entry.options == nil {
+		number, err := strconv.Atoi(value)
+		if err != nil {
+			return nil
+		}
+		return m.Save(index, strconv.Itoa(number+delta*entry.step))
+	}
+
+	options := entry.options(m.db)
+	if len(options) == 0 {
+		return nil
+	}
+	current := max(slices.Index(options, value), 0)
+	next := (current + delta + len(options)) % len(options)
+	return m.Save(index, options[next])
+}
+
+// edit types a new number in, or moves any other setting
+// to its next value.
+func (m *Model) edit() tea.Cmd {
+	index := m.table.Index()
+	if index >= len(m.values) {
+		return nil
+	}
+	if m.entries[index].options != nil {
+		return m.step(1)
+	}
+
+	m.err = nil
+	m.editing = true
+	m.input.Prompt = m.entries[index].title
+	m.input.SetValue(m.values[index])
+	m.input.CursorEnd()
+	return tea.Batch(
+		m.input.Focus(),
+		func() tea.Msg { return message.SetHelpKeysMsg{Keys: InputKeys} },
+	)
+}
+
+func (m Model) updateInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, InputKeys.Cancel), key.Matches(msg, InputKeys.Submit):
+		value := m.input.Value()
+		m.editing = false
+		m.input.Blur()
+		cmds := []tea.Cmd{m.SendSetKeysMsg}
+		if key.Matches(msg, InputKeys.Submit) {
+			cmds = append(cmds, m.Save(m.table.Index(), value))
+		}
+		return m, tea.Batch(cmds...)
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
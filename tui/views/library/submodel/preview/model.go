@@ -0,0 +1,108 @@
+package preview
+
+import (
+	"fmt"
+	"strings"
+
+	"viscue/tui/entity"
+	"viscue/tui/style"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Model is the read-only pane shown right of the shelf. It lists
+// every field of the selected password, each numbered so that it
+// can be copied with its shortcut. Unlike the shelf and sidebar it
+// never takes the focus, the shelf drives it instead.
+type Model struct {
+	// State
+	password      entity.Password
+	selected      bool
+	showConcealed bool
+
+	// Style
+	paneBorder lipgloss.Style
+}
+
+func New() Model {
+	return Model{
+		paneBorder: style.PaneBorderStyle.BorderForeground(style.ColorMuted),
+	}
+}
+
+// SetPassword shows the given password, masking its concealed
+// fields again when another password gets selected.
+func (m *Model) SetPassword(password entity.Password, selected bool) {
+	if password.Id != m.password.Id {
+		m.showConcealed = false
+	}
+	m.password = password
+	m.selected = selected
+}
+
+// ToggleConcealed reveals the concealed fields, or masks them back.
+func (m *Model) ToggleConcealed() {
+	m.showConcealed = !m.showConcealed
+}
+
+// SetSize sets the outer dimension of the pane.
+func (m *Model) SetSize(width, height int) {
+	m.paneBorder = m.paneBorder.Width(width).
+		Height(height).
+		MaxHeight(height + 2)
+}
+
+// Entry returns the nth copyable field, counting from one.
+func (m Model) Entry(n int) (entity.Entry, bool) {
+	entries := Entries(m.password)
+	if !m.selected || n < 1 || n > len(entries) {
+		return entity.Entry{}, false
+	}
+	return entries[n-1], true
+}
+
+func (m Model) View() string {
+	title := style.ModelTitleStyle.Render("Details")
+	if !m.selected {
+		return m.paneBorder.Render(lipgloss.JoinVertical(
+			lipgloss.Left,
+			title,
+			lipgloss.NewStyle().Foreground(style.ColorMuted).
+				Render("No item selected"),
+		))
+	}
+
+	width := m.paneBorder.GetWidth() - m.paneBorder.GetHorizontalPadding()
+	labelStyle := lipgloss.NewStyle().
+		Foreground(style.ColorMuted).
+		Width(labelWidth)
+	valueStyle := lipgloss.NewStyle().Width(max(1, width-labelWidth))
+
+	rows := []string{
+		title,
+		lipgloss.NewStyle().Bold(true).Width(width).Render(m.password.Name),
+		labelStyle.Render("Type") + valueStyle.Render(m.password.Type.String()),
+	}
+	if len(m.password.Tags) > 0 {
+		rows = append(rows, labelStyle.Render("Tags")+
+			valueStyle.Render(strings.Join(m.password.Tags, ", ")))
+	}
+	rows = append(rows, "")
+	for i, entry := range Entries(m.password) {
+		shortcut := " "
+		if i < maxShortcuts {
+			shortcut = fmt.Sprint(i + 1)
+		}
+		label := truncate(entry.Label, labelWidth-3)
+		rows = append(rows, lipgloss.JoinHorizontal(
+			lipgloss.Top,
+			labelStyle.Render(shortcut+" "+label),
+			valueStyle.Render(m.display(entry)),
+		))
+	}
+	rows = append(rows, "",
+		labelStyle.Render("Updated")+
+			valueStyle.Render(m.password.UpdatedAt.Format("2006-01-02 15:04")),
+	)
+	return m.paneBorder.Render(lipgloss.JoinVertical(lipgloss.Left, rows...))
+}
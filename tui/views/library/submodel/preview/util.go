@@ -0,0 +1,51 @@
+package preview
+
+import (
+	"strings"
+	"time"
+
+	"viscue/tui/entity"
+	"viscue/tui/tool/otp"
+)
+
+const (
+	labelWidth     = 14
+	maxShortcuts   = 9 // fields beyond have no shortcut
+	multilineShown = 3 // lines shown of multi-line values
+)
+
+// Entries lists the fields of the password that can be copied, the
+// one-time password giving its current code rather than its secret.
+func Entries(password entity.Password) []entity.Entry {
+	var entries []entity.Entry
+	for _, entry := range password.Entries() {
+		if entry.Key == entity.KeyOtp {
+			key, err := otp.Parse(entry.Value)
+			if err != nil {
+				continue
+			}
+			entry.Value, entry.Concealed = key.Code(time.Now()), false
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// display renders the value of an entry, masked when concealed
+// and cut to its first lines when long.
+func (m Model) display(entry entity.Entry) string {
+	lines := strings.Split(entry.Display(m.showConcealed), "\n")
+	if len(lines) > multilineShown {
+		lines = append(lines[:multilineShown], "…")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// truncate cuts the text to the given number of runes.
+func truncate(text string, width int) string {
+	runes := []rune(text)
+	if len(runes) <= width {
+		return text
+	}
+	return string(runes[:max(0, width-1)]) + "…"
+}
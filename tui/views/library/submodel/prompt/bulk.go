@@ -0,0 +1,349 @@
+package prompt
+
+import (
+	"crypto/rsa"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"viscue/tui/component/list"
+	"viscue/tui/entity"
+	"viscue/tui/tool/cache"
+	"viscue/tui/tool/crypto"
+	"viscue/tui/tool/settings"
+	"viscue/tui/tool/undo"
+	"viscue/tui/views/library/message"
+	"viscue/tui/views/library/submodel/history"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/log"
+	"github.com/jmoiron/sqlx"
+	"github.com/mattn/go-sqlite3"
+	"github.com/samber/lo"
+)
+
+// bulk is the payload of a prompt confirming an action
+// done to several passwords at once.
+type bulk struct {
+	action    message.BulkAction
+	passwords []entity.Password
+}
+
+// NewBulk creates the prompt confirming the action about to be done
+// to the passwords. Moving asks for the category and tagging for the
+// tags, the other actions only need the button to be pressed.
+func NewBulk(db *sqlx.DB, action message.BulkAction,
+	passwords []entity.Password) Model {
+	return New(db, bulk{action: action, passwords: passwords})
+}
+
+// buildBulkFields asks for what the action needs, if anything.
+func (m *Model) buildBulkFields(action message.BulkAction) {
+	switch action {
+	case message.BulkMove:
+		m.title = "Move Items"
+		if err := m.getCategories(); err != nil {
+			m.err = errors.New("failed building categories dropdown")
+		}
+		m.fields = []field{newField(keyCategory, "Category", m.textInputWidth())}
+		m.list = list.New(list.WithFocused(false))
+		m.list.SetHeight(4)
+		m.list.SetWidth(m.textInputWidth())
+		m.list.SetItems(m.categoryItems())
+		m.setCategoryField(m.categoryOption(0))
+		m.fields[0].Focus()
+	case message.BulkTag:
+		m.title = "Tag Items"
+		if err := m.getTags(); err != nil {
+			m.err = errors.New("failed loading tags")
+		}
+		m.fields = []field{newTagsField(nil, m.textInputWidth())}
+		m.fields[0].Focus()
+	default:
+		m.title = map[message.BulkAction]string{
+			message.BulkDelete:     "Delete Items",
+			message.BulkRestore:    "Restore Items",
+			message.BulkExport:     "Export Items",
+			message.BulkRegenerate: "Regenerate Passwords",
+		}[action]
+		m.focusSubmitButton()
+	}
+}
+
+func (m Model) isBulk() bool {
+	_, ok := m.payload.(bulk)
+	return ok
+}
+
+// summary tells what the action does, naming the first passwords.
+func (b bulk) summary() string {
+	names := lo.Map(b.passwords, func(password entity.Password, _ int) string {
+		return password.Name
+	})
+	if len(names) > 3 {
+		names = append(names[:3], fmt.Sprintf("and %d more", len(names)-3))
+	}
+	items := pluralize(len(b.passwords), "item")
+
+	var text string
+	switch b.action {
+	case message.BulkMove:
+		text = "Move " + items + " to the category below"
+	case message.BulkTag:
+		text = "Add the tags below to " + items
+	case message.BulkDelete:
+		trashed := lo.CountBy(b.passwords, func(password entity.Password) bool {
+			return password.DeletedAt.Valid
+		})
+		text = "Move " + items + " to trash"
+		if trashed == len(b.passwords) {
+			text = "Permanently delete " + items
+		}
+	case message.BulkRestore:
+		text = "Restore " + items + " from trash"
+	case message.BulkExport:
+		text = "Export " + items + " unencrypted to the home directory"
+	case message.BulkRegenerate:
+		text = "Replace the password of " + items +
+			" with a generated one, keeping the previous ones in their history"
+	}
+	return text + ":\n" + strings.Join(names, ", ")
+}
+
+// bulkView renders the summary of the action above its fields.
+func (m Model) bulkView(b bulk) string {
+	summary := lipgloss.NewStyle().
+		Width(labelWidth + m.textInputWidth()).
+		Align(lipgloss.Center).
+		Render(b.summary())
+	rows := []string{titleRenderer(m.title), summary}
+	if len(m.fields) > 0 {
+		rows = append(rows, "", lipgloss.JoinVertical(
+			lipgloss.Left,
+			m.textFields()...,
+		))
+	}
+	return textboxRenderer(lipgloss.JoinVertical(
+		lipgloss.Center,
+		append(rows, "", m.buttonView())...,
+	))
+}
+
+// BulkAppliedMsg tells that the action has been done to the
+// passwords. Exporting is left to whoever asked for it, since
+// the prompt does not know the paths of the categories.
+type BulkAppliedMsg struct {
+	Action    message.BulkAction
+	Passwords []entity.Password
+	Category  entity.Category // where the passwords were moved to
+}
+
+// submitBulk does the action to every password within a single
+// transaction, so that either all of them or none are changed.
+func (m Model) submitBulk(b bulk) error {
+	tx, err := m.db.Beginx()
+	if err != nil {
+		log.Error("prompt.(Model).submitBulk: failed to start transaction",
+			"err", err)
+		return SubmitError(errors.New("something went wrong with sqlite database"))
+	}
+
+	record := undo.Record(b.label())
+	ids := lo.Map(b.passwords, func(password entity.Password, _ int) int64 {
+		return password.Id
+	})
+	if err = record.Track(tx, undo.Passwords, ids...); err != nil {
+		_ = tx.Rollback()
+		return handleRecordError(err)
+	}
+
+	switch b.action {
+	case message.BulkMove:
+		err = moveAll(tx, b.passwords, m.destination)
+	case message.BulkTag:
+		err = tagAll(tx, b.passwords, parseTags(m.field(keyTags).Value()))
+	case message.BulkDelete:
+		err = deleteAll(tx, b.passwords)
+	case message.BulkRestore:
+		err = restoreAll(tx, b.passwords)
+	case message.BulkRegenerate:
+		err = regenerateAll(tx, b.passwords)
+	}
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	if err = record.Done(tx); err != nil {
+		_ = tx.Rollback()
+		return handleRecordError(err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error("prompt.(Model).submitBulk: failed to commit transaction",
+			"err", err)
+		return SubmitError(errors.New("something went wrong with sqlite database"))
+	}
+	record.Push()
+	return nil
+}
+
+// label names the action for the undo stack.
+func (b bulk) label() string {
+	items := pluralize(len(b.passwords), "item")
+	switch b.action {
+	case message.BulkMove:
+		return "Move " + items
+	case message.BulkTag:
+		return "Tag " + items
+	case message.BulkDelete:
+		return "Delete " + items
+	case message.BulkRestore:
+		return "Restore " + items
+	case message.BulkRegenerate:
+		return "Regenerate " + pluralize(len(b.passwords), "password")
+	}
+	return ""
+}
+
+func moveAll(tx *sqlx.Tx, passwords []entity.Password, categoryId int64) error {
+	for _, password := range passwords {
+		_, err := tx.Exec(
+			"UPDATE passwords SET category_id = ?, updated_at = ? WHERE id = ?",
+			sql.NullInt64{Int64: categoryId, Valid: categoryId > 0},
+			time.Now(), password.Id,
+		)
+		if err != nil {
+			if isConstraintError(err) {
+				return SubmitError(fmt.Errorf(
+					"an item named %s is already in this category", password.Name))
+			}
+			return handleUpsertPasswordError(err)
+		}
+	}
+	return nil
+}
+
+func tagAll(tx *sqlx.Tx, passwords []entity.Password, tags []string) error {
+	if len(tags) == 0 {
+		return SubmitError(errors.New("type the tags to add"))
+	}
+	for _, tag := range tags {
+		_, err := tx.Exec("INSERT OR IGNORE INTO tags (name) VALUES (?)", tag)
+		if err != nil {
+			log.Error("prompt.tagAll: failed inserting tag", "err", err)
+			return SubmitError(errors.New("failed saving tags"))
+		}
+		for _, password := range passwords {
+			_, err = tx.Exec(
+				`INSERT OR IGNORE INTO password_tags (password_id, tag_id)
+				SELECT ?, id FROM tags WHERE name = ?`,
+				password.Id, tag,
+			)
+			if err != nil {
+				log.Error("prompt.tagAll: failed tagging password", "err", err)
+				return SubmitError(errors.New("failed saving tags"))
+			}
+		}
+	}
+	return nil
+}
+
+// deleteAll moves the passwords to the trash, deleting
+// for good the ones already there.
+func deleteAll(tx *sqlx.Tx, passwords []entity.Password) error {
+	now := time.Now()
+	for _, password := range passwords {
+		var err error
+		if password.DeletedAt.Valid {
+			_, err = tx.Exec("DELETE FROM passwords WHERE id = ?", password.Id)
+		} else {
+			_, err = tx.Exec("UPDATE passwords SET deleted_at = ? WHERE id = ?",
+				now, password.Id)
+		}
+		if err != nil {
+			log.Error("prompt.deleteAll: failed deleting password", "err", err)
+			return SubmitError(errors.New("failed deleting items"))
+		}
+	}
+	if err := PruneTags(tx); err != nil {
+		log.Error("prompt.deleteAll: failed pruning tags", "err", err)
+		return SubmitError(errors.New("failed deleting items"))
+	}
+	return nil
+}
+
+func restoreAll(tx *sqlx.Tx, passwords []entity.Password) error {
+	for _, password := range passwords {
+		_, err := tx.Exec("UPDATE passwords SET deleted_at = NULL WHERE id = ?",
+			password.Id)
+		if err != nil {
+			if isConstraintError(err) {
+				return SubmitError(fmt.Errorf(
+					"another item has taken the name %s, rename it first",
+					password.Name))
+			}
+			log.Error("prompt.restoreAll: failed restoring password", "err", err)
+			return SubmitError(errors.New("failed restoring items"))
+		}
+	}
+	return nil
+}
+
+// regenerateAll replaces the password of every item whose type has
+// one, archiving the previous password in its history.
+func regenerateAll(tx *sqlx.Tx, passwords []entity.Password) error {
+	publicKey := cache.Get[*rsa.PublicKey](cache.PublicKey)
+	length := settings.GeneratorLength.Get(tx)
+	digits := settings.GeneratorDigits.Get(tx)
+	symbols := settings.GeneratorSymbols.Get(tx)
+
+	regenerated := 0
+	for _, password := range passwords {
+		if _, ok := password.Type.Schema().Field(entity.KeyPassword); !ok {
+			continue
+		}
+		generated, err := crypto.GeneratePassword(length, digits, symbols)
+		if err != nil {
+			log.Error("prompt.regenerateAll: failed generating password",
+				"err", err)
+			return SubmitError(errors.New("failed to generate random password"))
+		}
+		enc := password.Copy()
+		enc.Password = generated
+		if err = enc.Encrypt(publicKey); err != nil {
+			return SubmitError(fmt.Errorf("failed to encrypt entity: %w", err))
+		}
+		_, err = tx.Exec(
+			"UPDATE passwords SET password = ?, updated_at = ? WHERE id = ?",
+			enc.Password, time.Now(), password.Id,
+		)
+		if err != nil {
+			log.Error("prompt.regenerateAll: failed updating password",
+				"err", err)
+			return SubmitError(errors.New("failed saving passwords"))
+		}
+		if err = history.Archive(tx, password.Id, password.Password); err != nil {
+			return SubmitError(err)
+		}
+		regenerated++
+	}
+	if regenerated == 0 {
+		return SubmitError(errors.New("none of the items has a password"))
+	}
+	return nil
+}
+
+func isConstraintError(err error) bool {
+	var sqliteErr sqlite3.Error
+	return errors.As(err, &sqliteErr) && sqliteErr.Code == sqlite3.ErrConstraint
+}
+
+// pluralize prefixes the noun with the count, adding an s past one.
+func pluralize(count int, noun string) string {
+	if count == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", count, noun)
+}
@@ -2,14 +2,22 @@ package prompt
 
 import (
 	"crypto/rsa"
+	"database/sql"
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"viscue/tui/entity"
 	"viscue/tui/tool/cache"
+	"viscue/tui/tool/otp"
+	"viscue/tui/tool/undo"
 	"viscue/tui/views/library/message"
+	"viscue/tui/views/library/submodel/history"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/log"
+	"github.com/jmoiron/sqlx"
 )
 
 func (m Model) SendSetKeysMsg() tea.Msg {
@@ -28,7 +36,7 @@ func (m Model) Close() tea.Msg {
 	switch m.payload.(type) {
 	case entity.Category:
 		return message.ClosePromptMsg[entity.Category]{}
-	case entity.Password:
+	case entity.Password, bulk:
 		return message.ClosePromptMsg[entity.Password]{}
 	default:
 		return nil
@@ -44,79 +52,293 @@ type DataSubmittedMsg[T any] struct {
 func (m Model) Submit() tea.Msg {
 	switch payload := m.payload.(type) {
 	case entity.Category:
-		payload = m.buildCategoryEntity()
-		if payload.Id == 0 {
-			res, err := m.db.NamedExec(`INSERT INTO categories (name) VALUES (:name) RETURNING id`,
-				&payload)
-			if err != nil {
-				return handleUpsertCategoryError(err)
-			}
-			id, err := res.LastInsertId()
-			if err != nil {
-				return tea.Sequence(func() tea.Msg {
-					return message.ShouldReloadMsg{}
-				}, func() tea.Msg {
-					return message.ClosePromptMsg[entity.Category]{}
-				})()
-			}
-			payload.Id = id
-		} else {
-			_, err := m.db.NamedExec(
-				"UPDATE categories SET name = :name WHERE id = :id",
-				payload,
-			)
-			if err != nil {
-				return handleUpsertCategoryError(err)
-			}
-		}
-		return DataSubmittedMsg[entity.Category]{Data: payload}
+		return m.submitCategory()
 	case entity.Password:
-		payload = m.buildPasswordEntity()
-		publicKey := cache.Get[*rsa.PublicKey](cache.PublicKey)
-		enc := payload.Copy()
-		if err := enc.Encrypt(publicKey); err != nil {
-			return SubmitError(fmt.Errorf("failed to encrypt entity: %w", err))
-		}
-		if payload.Id == 0 {
-			res, err := m.db.NamedExec(
-				`INSERT INTO
-			    	passwords (name, category_id, email, username, password) 
-				VALUES (:name, :category_id, :email, :username, :password)
-				RETURNING id`,
-				&enc,
-			)
-			if err != nil {
-				return handleUpsertPasswordError(err)
+		return m.submitPassword()
+	case bulk:
+		if payload.action != message.BulkExport {
+			if err := m.submitBulk(payload); err != nil {
+				return err
 			}
-			id, err := res.LastInsertId()
-			if err != nil {
-				return tea.Sequence(func() tea.Msg {
-					return message.ShouldReloadMsg{}
-				}, func() tea.Msg {
-					return message.ClosePromptMsg[entity.Password]{}
-				})()
+		}
+		msg := BulkAppliedMsg{Action: payload.action, Passwords: payload.passwords}
+		if payload.action == message.BulkMove {
+			msg.Category = m.categoryOption(m.destination)
+		}
+		return msg
+	}
+	return nil
+}
+
+func (m Model) submitCategory() tea.Msg {
+	payload := m.buildCategoryEntity()
+	if err := m.checkCategoryParent(payload); err != nil {
+		return err
+	}
+
+	tx, err := m.db.Beginx()
+	if err != nil {
+		log.Error("prompt.(Model).submitCategory: failed to start transaction",
+			"err", err)
+		return SubmitError(errors.New("something went wrong with sqlite database"))
+	}
+
+	var record *undo.Recorder
+	if payload.Id == 0 {
+		record = undo.Record("Add category " + payload.Name)
+		res, err := tx.NamedExec(`INSERT INTO categories (name, parent_id)
+			VALUES (:name, :parent_id) RETURNING id`,
+			&payload)
+		if err != nil {
+			_ = tx.Rollback()
+			return handleUpsertCategoryError(err)
+		}
+		id, err := res.LastInsertId()
+		if err != nil {
+			_ = tx.Rollback()
+			return handleUpsertCategoryError(err)
+		}
+		payload.Id = id
+		record.Created(undo.Categories, id)
+	} else {
+		record = undo.Record("Edit category " + payload.Name)
+		if err = record.Track(tx, undo.Categories, payload.Id); err != nil {
+			_ = tx.Rollback()
+			return handleRecordError(err)
+		}
+		_, err = tx.NamedExec(
+			"UPDATE categories SET name = :name, parent_id = :parent_id WHERE id = :id",
+			payload,
+		)
+		if err != nil {
+			_ = tx.Rollback()
+			return handleUpsertCategoryError(err)
+		}
+	}
+
+	if err = record.Done(tx); err != nil {
+		_ = tx.Rollback()
+		return handleRecordError(err)
+	}
+	if err = tx.Commit(); err != nil {
+		return handleUpsertCategoryError(err)
+	}
+	record.Push()
+	return DataSubmittedMsg[entity.Category]{Data: payload}
+}
+
+func (m Model) submitPassword() tea.Msg {
+	payload := m.buildPasswordEntity()
+	if err := payload.Validate(); err != nil {
+		return SubmitError(err)
+	}
+	if payload.Otp != "" {
+		key, err := otp.Parse(payload.Otp)
+		if err != nil {
+			return SubmitError(fmt.Errorf("one-time password: %w", err))
+		}
+		if key.Account == "" {
+			key.Account = payload.Name
+		}
+		payload.Otp = key.String()
+	}
+	payload.UpdatedAt = time.Now()
+	if payload.Id == 0 {
+		payload.CreatedAt = payload.UpdatedAt
+	}
+	publicKey := cache.Get[*rsa.PublicKey](cache.PublicKey)
+	enc := payload.Copy()
+	if err := enc.Encrypt(publicKey); err != nil {
+		return SubmitError(fmt.Errorf("failed to encrypt entity: %w", err))
+	}
+
+	tx, err := m.db.Beginx()
+	if err != nil {
+		log.Error("prompt.(Model).submitPassword: failed to start transaction",
+			"err", err)
+		return SubmitError(errors.New("something went wrong with sqlite database"))
+	}
+
+	var record *undo.Recorder
+	if payload.Id == 0 {
+		record = undo.Record("Add " + payload.Name)
+		res, err := tx.NamedExec(
+			`INSERT INTO
+		    	passwords (type, name, category_id, email, username, password,
+		    		otp, notes, data, created_at, updated_at)
+			VALUES (:type, :name, :category_id, :email, :username, :password,
+				:otp, :notes, :data, :created_at, :updated_at)
+			RETURNING id`,
+			&enc,
+		)
+		if err != nil {
+			_ = tx.Rollback()
+			return handleUpsertPasswordError(err)
+		}
+		id, err := res.LastInsertId()
+		if err != nil {
+			_ = tx.Rollback()
+			return handleUpsertPasswordError(err)
+		}
+		payload.Id = id
+		record.Created(undo.Passwords, id)
+	} else {
+		record = undo.Record("Edit " + payload.Name)
+		if err = record.Track(tx, undo.Passwords, payload.Id); err != nil {
+			_ = tx.Rollback()
+			return handleRecordError(err)
+		}
+		_, err = tx.NamedExec(
+			`UPDATE passwords SET
+					category_id = :category_id,
+					name = :name,
+					email = :email,
+					username = :username,
+					password = :password,
+					otp = :otp,
+					notes = :notes,
+					data = :data,
+					updated_at = :updated_at
+				WHERE id = :id`,
+			&enc,
+		)
+		if err != nil {
+			_ = tx.Rollback()
+			return handleUpsertPasswordError(err)
+		}
+		if previous := m.payload.(entity.Password).Password; previous != payload.Password {
+			if err = history.Archive(tx, payload.Id, previous); err != nil {
+				_ = tx.Rollback()
+				return SubmitError(err)
 			}
-			payload.Id = id
-		} else {
-			_, err := m.db.NamedExec(
-				`UPDATE passwords SET
-						category_id = :category_id,
-						name = :name,
-						email = :email,
-						username = :username,
-						password = :password
-					WHERE id = :id`,
-				&enc,
+		}
+	}
+
+	if err = saveFields(tx, payload.Id, payload.Fields); err != nil {
+		_ = tx.Rollback()
+		return SubmitError(err)
+	}
+	if err = saveURLs(tx, payload.Id, payload.URLs); err != nil {
+		_ = tx.Rollback()
+		return SubmitError(err)
+	}
+	if err = saveTags(tx, payload.Id, payload.Tags); err != nil {
+		_ = tx.Rollback()
+		return SubmitError(err)
+	}
+	if err = record.Done(tx); err != nil {
+		_ = tx.Rollback()
+		return handleRecordError(err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return handleUpsertPasswordError(err)
+	}
+	record.Push()
+	return DataSubmittedMsg[entity.Password]{Data: payload}
+}
+
+// saveFields replaces the custom fields of the password.
+func saveFields(tx *sqlx.Tx, passwordId int64, fields []entity.Field) error {
+	_, err := tx.Exec("DELETE FROM password_fields WHERE password_id = ?",
+		passwordId)
+	if err != nil {
+		log.Error("prompt.saveFields: failed clearing fields", "err", err)
+		return errors.New("failed saving custom fields")
+	}
+
+	publicKey := cache.Get[*rsa.PublicKey](cache.PublicKey)
+	for i, field := range fields {
+		field.PasswordId = passwordId
+		field.Position = i
+		if err = field.Encrypt(publicKey); err != nil {
+			return fmt.Errorf("failed to encrypt custom field: %w", err)
+		}
+		_, err = tx.NamedExec(
+			`INSERT INTO password_fields
+				(password_id, name, value, concealed, position)
+			VALUES (:password_id, :name, :value, :concealed, :position)`,
+			&field,
+		)
+		if err != nil {
+			log.Error("prompt.saveFields: failed inserting field", "err", err)
+			return errors.New("failed saving custom fields")
+		}
+	}
+	return nil
+}
+
+// saveURLs replaces the URLs of the password.
+func saveURLs(tx *sqlx.Tx, passwordId int64, urls []entity.URL) error {
+	_, err := tx.Exec("DELETE FROM password_urls WHERE password_id = ?",
+		passwordId)
+	if err != nil {
+		log.Error("prompt.saveURLs: failed clearing urls", "err", err)
+		return errors.New("failed saving urls")
+	}
+
+	publicKey := cache.Get[*rsa.PublicKey](cache.PublicKey)
+	for i, url := range urls {
+		url.PasswordId = passwordId
+		url.Position = i
+		if err = url.Encrypt(publicKey); err != nil {
+			return fmt.Errorf("failed to encrypt url: %w", err)
+		}
+		_, err = tx.NamedExec(
+			`INSERT INTO password_urls
+				(password_id, url, match_rule, position)
+			VALUES (:password_id, :url, :match_rule, :position)`,
+			&url,
+		)
+		if err != nil {
+			log.Error("prompt.saveURLs: failed inserting url", "err", err)
+			return errors.New("failed saving urls")
+		}
+	}
+	return nil
+}
+
+// saveTags replaces the tags of the password, creating the
+// missing ones and dropping those no longer used.
+func saveTags(tx *sqlx.Tx, passwordId int64, tags []string) error {
+	_, err := tx.Exec("DELETE FROM password_tags WHERE password_id = ?",
+		passwordId)
+	if err != nil {
+		log.Error("prompt.saveTags: failed clearing tags", "err", err)
+		return errors.New("failed saving tags")
+	}
+
+	for _, tag := range tags {
+		_, err = tx.Exec("INSERT OR IGNORE INTO tags (name) VALUES (?)", tag)
+		if err == nil {
+			_, err = tx.Exec(
+				`INSERT INTO password_tags (password_id, tag_id)
+				SELECT ?, id FROM tags WHERE name = ?`,
+				passwordId, tag,
 			)
-			if err != nil {
-				return handleUpsertPasswordError(err)
-			}
 		}
-		return DataSubmittedMsg[entity.Password]{Data: payload}
+		if err != nil {
+			log.Error("prompt.saveTags: failed inserting tag", "err", err)
+			return errors.New("failed saving tags")
+		}
+	}
+
+	if err = PruneTags(tx); err != nil {
+		log.Error("prompt.saveTags: failed pruning tags", "err", err)
+		return errors.New("failed saving tags")
 	}
 	return nil
 }
 
+// PruneTags deletes the tags no password uses anymore.
+func PruneTags(e sqlx.Execer) error {
+	_, err := e.Exec(
+		`DELETE FROM tags
+		WHERE id NOT IN (SELECT DISTINCT tag_id FROM password_tags)`,
+	)
+	return err
+}
+
 type DeleteErrorMsg struct {
 	Error error
 }
@@ -127,6 +349,12 @@ type DeleteConfirmedMsg[T interface {
 	Payload T
 }
 
+// TrashedMsg tells that a password has been moved to the trash,
+// deleting it from there being final.
+type TrashedMsg struct {
+	Payload entity.Password
+}
+
 func (m Model) Delete() tea.Msg {
 	if !m.isDeletion {
 		return nil
@@ -134,40 +362,227 @@ func (m Model) Delete() tea.Msg {
 
 	switch payload := m.payload.(type) {
 	case entity.Category:
-		_, err := m.db.Exec("DELETE FROM categories WHERE id = ?", payload.Id)
-		if err != nil {
-			return err
-		}
-		return DeleteConfirmedMsg[entity.Category]{
-			Payload: payload,
-		}
+		return m.deleteCategory(payload)
 	case entity.Password:
-		_, err := m.db.Exec("DELETE FROM passwords WHERE id = ?", payload.Id)
-		if err != nil {
-			return err
-		}
-		return DeleteConfirmedMsg[entity.Password]{
-			Payload: payload,
-		}
+		return m.deletePassword(payload)
 	default:
 		return nil
 	}
 }
 
+// deleteCategory moves the passwords within the category to the
+// destination and its subcategories up to its parent before deleting it.
+func (m Model) deleteCategory(payload entity.Category) tea.Msg {
+	tx, err := m.db.Beginx()
+	if err != nil {
+		return err
+	}
+
+	record := undo.Record("Delete category " + payload.Name)
+	var passwordIds, subcategoryIds []int64
+	err = tx.Select(&passwordIds,
+		"SELECT id FROM passwords WHERE category_id = ?", payload.Id)
+	if err == nil {
+		err = tx.Select(&subcategoryIds,
+			"SELECT id FROM categories WHERE parent_id = ?", payload.Id)
+	}
+	if err == nil {
+		err = record.Track(tx, undo.Categories, payload.Id)
+	}
+	if err == nil {
+		err = record.Track(tx, undo.Passwords, passwordIds...)
+	}
+	if err == nil {
+		err = record.Track(tx, undo.Categories, subcategoryIds...)
+	}
+	if err != nil {
+		_ = tx.Rollback()
+		return handleRecordError(err)
+	}
+
+	if m.destination == trashDestination {
+		_, err = tx.Exec(
+			`UPDATE passwords SET deleted_at = ?
+			WHERE category_id = ? AND deleted_at IS NULL`,
+			time.Now(), payload.Id)
+	} else {
+		_, err = tx.Exec("UPDATE passwords SET category_id = ? WHERE category_id = ?",
+			sql.NullInt64{Int64: m.destination, Valid: m.destination > 0},
+			payload.Id)
+	}
+	if err != nil {
+		_ = tx.Rollback()
+		return handleUpsertPasswordError(err)
+	}
+	// Subcategories move up to the parent of the deleted category
+	_, err = tx.Exec("UPDATE categories SET parent_id = ? WHERE parent_id = ?",
+		payload.ParentId, payload.Id)
+	if err == nil {
+		_, err = tx.Exec("DELETE FROM categories WHERE id = ?", payload.Id)
+	}
+	if isConstraintError(err) {
+		// Names are only unique within their parent
+		_ = tx.Rollback()
+		return SubmitError(errors.New("a subcategory is named like a category of the parent"))
+	} else if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	if err = record.Done(tx); err != nil {
+		_ = tx.Rollback()
+		return handleRecordError(err)
+	}
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+	record.Push()
+	return DeleteConfirmedMsg[entity.Category]{
+		Payload: payload,
+	}
+}
+
+// deletePassword moves the password to the trash, deleting it
+// for good when it is already there.
+func (m Model) deletePassword(payload entity.Password) tea.Msg {
+	tx, err := m.db.Beginx()
+	if err != nil {
+		return err
+	}
+
+	trashed := payload.DeletedAt.Valid
+	record := undo.Record("Trash " + payload.Name)
+	if trashed {
+		record = undo.Record("Delete " + payload.Name)
+	}
+	if err = record.Track(tx, undo.Passwords, payload.Id); err != nil {
+		_ = tx.Rollback()
+		return handleRecordError(err)
+	}
+
+	if !trashed {
+		payload.DeletedAt = sql.NullTime{Time: time.Now(), Valid: true}
+		_, err = tx.Exec("UPDATE passwords SET deleted_at = ? WHERE id = ?",
+			payload.DeletedAt, payload.Id)
+	} else {
+		_, err = tx.Exec("DELETE FROM passwords WHERE id = ?", payload.Id)
+		if err == nil {
+			if pruneErr := PruneTags(tx); pruneErr != nil {
+				log.Error("prompt.(Model).deletePassword: failed pruning tags",
+					"err", pruneErr)
+			}
+		}
+	}
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	if err = record.Done(tx); err != nil {
+		_ = tx.Rollback()
+		return handleRecordError(err)
+	}
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+	record.Push()
+
+	if !trashed {
+		return TrashedMsg{Payload: payload}
+	}
+	return DeleteConfirmedMsg[entity.Password]{
+		Payload: payload,
+	}
+}
+
 func (m Model) buildCategoryEntity() entity.Category {
+	payload := m.payload.(entity.Category)
 	return entity.Category{
-		Id:   m.payload.(entity.Category).Id,
-		Name: strings.TrimSpace(m.fields[0].Value()),
+		Id:       payload.Id,
+		Name:     strings.TrimSpace(m.fields[0].Value()),
+		ParentId: payload.ParentId,
 	}
 }
 
+// checkCategoryParent refuses to move a category under itself or one of
+// its subcategories. The tree is read again since it could have changed
+// since the prompt was opened.
+func (m Model) checkCategoryParent(category entity.Category) SubmitError {
+	if category.Id == 0 || !category.ParentId.Valid {
+		return nil
+	}
+	var cycle bool
+	err := m.db.Get(&cycle, `
+		WITH RECURSIVE subtree(id) AS (
+			SELECT ?
+			UNION
+			SELECT categories.id FROM categories
+			JOIN subtree ON categories.parent_id = subtree.id
+		)
+		SELECT EXISTS (SELECT 1 FROM subtree WHERE id = ?)`,
+		category.Id, category.ParentId.Int64,
+	)
+	if err != nil {
+		log.Error("prompt.(Model).checkCategoryParent: failed reading subcategories",
+			"err", err)
+		return SubmitError(errors.New("something went wrong with sqlite database"))
+	}
+	if cycle {
+		return SubmitError(errors.New(
+			"a category cannot be moved under itself or one of its subcategories"))
+	}
+	return nil
+}
+
 func (m Model) buildPasswordEntity() entity.Password {
-	return entity.Password{
-		Id:         m.payload.(entity.Password).Id,
-		Name:       strings.TrimSpace(m.fields[0].Value()),
-		CategoryId: m.payload.(entity.Password).CategoryId,
-		Email:      strings.ToLower(strings.TrimSpace(m.fields[2].Value())),
-		Username:   strings.TrimSpace(m.fields[3].Value()),
-		Password:   strings.TrimSpace(m.fields[4].Value()),
+	payload := m.payload.(entity.Password)
+	password := entity.Password{
+		Id:         payload.Id,
+		Type:       payload.Type,
+		Name:       strings.TrimSpace(m.field(keyName).Value()),
+		CategoryId: payload.CategoryId,
+		CreatedAt:  payload.CreatedAt,
+		LastUsedAt: payload.LastUsedAt,
+		Favorite:   payload.Favorite,
+	}
+
+	for _, field := range m.fields {
+		switch field.key {
+		case keyName, keyCategory, keyCustomLabel, keyCustomValue:
+			continue
+		case keyTags:
+			password.Tags = parseTags(field.Value())
+			continue
+		case keyURL:
+			if url := strings.TrimSpace(field.Value()); url != "" {
+				password.URLs = append(password.URLs, entity.URL{
+					PasswordId: password.Id,
+					URL:        url,
+					Rule:       field.rule,
+				})
+			}
+			continue
+		}
+		value := strings.TrimSpace(field.Value())
+		if field.key == entity.KeyEmail {
+			value = strings.ToLower(value)
+		}
+		password.SetValue(field.key, value)
+	}
+
+	for i := 0; i+1 < len(m.fields); i++ {
+		if m.fields[i].key != keyCustomLabel {
+			continue
+		}
+		name := strings.TrimSpace(m.fields[i].Value())
+		value := m.fields[i+1]
+		if name == "" && value.Value() == "" {
+			continue
+		}
+		password.Fields = append(password.Fields, entity.Field{
+			PasswordId: password.Id,
+			Name:       name,
+			Value:      value.Value(),
+			Concealed:  value.concealed,
+		})
 	}
+	return password
 }
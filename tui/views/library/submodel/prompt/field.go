@@ -0,0 +1,226 @@
+package prompt
+
+import (
+	"strings"
+
+	"viscue/tui/entity"
+	"viscue/tui/style"
+	"viscue/tui/tool/urlmatch"
+
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Keys identifying the fields of the prompt.
+const (
+	keyName        = "name"
+	keyCategory    = "category"
+	keyParent      = "parent"
+	keyDestination = "destination"
+	keyTags        = "tags"
+	keyCustomLabel = "custom_label"
+	keyCustomValue = "custom_value"
+	keyURL         = "url"
+)
+
+// The remaining fields are keyed after entity.SchemaField.Key.
+
+const (
+	labelWidth = 10
+	areaHeight = 3
+	ruleWidth  = 9
+)
+
+// field is a single input of the prompt. It is either a one
+// line text input or a multi-line text area for long values.
+type field struct {
+	key       string
+	input     textinput.Model
+	area      textarea.Model
+	multiline bool
+	concealed bool
+	rule      urlmatch.Rule // match rule of URL fields
+}
+
+func newField(key, prompt string, width int) field {
+	input := textinput.New()
+	input.Prompt = prompt
+	input.PromptStyle = style.TextInputPromptStyle.Width(labelWidth)
+	input.Cursor.SetMode(cursor.CursorBlink)
+	input.Width = width
+	return field{key: key, input: input}
+}
+
+func newAreaField(key, prompt string, width int) field {
+	area := textarea.New()
+	area.Prompt = ""
+	area.ShowLineNumbers = false
+	area.CharLimit = 0
+	area.SetWidth(width)
+	area.SetHeight(areaHeight)
+	area.Cursor.SetMode(cursor.CursorBlink)
+	area.FocusedStyle.CursorLine = lipgloss.NewStyle()
+	area.Blur()
+
+	f := newField(key, prompt, width)
+	f.area = area
+	f.multiline = true
+	return f
+}
+
+// newSchemaField creates the input of a value asked by an item type.
+func newSchemaField(schema entity.SchemaField, value string, width int) field {
+	f := newField(schema.Key, schema.Label, width)
+	if schema.Multiline {
+		f = newAreaField(schema.Key, schema.Label, width)
+		f.area.Placeholder = schema.Placeholder
+	}
+	f.input.Placeholder = schema.Placeholder
+	f.SetValue(value)
+	f.SetConcealed(schema.Concealed)
+	return f
+}
+
+// newURLField creates the input of a URL along with its match rule.
+func newURLField(url string, rule urlmatch.Rule, width int) field {
+	f := newField(keyURL, "URL", width-ruleWidth)
+	f.input.Placeholder = "https://example.com"
+	f.SetValue(url)
+	f.rule = rule
+	if f.rule == "" {
+		f.rule = urlmatch.Domain
+	}
+	return f
+}
+
+// newTagsField creates the input of comma separated tags, suggesting
+// the existing ones for the tag being typed.
+func newTagsField(tags []string, width int) field {
+	f := newField(keyTags, "Tags", width)
+	f.input.Placeholder = "comma separated"
+	f.input.ShowSuggestions = true
+	// Tab and ctrl+n/p are taken by the prompt itself
+	f.input.KeyMap.AcceptSuggestion = key.NewBinding(key.WithKeys("right"))
+	f.input.KeyMap.NextSuggestion = key.NewBinding(key.WithKeys("down"))
+	f.input.KeyMap.PrevSuggestion = key.NewBinding(key.WithKeys("up"))
+	f.SetValue(strings.Join(tags, ", "))
+	return f
+}
+
+// newCustomFields creates the label and value inputs of a custom
+// field. The label input takes the place of the prompt.
+func newCustomFields(name, value string, concealed bool, width int) [2]field {
+	label := newField(keyCustomLabel, "", labelWidth-3)
+	label.input.PromptStyle = lipgloss.NewStyle()
+	label.input.Placeholder = "label"
+	label.input.SetValue(name)
+
+	input := newField(keyCustomValue, "", width)
+	input.input.PromptStyle = lipgloss.NewStyle()
+	input.input.Placeholder = "value"
+	input.SetValue(value)
+	input.SetConcealed(concealed)
+	return [2]field{label, input}
+}
+
+func (f *field) Focus() tea.Cmd {
+	if f.multiline {
+		return f.area.Focus()
+	}
+	return f.input.Focus()
+}
+
+func (f *field) Blur() {
+	if f.multiline {
+		f.area.Blur()
+		return
+	}
+	f.input.Blur()
+}
+
+func (f field) Focused() bool {
+	if f.multiline {
+		return f.area.Focused()
+	}
+	return f.input.Focused()
+}
+
+func (f field) Value() string {
+	if f.multiline {
+		return f.area.Value()
+	}
+	return f.input.Value()
+}
+
+func (f *field) SetValue(value string) {
+	if f.multiline {
+		f.area.SetValue(value)
+		return
+	}
+	f.input.SetValue(value)
+}
+
+// SetConcealed masks the value of the field until revealed.
+func (f *field) SetConcealed(concealed bool) {
+	f.concealed = concealed
+	f.Reveal(!concealed)
+}
+
+// Reveal shows or masks the value of a concealed field.
+func (f *field) Reveal(reveal bool) {
+	if reveal || !f.concealed {
+		f.input.EchoMode = textinput.EchoNormal
+		return
+	}
+	f.input.EchoMode = textinput.EchoPassword
+	f.input.EchoCharacter = '•'
+}
+
+func (f field) Update(msg tea.Msg) (field, tea.Cmd) {
+	var cmd tea.Cmd
+	if f.multiline {
+		f.area, cmd = f.area.Update(msg)
+	} else {
+		f.input, cmd = f.input.Update(msg)
+	}
+	return f, cmd
+}
+
+func (f field) View() string {
+	switch {
+	case f.multiline && f.input.EchoMode == textinput.EchoPassword:
+		// Text areas can't mask their value, hide it altogether
+		mask := strings.Repeat("•", 12)
+		if f.area.Value() == "" {
+			mask = ""
+		}
+		return lipgloss.JoinHorizontal(
+			lipgloss.Top,
+			f.input.PromptStyle.Render(f.input.Prompt),
+			lipgloss.NewStyle().Height(areaHeight).Render(mask),
+		)
+	case f.multiline:
+		return lipgloss.JoinHorizontal(
+			lipgloss.Top,
+			f.input.PromptStyle.Render(f.input.Prompt),
+			f.area.View(),
+		)
+	case f.key == keyURL:
+		return lipgloss.JoinHorizontal(
+			lipgloss.Top,
+			f.input.View(),
+			lipgloss.NewStyle().Foreground(style.ColorMuted).
+				Width(ruleWidth).Align(lipgloss.Right).
+				Render("["+string(f.rule)+"]"),
+		)
+	case f.key == keyCustomLabel:
+		return style.TextInputPromptStyle.Width(labelWidth).
+			Render(f.input.View())
+	default:
+		return f.input.View()
+	}
+}
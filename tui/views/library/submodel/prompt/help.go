@@ -1,6 +1,10 @@
 package prompt
 
-import "github.com/charmbracelet/bubbles/key"
+import (
+	"viscue/tui/tool/keymap"
+
+	"github.com/charmbracelet/bubbles/key"
+)
 
 type KeyMap struct {
 	Cycle  key.Binding
@@ -39,6 +43,12 @@ type PasswordKeyMap struct {
 	KeyMap
 	TogglePasswordVisibility key.Binding
 	GeneratePassword         key.Binding
+	AddField                 key.Binding
+	RemoveField              key.Binding
+	ToggleConcealed          key.Binding
+	AddURL                   key.Binding
+	CycleRule                key.Binding
+	CycleType                key.Binding
 }
 
 func (k PasswordKeyMap) ShortHelp() []key.Binding {
@@ -49,6 +59,8 @@ func (k PasswordKeyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
 		{k.Cycle, k.Close, k.Submit},
 		{k.TogglePasswordVisibility, k.GeneratePassword},
+		{k.AddField, k.RemoveField, k.ToggleConcealed},
+		{k.AddURL, k.CycleRule, k.CycleType},
 	}
 }
 
@@ -62,6 +74,30 @@ var PasswordKeys = PasswordKeyMap{
 		key.WithKeys("ctrl+g"),
 		key.WithHelp("ctrl+g", "generate random password"),
 	),
+	AddField: key.NewBinding(
+		key.WithKeys("ctrl+n"),
+		key.WithHelp("ctrl+n", "add custom field"),
+	),
+	RemoveField: key.NewBinding(
+		key.WithKeys("ctrl+x"),
+		key.WithHelp("ctrl+x", "remove custom field or url"),
+	),
+	ToggleConcealed: key.NewBinding(
+		key.WithKeys("ctrl+t"),
+		key.WithHelp("ctrl+t", "toggle field concealed"),
+	),
+	AddURL: key.NewBinding(
+		key.WithKeys("ctrl+u"),
+		key.WithHelp("ctrl+u", "add url"),
+	),
+	CycleRule: key.NewBinding(
+		key.WithKeys("ctrl+r"),
+		key.WithHelp("ctrl+r", "cycle url match rule"),
+	),
+	CycleType: key.NewBinding(
+		key.WithKeys("ctrl+y"),
+		key.WithHelp("ctrl+y", "change item type"),
+	),
 }
 
 type DropdownActiveKeyMap struct {
@@ -97,3 +133,31 @@ var DropdownKeys = DropdownActiveKeyMap{
 		key.WithHelp("esc", "cancel"),
 	),
 }
+
+// ApplyKeymap rebinds the keys of the prompts and of their
+// dropdowns remapped in the config.
+func ApplyKeymap(config keymap.Config) error {
+	err := config.Apply("prompt", keymap.Section{
+		"cycle":            &BaseKeys.Cycle,
+		"close":            &BaseKeys.Close,
+		"submit":           &BaseKeys.Submit,
+		"toggle_password":  &PasswordKeys.TogglePasswordVisibility,
+		"generate":         &PasswordKeys.GeneratePassword,
+		"add_field":        &PasswordKeys.AddField,
+		"remove_field":     &PasswordKeys.RemoveField,
+		"toggle_concealed": &PasswordKeys.ToggleConcealed,
+		"add_url":          &PasswordKeys.AddURL,
+		"cycle_rule":       &PasswordKeys.CycleRule,
+		"cycle_type":       &PasswordKeys.CycleType,
+	})
+	if err != nil {
+		return err
+	}
+	PasswordKeys.KeyMap = BaseKeys
+	return config.Apply("dropdown", keymap.Section{
+		"up":     &DropdownKeys.Up,
+		"down":   &DropdownKeys.Down,
+		"select": &DropdownKeys.Select,
+		"cancel": &DropdownKeys.Cancel,
+	})
+}
@@ -3,11 +3,13 @@ package prompt
 import (
 	"errors"
 	"fmt"
+	"strings"
 
 	"viscue/tui/component/list"
 	"viscue/tui/entity"
 	"viscue/tui/style"
 	"viscue/tui/tool/cache"
+	"viscue/tui/tool/zone"
 	"viscue/tui/views/library/message"
 
 	"github.com/charmbracelet/bubbles/cursor"
@@ -16,7 +18,6 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/jmoiron/sqlx"
-	"github.com/samber/lo"
 )
 
 var (
@@ -24,15 +25,15 @@ var (
 
 	textboxRenderer = lipgloss.NewStyle().
 			Border(lipgloss.RoundedBorder()).
-			BorderForeground(style.ColorPurple).
+			BorderForeground(style.ColorAccent).
 			Padding(1).
 			Render
 	titleRenderer = lipgloss.NewStyle().Bold(true).
 			BorderStyle(lipgloss.NormalBorder()).
 			BorderBottom(true).
 			Padding(0, 2).
-			BorderForeground(style.ColorPurplePale).
-			Foreground(style.ColorPurplePale).
+			BorderForeground(style.ColorAccentPale).
+			Foreground(style.ColorAccentPale).
 			MarginBottom(2).
 			Render
 )
@@ -44,7 +45,8 @@ type Model struct {
 	db *sqlx.DB
 
 	categories      []entity.Category
-	fields          []textinput.Model
+	tags            []string // names of every existing tag
+	fields          []field
 	list            list.Model
 	button          lipgloss.Style
 	payload         any // holds either Password or Category entity.
@@ -55,6 +57,8 @@ type Model struct {
 	pointer         int
 	showPassword    bool
 	isDeletion      bool
+	destination     int64  // where the items of a deleted category go
+	zoneId          string // prefixes the zones of the fields for mouse events
 }
 
 type Option func(*Model)
@@ -75,6 +79,7 @@ func New(db *sqlx.DB, payload any, opts ...Option) Model {
 		button:          style.ButtonStyle.SetString("Submit"),
 		availableWidth:  termWidth,
 		availableHeight: appHeight,
+		zoneId:          zone.NewPrefix(),
 	}
 
 	for _, opt := range opts {
@@ -86,6 +91,7 @@ func New(db *sqlx.DB, payload any, opts ...Option) Model {
 		if payload.Id != 0 {
 			if m.isDeletion {
 				m.title = "Delete Category"
+				m.buildDestinationField(payload)
 				m.focusSubmitButton()
 				break
 			} else {
@@ -94,72 +100,59 @@ func New(db *sqlx.DB, payload any, opts ...Option) Model {
 		} else {
 			m.title = "Create Category"
 		}
-		m.fields = make([]textinput.Model, 1)
-		m.fields[0] = textinput.New()
-		m.fields[0].Prompt = "Name"
-		m.fields[0].PromptStyle = style.TextInputPromptStyle
-		m.fields[0].Cursor.SetMode(cursor.CursorBlink)
-		m.fields[0].Focus()
-		m.fields[0].SetValue(payload.Name)
-		m.fields[0].Width = m.textInputWidth()
+		name := newField(keyName, "Name", m.textInputWidth())
+		name.input.PromptStyle = style.TextInputPromptStyle
+		name.SetValue(payload.Name)
+		name.Focus()
+		m.fields = []field{name, newField(keyParent, "Parent", m.textInputWidth())}
+
+		if err := m.getCategories(); err != nil {
+			m.err = errors.New("failed building categories dropdown")
+		}
+		// A category cannot be moved under itself or its subcategories
+		excluded := append(entity.Descendants(m.categories, payload.Id), payload.Id)
+		m.list = list.New(list.WithFocused(false))
+		m.list.SetHeight(4)
+		m.list.SetWidth(m.textInputWidth())
+		m.list.SetItems(m.categoryItems(excluded...))
+		m.setCategoryField(m.categoryOption(payload.ParentId.Int64))
 	case entity.Password:
+		if payload.Type == "" {
+			payload.Type = entity.TypeLogin
+			m.payload = payload
+		}
 		if payload.Id != 0 {
-			if m.isDeletion {
-				m.title = "Delete Password"
+			if m.isDeletion && payload.DeletedAt.Valid {
+				m.title = "Purge " + payload.Type.String()
+				m.focusSubmitButton()
+				break
+			} else if m.isDeletion {
+				m.title = "Trash " + payload.Type.String()
 				m.focusSubmitButton()
 				break
 			} else {
-				m.title = "Edit Password"
+				m.title = "Edit " + payload.Type.String()
 			}
 		} else {
-			m.title = "Create Password"
+			m.title = "Create " + payload.Type.String()
 		}
 
 		if err := m.getCategories(); err != nil {
 			m.err = errors.New("failed building categories dropdown")
 		}
+		if err := m.getTags(); err != nil {
+			m.err = errors.New("failed loading tags")
+		}
 
-		m.fields = make([]textinput.Model, 5)
-		m.fields[0] = textinput.New()
-		m.fields[1] = textinput.New()
-		m.fields[2] = textinput.New()
-		m.fields[3] = textinput.New()
-		m.fields[4] = textinput.New()
-
-		m.fields[0].Prompt = "Name"
-		m.fields[0].SetValue(payload.Name)
-		m.fields[1].Prompt = "Category"
-		category, _ := lo.Find(m.categories, func(item entity.Category) bool {
-			return item.Id == payload.CategoryId.Int64
-		})
-		m.setCategoryField(category)
-		m.fields[2].Prompt = "Email"
-		m.fields[2].SetValue(payload.Email)
-		m.fields[3].Prompt = "Username"
-		m.fields[3].SetValue(payload.Username)
-		m.fields[4].Prompt = "Password"
-		m.fields[4].SetValue(payload.Password)
-		m.fields[4].EchoMode = textinput.EchoPassword
-		m.fields[4].EchoCharacter = '•'
+		m.buildPasswordFields(payload)
+		m.fields[0].Focus()
 
-		for i := range m.fields {
-			if i == 0 {
-				m.fields[i].Focus()
-			}
-			m.fields[i].PromptStyle = style.TextInputPromptStyle.Width(10)
-			m.fields[i].Cursor.SetMode(cursor.CursorBlink)
-			m.fields[i].Width = m.textInputWidth()
-		}
 		m.list = list.New(list.WithFocused(false))
 		m.list.SetHeight(4)
-		m.list.SetWidth(m.fields[0].Width)
-		m.list.SetItems(
-			lo.Map(m.categories,
-				func(item entity.Category, index int) list.Item {
-					return item
-				},
-			),
-		)
+		m.list.SetWidth(m.textInputWidth())
+		m.list.SetItems(m.categoryItems())
+	case bulk:
+		m.buildBulkFields(payload.action)
 	}
 
 	return m
@@ -176,68 +169,113 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 	case tea.KeyMsg:
 		switch {
+		case m.list.Focused():
+			switch {
+			case key.Matches(msg, DropdownKeys.Up):
+				m.list.Up()
+				return m, nil
+			case key.Matches(msg, DropdownKeys.Down):
+				m.list.Down()
+				return m, nil
+			case key.Matches(msg, DropdownKeys.Select):
+				category := m.list.SelectedItem().(entity.Category)
+				m.setCategoryField(category)
+				fallthrough
+			case key.Matches(msg, DropdownKeys.Cancel):
+				m.list.Blur()
+				return m, m.SendSetKeysMsg
+			}
 		case m.isDeletion:
 			switch {
+			case key.Matches(msg, BaseKeys.Cycle):
+				m.cycleFocus(msg)
+				return m, nil
 			case key.Matches(msg, BaseKeys.Close):
 				return m, m.Close
 			case key.Matches(msg, BaseKeys.Submit):
+				if m.isCategoryFocused() {
+					m.list.Focus()
+					return m, func() tea.Msg {
+						return message.SetHelpKeysMsg{
+							Keys: DropdownKeys,
+						}
+					}
+				}
 				return m, m.Delete
 			}
 		default:
 			switch {
-			case m.list.Focused():
-				switch {
-				case key.Matches(msg, DropdownKeys.Up),
-					key.Matches(msg, DropdownKeys.Down):
-					var cmd tea.Cmd
-					m.list, cmd = m.list.Update(msg)
-					return m, cmd
-				case key.Matches(msg, DropdownKeys.Select):
-					category := m.list.SelectedItem().(entity.Category)
-					m.setCategoryField(category)
-					fallthrough
-				case key.Matches(msg, DropdownKeys.Cancel):
-					m.list.Blur()
-					return m, m.SendSetKeysMsg
+			case key.Matches(msg, BaseKeys.Cycle):
+				m.cycleFocus(msg)
+				return m, nil
+			case key.Matches(msg, BaseKeys.Close):
+				return m, m.Close
+			case key.Matches(msg, BaseKeys.Submit):
+				if m.isButtonFocused() {
+					return m, m.Submit
 				}
-			default:
-				switch {
-				case key.Matches(msg, BaseKeys.Cycle):
-					m.cycleFocus(msg)
-					return m, nil
-				case key.Matches(msg, BaseKeys.Close):
-					return m, m.Close
-				case key.Matches(msg, BaseKeys.Submit):
-					if m.isButtonFocused() {
-						return m, m.Submit
-					}
-					if m.isPasswordPrompt() && m.pointer == 1 {
-						m.list.Focus()
-						return m, func() tea.Msg {
-							return message.SetHelpKeysMsg{
-								Keys: DropdownKeys,
-							}
+				if m.isCategoryFocused() {
+					m.list.Focus()
+					return m, func() tea.Msg {
+						return message.SetHelpKeysMsg{
+							Keys: DropdownKeys,
 						}
 					}
-				case key.Matches(msg, PasswordKeys.TogglePasswordVisibility):
-					if m.isPasswordPrompt() {
-						m.togglePasswordVisibility()
-					}
-					return m, nil
-				case key.Matches(msg, PasswordKeys.GeneratePassword):
-					if m.isPasswordPrompt() {
-						m.generateRandomPassword()
-					}
-					return m, nil
-				default:
-					m.err = nil // Clear existing error on type
 				}
+				if m.isBulk() {
+					return m, m.Submit
+				}
+			case key.Matches(msg, PasswordKeys.TogglePasswordVisibility):
+				if m.isPasswordPrompt() {
+					m.togglePasswordVisibility()
+				}
+				return m, nil
+			case key.Matches(msg, PasswordKeys.GeneratePassword):
+				if m.isPasswordPrompt() {
+					m.generateRandomPassword()
+				}
+				return m, nil
+			case key.Matches(msg, PasswordKeys.AddField):
+				if m.isPasswordPrompt() {
+					return m, m.addCustomField()
+				}
+				return m, nil
+			case key.Matches(msg, PasswordKeys.RemoveField):
+				if m.isPasswordPrompt() {
+					return m, m.removeField()
+				}
+				return m, nil
+			case key.Matches(msg, PasswordKeys.ToggleConcealed):
+				if m.isPasswordPrompt() {
+					m.toggleCustomFieldConcealed()
+				}
+				return m, nil
+			case key.Matches(msg, PasswordKeys.AddURL):
+				if m.isPasswordPrompt() {
+					return m, m.addURLField()
+				}
+				return m, nil
+			case key.Matches(msg, PasswordKeys.CycleRule):
+				if m.isPasswordPrompt() {
+					m.cycleURLRule()
+				}
+				return m, nil
+			case key.Matches(msg, PasswordKeys.CycleType):
+				if m.isPasswordPrompt() {
+					return m, m.cycleItemType()
+				}
+				return m, nil
+			default:
+				m.err = nil // Clear existing error on type
 			}
 		}
 		return m.updateTextInputs(msg)
+	case tea.MouseMsg:
+		return m.updateMouse(msg)
 	case cursor.BlinkMsg:
 		return m.updateTextInputs(msg)
-	case message.OpenPromptMsg[entity.Password], message.OpenPromptMsg[entity.Category]:
+	case message.OpenPromptMsg[entity.Password], message.OpenPromptMsg[entity.Category],
+		message.OpenBulkPromptMsg:
 		return m, m.SendSetKeysMsg
 	}
 	return m, nil
@@ -245,51 +283,53 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 func (m Model) View() string {
 	var view string
-	if m.isDeletion {
+	if b, ok := m.payload.(bulk); ok {
+		view = m.bulkView(b)
+	} else if m.isDeletion {
 		var subtext string
 		switch payload := m.payload.(type) {
 		case entity.Category:
 			subtext = fmt.Sprintf("Delete category: %s", payload.Name)
 		case entity.Password:
-			subtext = fmt.Sprintf("Delete password: %s", payload.Name)
+			format := "Move %s to trash: %s"
+			if payload.DeletedAt.Valid {
+				format = "Permanently delete %s: %s"
+			}
+			subtext = fmt.Sprintf(format,
+				strings.ToLower(payload.Type.String()), payload.Name)
+		}
+		rows := []string{subtext}
+		if len(m.fields) > 0 {
+			rows = append(rows, lipgloss.JoinVertical(
+				lipgloss.Left,
+				m.textFields()...,
+			))
 		}
 		view = textboxRenderer(
 			lipgloss.JoinVertical(
 				lipgloss.Center,
-				subtext,
-				m.button.Render(),
+				append(rows, m.buttonView())...,
 			),
 		)
 	} else {
-		var textFields []string
-		if m.isPasswordPrompt() && m.list.Focused() {
-			textFields = lo.FilterMap(m.fields,
-				func(item textinput.Model, index int) (string, bool) {
-					return item.View(), index < 1
-				})
-			label := style.TextInputPromptStyle.Width(10).
-				Render("Category")
-			selectBox := lipgloss.JoinHorizontal(
-				lipgloss.Left, label, m.list.View(),
-			)
-			textFields = append(textFields, selectBox)
-		} else {
-			textFields = lo.Map(m.fields,
-				func(item textinput.Model, index int) string {
-					return item.View()
-				})
-		}
-		view = textboxRenderer(
-			lipgloss.JoinVertical(
-				lipgloss.Center,
-				titleRenderer(m.title),
+		textFields, focused := m.textFields(), m.focusedTextField()
+		render := func(textFields []string) string {
+			return textboxRenderer(
 				lipgloss.JoinVertical(
-					lipgloss.Left,
-					textFields...,
+					lipgloss.Center,
+					titleRenderer(m.title),
+					lipgloss.JoinVertical(
+						lipgloss.Left,
+						textFields...,
+					),
+					m.buttonView(),
 				),
-				m.button.Render(),
-			),
-		)
+			)
+		}
+		view = render(textFields)
+		if overflow := lipgloss.Height(view) - m.availableHeight; overflow > 0 {
+			view = render(visibleFields(textFields, focused, overflow))
+		}
 	}
 
 	if m.err != nil {
@@ -308,3 +348,38 @@ func (m Model) View() string {
 		view,
 	)
 }
+
+// textFields renders the fields, the dropdown replacing
+// the field it is opened from and the ones below it.
+func (m Model) textFields() []string {
+	var textFields []string
+	if m.list.Focused() {
+		label := ""
+		for i, field := range m.fields {
+			if field.key == keyCategory || field.key == keyParent ||
+				field.key == keyDestination {
+				label = field.input.Prompt
+				break
+			}
+			textFields = append(textFields, m.fieldView(i))
+		}
+		label = style.TextInputPromptStyle.Width(labelWidth).
+			Render(label)
+		selectBox := lipgloss.JoinHorizontal(
+			lipgloss.Left, label, m.list.View(),
+		)
+		return append(textFields, selectBox)
+	}
+
+	for i := 0; i < len(m.fields); i++ {
+		view := m.fieldView(i)
+		if m.fields[i].key == keyCustomLabel && i+1 < len(m.fields) {
+			// Custom fields render their label and value side by side
+			i++
+			view = lipgloss.JoinHorizontal(lipgloss.Top,
+				view, m.fieldView(i))
+		}
+		textFields = append(textFields, view)
+	}
+	return textFields
+}
@@ -3,13 +3,20 @@ package prompt
 import (
 	"database/sql"
 	"errors"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
 
+	"viscue/tui/component/list"
 	"viscue/tui/entity"
 	"viscue/tui/style"
 	"viscue/tui/tool/crypto"
+	"viscue/tui/tool/settings"
+	"viscue/tui/tool/urlmatch"
+	"viscue/tui/tool/zone"
+	"viscue/tui/views/library/message"
 
-	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/log"
@@ -18,19 +25,20 @@ import (
 )
 
 func (m *Model) cycleFocus(msg tea.KeyMsg) {
-	if m.isDeletion {
+	if len(m.fields) == 0 {
 		return
 	}
 
 	length := len(m.fields) // Including button
 	if _, idx, found := lo.FindIndexOf(m.fields,
-		func(item textinput.Model) bool {
+		func(item field) bool {
 			return item.Focused()
 		},
 	); found {
 		m.fields[idx].Blur()
 	}
-	if msg.String() == "tab" {
+	// The first key of the binding moves forward, the others backward
+	if msg.String() == BaseKeys.Cycle.Keys()[0] {
 		m.pointer++
 	} else {
 		m.pointer--
@@ -53,10 +61,8 @@ func (m *Model) cycleFocus(msg tea.KeyMsg) {
 
 func (m *Model) togglePasswordVisibility() {
 	m.showPassword = !m.showPassword
-	if m.showPassword {
-		m.fields[4].EchoMode = textinput.EchoNormal
-	} else {
-		m.fields[4].EchoMode = textinput.EchoPassword
+	for i := range m.fields {
+		m.fields[i].Reveal(m.showPassword)
 	}
 }
 
@@ -64,25 +70,70 @@ func (m Model) updateTextInputs(msg tea.Msg) (Model, tea.Cmd) {
 	var commands []tea.Cmd
 	var cmd tea.Cmd
 	for i := range m.fields {
-		if m.isPasswordPrompt() && m.pointer == 1 {
+		if m.isCategoryFocused() {
 			// Disable category text input
 			continue
 		}
 		m.fields[i], cmd = m.fields[i].Update(msg)
 		commands = append(commands, cmd)
 	}
+	if tags := m.field(keyTags); tags != nil && tags.Focused() {
+		m.suggestTags(tags)
+	}
 	return m, tea.Batch(commands...)
 }
 
+func (m *Model) getTags() error {
+	err := m.db.Select(&m.tags, "SELECT name FROM tags ORDER BY name")
+	if err != nil {
+		log.Error("prompt.Model.getTags: failed to get tags", "err", err)
+	}
+	return err
+}
+
+// suggestTags completes the tag being typed with the existing
+// tags that are not used yet. Suggestions of a text input apply
+// to its whole value, hence the tags typed so far prefix them.
+func (m Model) suggestTags(tags *field) {
+	value := tags.Value()
+	typed := parseTags(value)
+	prefix := ""
+	if i := strings.LastIndex(value, ","); i >= 0 {
+		prefix = value[:i+1] + " "
+	}
+	tags.input.SetSuggestions(lo.FilterMap(m.tags,
+		func(tag string, _ int) (string, bool) {
+			return prefix + tag, !lo.ContainsBy(typed, func(t string) bool {
+				return strings.EqualFold(t, tag)
+			})
+		}))
+}
+
+// parseTags splits comma separated tags, dropping the
+// empty and duplicated ones.
+func parseTags(value string) []string {
+	var tags []string
+	for _, tag := range strings.Split(value, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || lo.ContainsBy(tags, func(t string) bool {
+			return strings.EqualFold(t, tag)
+		}) {
+			continue
+		}
+		tags = append(tags, tag)
+	}
+	return tags
+}
+
 func (m *Model) getCategories() error {
 	query := `
 		WITH results AS (
-			SELECT 0 AS id, 'None' AS name, 1 AS sort_order
+			SELECT 0 AS id, 'None' AS name, NULL AS parent_id, 1 AS sort_order
 			UNION ALL
-			SELECT id, name, 2 AS sort_order FROM categories
+			SELECT id, name, parent_id, 2 AS sort_order FROM categories
 			ORDER BY sort_order, name
 		)
-		SELECT id, name FROM results
+		SELECT id, name, parent_id FROM results
 	`
 	rows, err := m.db.Queryx(query)
 	if err != nil {
@@ -107,6 +158,55 @@ func (m *Model) getCategories() error {
 	return nil
 }
 
+// trashDestination moves the items of a deleted category to the trash.
+const trashDestination = -3
+
+// buildDestinationField asks where the items of a deleted category
+// go: to the trash, which is the default, or to another category.
+func (m *Model) buildDestinationField(category entity.Category) {
+	if err := m.getCategories(); err != nil {
+		m.err = errors.New("failed building categories dropdown")
+	}
+	m.fields = []field{newField(keyDestination, "Items to", m.textInputWidth())}
+	m.pointer = len(m.fields)
+
+	trash := entity.Category{Id: trashDestination, Name: "Trash"}
+	m.list = list.New(list.WithFocused(false))
+	m.list.SetHeight(4)
+	m.list.SetWidth(m.textInputWidth())
+	m.list.SetItems(append([]list.Item{trash}, m.categoryItems(category.Id)...))
+	m.setCategoryField(trash)
+}
+
+// categoryOption returns the category named after its full path.
+func (m Model) categoryOption(id int64) entity.Category {
+	category, _ := lo.Find(m.categories, func(item entity.Category) bool {
+		return item.Id == id
+	})
+	if category.Id > 0 {
+		category.Name = entity.Path(m.categories, category.Id)
+	}
+	return category
+}
+
+// categoryItems lists the dropdown options sorted by their path,
+// leaving out the excluded categories.
+func (m Model) categoryItems(excluded ...int64) []list.Item {
+	options := lo.FilterMap(m.categories,
+		func(item entity.Category, _ int) (entity.Category, bool) {
+			return m.categoryOption(item.Id), !lo.Contains(excluded, item.Id)
+		})
+	sort.SliceStable(options, func(i, j int) bool {
+		if options[i].Id == 0 || options[j].Id == 0 {
+			return options[i].Id == 0 && options[j].Id != 0
+		}
+		return strings.ToLower(options[i].Name) < strings.ToLower(options[j].Name)
+	})
+	return lo.Map(options, func(item entity.Category, _ int) list.Item {
+		return item
+	})
+}
+
 func (m *Model) focusSubmitButton() {
 	m.button = m.button.
 		UnsetForeground().
@@ -127,13 +227,121 @@ func (m Model) isButtonFocused() bool {
 	return m.pointer == len(m.fields)
 }
 
+func (m Model) isCategoryFocused() bool {
+	if m.pointer >= len(m.fields) {
+		return false
+	}
+	switch m.fields[m.pointer].key {
+	case keyCategory, keyParent, keyDestination:
+		return true
+	default:
+		return false
+	}
+}
+
+// field returns the first field with the given key.
+func (m *Model) field(key string) *field {
+	for i := range m.fields {
+		if m.fields[i].key == key {
+			return &m.fields[i]
+		}
+	}
+	return nil
+}
+
+// addCustomField appends an empty custom field and focuses its label.
+func (m *Model) addCustomField() tea.Cmd {
+	if m.pointer < len(m.fields) {
+		m.fields[m.pointer].Blur()
+	}
+	m.blurSubmitButton()
+	fields := newCustomFields("", "", false, m.textInputWidth())
+	m.fields = append(m.fields, fields[:]...)
+	m.pointer = len(m.fields) - 2
+	return m.fields[m.pointer].Focus()
+}
+
+// removeField drops the focused custom field or URL, if any.
+func (m *Model) removeField() tea.Cmd {
+	from := m.pointer
+	if from >= len(m.fields) {
+		return nil
+	}
+	to := from + 1
+	switch m.fields[from].key {
+	case keyCustomValue:
+		from--
+	case keyCustomLabel:
+		to++
+	case keyURL:
+	default:
+		return nil
+	}
+	m.fields = slices.Delete(m.fields, from, to)
+	m.pointer = min(from, len(m.fields))
+	if m.pointer == len(m.fields) {
+		m.focusSubmitButton()
+		return nil
+	}
+	return m.fields[m.pointer].Focus()
+}
+
+// addURLField inserts an empty URL after the last one, or after
+// the type specific fields when there is none, and focuses it.
+func (m *Model) addURLField() tea.Cmd {
+	schema := m.payload.(entity.Password).Type.Schema()
+	if !schema.URLs {
+		return nil
+	}
+
+	at := 0
+	for i, f := range m.fields {
+		if f.key == keyURL || f.key == keyCategory {
+			at = i + 1
+		} else if _, ok := schema.Field(f.key); ok {
+			at = i + 1
+		}
+	}
+	if m.pointer < len(m.fields) {
+		m.fields[m.pointer].Blur()
+	}
+	m.blurSubmitButton()
+	url := newURLField("", urlmatch.Domain, m.textInputWidth())
+	m.fields = slices.Insert(m.fields, at, url)
+	m.pointer = at
+	return m.fields[m.pointer].Focus()
+}
+
+// cycleURLRule switches the focused URL to the following match rule.
+func (m *Model) cycleURLRule() {
+	if m.pointer < len(m.fields) && m.fields[m.pointer].key == keyURL {
+		m.fields[m.pointer].rule = m.fields[m.pointer].rule.Next()
+	}
+}
+
+// toggleCustomFieldConcealed masks or unmasks the value of the
+// focused custom field.
+func (m *Model) toggleCustomFieldConcealed() {
+	value := m.pointer
+	if value < len(m.fields) && m.fields[value].key == keyCustomLabel {
+		value++
+	}
+	if value >= len(m.fields) || m.fields[value].key != keyCustomValue {
+		return
+	}
+	m.fields[value].SetConcealed(!m.fields[value].concealed)
+	m.fields[value].Reveal(m.showPassword)
+}
+
 func (m Model) isPasswordPrompt() bool {
 	_, ok := m.payload.(entity.Password)
 	return ok
 }
 
+// textInputWidth leaves room for the labels and the border
+// of the prompt on narrow terminals.
 func (m Model) textInputWidth() int {
-	return min(m.availableWidth-2, minimumTextInputWidth)
+	return min(m.availableWidth-labelWidth-6, minimumTextInputWidth)
 }
 
 func (m *Model) setCategoryField(category entity.Category) {
@@ -151,14 +359,29 @@ func (m *Model) setCategoryField(category entity.Category) {
 		strings.Repeat(" ", 2),
 		"⌄",
 	)
-	m.fields[1].SetValue(categoryName)
-	m.fields[1].SetCursor(len(categoryName))
-	password := m.payload.(entity.Password)
-	password.CategoryId = sql.NullInt64{
-		Int64: category.Id,
-		Valid: category.Id != 0,
+	id := sql.NullInt64{Int64: category.Id, Valid: category.Id != 0}
+	switch payload := m.payload.(type) {
+	case entity.Password:
+		m.field(keyCategory).input.SetValue(categoryName)
+		m.field(keyCategory).input.SetCursor(len(categoryName))
+		payload.CategoryId = id
+		m.payload = payload
+	case entity.Category:
+		if m.isDeletion {
+			m.field(keyDestination).input.SetValue(categoryName)
+			m.field(keyDestination).input.SetCursor(len(categoryName))
+			m.destination = category.Id
+			return
+		}
+		m.field(keyParent).input.SetValue(categoryName)
+		m.field(keyParent).input.SetCursor(len(categoryName))
+		payload.ParentId = id
+		m.payload = payload
+	case bulk:
+		m.field(keyCategory).input.SetValue(categoryName)
+		m.field(keyCategory).input.SetCursor(len(categoryName))
+		m.destination = category.Id
 	}
-	m.payload = password
 }
 
 func handleUpsertCategoryError(err error) SubmitError {
@@ -167,7 +390,7 @@ func handleUpsertCategoryError(err error) SubmitError {
 		if ok {
 			switch {
 			case errors.Is(sqliteErr.Code, sqlite3.ErrConstraint):
-				return SubmitError(errors.New("seems like the category name is taken"))
+				return SubmitError(errors.New("seems like the category name is taken within its parent"))
 			default:
 				log.Error("prompt.handleUpsertCategory: unrecognized sqlite error",
 					"err", err)
@@ -204,13 +427,207 @@ func handleUpsertPasswordError(err error) SubmitError {
 	return SubmitError(err)
 }
 
+// handleRecordError reports a change that could not be kept on the
+// undo stack, which is rolled back rather than done without a way back.
+func handleRecordError(err error) SubmitError {
+	log.Error("prompt.handleRecordError: failed recording change", "err", err)
+	return SubmitError(errors.New("failed keeping the change for undo"))
+}
+
+// focusedTextField returns which of the text fields holds the
+// focused field, the last one while the button is focused.
+func (m Model) focusedTextField() int {
+	index := 0
+	for i := 0; i < len(m.fields) && i < m.pointer; i++ {
+		if m.fields[i].key == keyCustomLabel && i+1 < len(m.fields) {
+			// Rendered along with its value
+			i++
+			if i == m.pointer {
+				break
+			}
+		}
+		index++
+	}
+	return index
+}
+
+// visibleFields drops enough lines of text fields for the prompt to
+// fit within the height, keeping the focused one and the ones around it.
+func visibleFields(textFields []string, focused, overflow int) []string {
+	focused = min(focused, len(textFields)-1)
+	from, to := 0, len(textFields)
+	for overflow > 0 && to-from > 1 {
+		// Fields are dropped away from the focused one, below it first
+		if to-1 > focused {
+			to--
+			overflow -= lipgloss.Height(textFields[to])
+		} else {
+			overflow -= lipgloss.Height(textFields[from])
+			from++
+		}
+	}
+	return textFields[from:to]
+}
+
 func (m *Model) generateRandomPassword() {
-	randomPassword, err := crypto.GenerateRandomPassword(24)
+	randomPassword, err := crypto.GeneratePassword(
+		settings.GeneratorLength.Get(m.db),
+		settings.GeneratorDigits.Get(m.db),
+		settings.GeneratorSymbols.Get(m.db),
+	)
 	if err != nil {
 		log.Error("prompt.*Model.generateRandomPassword: failed", "err", err)
 		m.err = errors.New("failed to generate random password")
 	}
-	m.fields[4].SetValue(randomPassword)
-	m.showPassword = true
-	m.fields[4].EchoMode = textinput.EchoNormal
+	// Fill the focused concealed field, falling back to the password
+	target := m.field(entity.KeyPassword)
+	if m.pointer < len(m.fields) && m.fields[m.pointer].concealed &&
+		!m.fields[m.pointer].multiline {
+		target = &m.fields[m.pointer]
+	}
+	if target == nil {
+		return
+	}
+	target.SetValue(randomPassword)
+	target.Reveal(true)
+}
+
+// buildPasswordFields creates the fields of the item type of the
+// payload, followed by the notes and the custom fields.
+func (m *Model) buildPasswordFields(payload entity.Password) {
+	width := m.textInputWidth()
+	schema := payload.Type.Schema()
+
+	fields := []field{
+		newField(keyName, "Name", width),
+		newField(keyCategory, "Category", width),
+		newTagsField(payload.Tags, width),
+	}
+	for _, schemaField := range schema.Fields {
+		fields = append(fields, newSchemaField(schemaField,
+			payload.Value(schemaField.Key), width))
+	}
+	if schema.URLs {
+		for _, url := range payload.URLs {
+			fields = append(fields, newURLField(url.URL, url.Rule, width))
+		}
+		if len(payload.URLs) == 0 {
+			fields = append(fields, newURLField("", urlmatch.Domain, width))
+		}
+	}
+	if _, ok := schema.Field(entity.KeyNotes); !ok {
+		notes := newAreaField(entity.KeyNotes, "Notes", width)
+		notes.SetValue(payload.Notes)
+		fields = append(fields, notes)
+	}
+	for _, custom := range payload.Fields {
+		pair := newCustomFields(custom.Name, custom.Value,
+			custom.Concealed, width)
+		fields = append(fields, pair[:]...)
+	}
+	m.fields = fields
+
+	m.field(keyName).SetValue(payload.Name)
+	m.setCategoryField(m.categoryOption(payload.CategoryId.Int64))
+	for i := range m.fields {
+		m.fields[i].Reveal(m.showPassword)
+	}
+}
+
+// cycleItemType switches a new item to the following type, keeping
+// the values the types have in common.
+func (m *Model) cycleItemType() tea.Cmd {
+	current := m.buildPasswordEntity()
+	if current.Id != 0 {
+		return nil
+	}
+	payload := entity.Password{
+		Type:       current.Type.Next(),
+		Name:       current.Name,
+		CategoryId: current.CategoryId,
+		Notes:      current.Notes,
+		Fields:     current.Fields,
+		URLs:       current.URLs,
+		Tags:       current.Tags,
+	}
+	for _, schemaField := range payload.Type.Schema().Fields {
+		payload.SetValue(schemaField.Key, current.Value(schemaField.Key))
+	}
+	m.payload = payload
+	m.title = "Create " + payload.Type.String()
+	m.buildPasswordFields(payload)
+	m.pointer = 0
+	m.blurSubmitButton()
+	return m.fields[0].Focus()
+}
+
+// focus moves the focus to the field at index, or to the
+// button when the index is past the fields.
+func (m *Model) focus(index int) tea.Cmd {
+	for i := range m.fields {
+		m.fields[i].Blur()
+	}
+	m.pointer = index
+	if index >= len(m.fields) {
+		m.focusSubmitButton()
+		return nil
+	}
+	m.blurSubmitButton()
+	return m.fields[index].Focus()
+}
+
+// fieldView renders the field at index, marked for mouse events.
+func (m Model) fieldView(index int) string {
+	return zone.Mark(m.zoneId+"field"+strconv.Itoa(index), m.fields[index].View())
+}
+
+// buttonView renders the button, marked for mouse events.
+func (m Model) buttonView() string {
+	return zone.Mark(m.zoneId+"button", m.button.Render())
+}
+
+// updateMouse focuses the clicked field, opening the dropdown of a
+// category field, and submits when the button is clicked. While the
+// dropdown is open, a click picks the category under the mouse, or
+// closes it when elsewhere, and the wheel scrolls it.
+func (m Model) updateMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	if m.list.Focused() {
+		if _, ok := zone.IsWheel(msg); ok {
+			var cmd tea.Cmd
+			m.list, cmd = m.list.Update(msg)
+			return m, cmd
+		} else if !zone.IsClick(msg) {
+			return m, nil
+		}
+		if _, ok := m.list.Click(msg); ok {
+			m.setCategoryField(m.list.SelectedItem().(entity.Category))
+		}
+		m.list.Blur()
+		return m, m.SendSetKeysMsg
+	}
+	if !zone.IsClick(msg) {
+		return m, nil
+	}
+
+	if _, ok := zone.Hit(m.zoneId+"button", msg); ok {
+		m.focus(len(m.fields))
+		if m.isDeletion {
+			return m, m.Delete
+		}
+		return m, m.Submit
+	}
+	for i := range m.fields {
+		if _, ok := zone.Hit(m.zoneId+"field"+strconv.Itoa(i), msg); !ok {
+			continue
+		}
+		cmd := m.focus(i)
+		if m.isCategoryFocused() {
+			m.list.Focus()
+			return m, func() tea.Msg {
+				return message.SetHelpKeysMsg{Keys: DropdownKeys}
+			}
+		}
+		return m, cmd
+	}
+	return m, nil
 }
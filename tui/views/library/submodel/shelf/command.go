@@ -3,13 +3,31 @@ package shelf
 import (
 	"crypto/rsa"
 	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
 	"viscue/tui/component/notification"
 	"viscue/tui/entity"
+	"viscue/tui/tool/breach"
 	"viscue/tui/tool/cache"
+	"viscue/tui/tool/otp"
+	"viscue/tui/tool/settings"
+	"viscue/tui/tool/undo"
+	"viscue/tui/tool/urlmatch"
+	"viscue/tui/tool/vault"
 	"viscue/tui/views/library/message"
+	"viscue/tui/views/library/submodel/prompt"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/log"
+	"github.com/jmoiron/sqlx"
+	"github.com/mattn/go-sqlite3"
+	"github.com/samber/lo"
 	"golang.design/x/clipboard"
 )
 
@@ -18,27 +36,14 @@ type DataLoadedMsg struct {
 }
 
 func (m Model) LoadItems() tea.Msg {
-	rows, err := m.db.Queryx(
-		"SELECT id, category_id, name, email, username, password FROM passwords",
-	)
-	if err != nil {
-		return nil
+	if err := PurgeTrash(m.db); err != nil {
+		log.Error("shelf.(Model).LoadItems: failed purging trash", "err", err)
 	}
-	defer rows.Close()
-
-	privateKey := cache.Get[*rsa.PrivateKey](cache.PrivateKey)
-	var passwords []entity.Password
-	for rows.Next() {
-		var password entity.Password
-		err = rows.StructScan(&password)
-		if err != nil {
-			return err
-		}
-		err = password.Decrypt(privateKey)
-		if err != nil {
-			return err
-		}
-		passwords = append(passwords, password)
+	passwords, err := vault.Passwords(m.db)
+	if err != nil {
+		log.Error("shelf.(Model).LoadItems: failed loading passwords",
+			"err", err)
+		return err
 	}
 
 	return DataLoadedMsg{
@@ -46,9 +51,37 @@ func (m Model) LoadItems() tea.Msg {
 	}
 }
 
+// PurgeTrash deletes the passwords kept in the trash for longer
+// than the configured retention. A retention of zero keeps them
+// until purged by hand.
+func PurgeTrash(db *sqlx.DB) error {
+	retention := settings.TrashRetention.Get(db)
+	if retention <= 0 {
+		return nil
+	}
+
+	_, err := db.Exec(
+		"DELETE FROM passwords WHERE deleted_at IS NOT NULL AND deleted_at < ?",
+		time.Now().AddDate(0, 0, -retention),
+	)
+	if err != nil {
+		return err
+	}
+	return prompt.PruneTags(db)
+}
+
 func (m Model) EditPasswordPromptMsg() tea.Cmd {
-	row := m.table.SelectedRow()
-	password, _ := entity.NewPasswordFromTableRow(row)
+	password, ok := m.selectedPassword()
+	if !ok {
+		return nil
+	} else if password.DeletedAt.Valid {
+		return func() tea.Msg {
+			return notification.ShowMsg{
+				Message: "Restore the item before editing it",
+				Level:   notification.LevelWarning,
+			}
+		}
+	}
 	return tea.Sequence(
 		func() tea.Msg {
 			return message.OpenPromptMsg[entity.Password]{
@@ -63,18 +96,18 @@ func (m Model) EditPasswordPromptMsg() tea.Cmd {
 
 func (m Model) AddPasswordPromptMsg() tea.Cmd {
 	var selectedCategoryId int64
-	if m.selectedCategoryId == nil {
-		selectedCategoryId = 0
-	} else {
+	if m.selectedCategoryId != nil {
 		selectedCategoryId = *m.selectedCategoryId
-		if selectedCategoryId <= 0 {
-			selectedCategoryId = 0
-		}
+	}
+	if selectedCategoryId <= 0 {
+		// Not an actual category, e.g. all items or the favorites
+		selectedCategoryId = settings.DefaultCategory.Get(m.db)
 	}
 	return tea.Sequence(
 		func() tea.Msg {
 			return message.OpenPromptMsg[entity.Password]{
 				Payload: entity.Password{
+					Type: m.itemType,
 					CategoryId: sql.NullInt64{
 						Int64: selectedCategoryId,
 						Valid: selectedCategoryId > 0,
@@ -89,8 +122,10 @@ func (m Model) AddPasswordPromptMsg() tea.Cmd {
 }
 
 func (m Model) DeletePasswordPromptMsg() tea.Cmd {
-	row := m.table.SelectedRow()
-	password, _ := entity.NewPasswordFromTableRow(row)
+	password, ok := m.selectedPassword()
+	if !ok {
+		return nil
+	}
 	return tea.Sequence(
 		func() tea.Msg {
 			return message.OpenPromptMsg[entity.Password]{
@@ -104,11 +139,522 @@ func (m Model) DeletePasswordPromptMsg() tea.Cmd {
 	)
 }
 
+func (m Model) SendSetKeysMsg() tea.Msg {
+	if m.marked() {
+		return message.SetHelpKeysMsg{Keys: MarkedKeyMap{Keys}}
+	}
+	return message.SetHelpKeysMsg{Keys: Keys}
+}
+
+// BulkPromptMsg asks to confirm the action about to be done to the
+// marked passwords, or to the selected one when none is marked.
+// Only deleting and restoring apply to the trash.
+func (m Model) BulkPromptMsg(action message.BulkAction) tea.Cmd {
+	passwords := m.markedPasswords()
+	if len(passwords) == 0 {
+		return nil
+	}
+	trashed := lo.SomeBy(passwords, func(password entity.Password) bool {
+		return password.DeletedAt.Valid
+	})
+	switch {
+	case action == message.BulkRestore && !trashed:
+		return nil
+	case action != message.BulkDelete && action != message.BulkRestore && trashed:
+		return func() tea.Msg {
+			return notification.ShowMsg{
+				Message: "Restore the items before changing them",
+				Level:   notification.LevelWarning,
+			}
+		}
+	}
+	return openBulkPrompt(action, passwords)
+}
+
+// ExportPromptMsg asks to confirm exporting every password out of
+// the trash, marked or not.
+func (m Model) ExportPromptMsg() tea.Cmd {
+	passwords := m.Passwords()
+	if len(passwords) == 0 {
+		return nil
+	}
+	return openBulkPrompt(message.BulkExport, passwords)
+}
+
+func openBulkPrompt(action message.BulkAction, passwords []entity.Password) tea.Cmd {
+	return tea.Sequence(
+		func() tea.Msg {
+			return message.OpenBulkPromptMsg{Action: action, Passwords: passwords}
+		},
+		func() tea.Msg {
+			return message.PromptFocused
+		},
+	)
+}
+
+// notifyBulkApplied tells what has been done to how many passwords.
+func (m Model) notifyBulkApplied(msg prompt.BulkAppliedMsg) tea.Cmd {
+	count := len(msg.Passwords)
+	items := fmt.Sprintf("%d items", count)
+	if count == 1 {
+		items = "1 item"
+	}
+	var text string
+	switch msg.Action {
+	case message.BulkMove:
+		text = "Moved " + items + " to " + msg.Category.Name
+	case message.BulkTag:
+		text = "Tagged " + items
+	case message.BulkDelete:
+		text = "Moved " + items + " to trash"
+		if lo.EveryBy(msg.Passwords, func(password entity.Password) bool {
+			return password.DeletedAt.Valid
+		}) {
+			text = "Deleted " + items + " for good"
+		}
+	case message.BulkRestore:
+		text = "Restored " + items + " from trash"
+	case message.BulkRegenerate:
+		text = "Regenerated the passwords of " + items
+	}
+	return func() tea.Msg {
+		return notification.ShowMsg{
+			Message: text,
+			Level:   notification.LevelSuccess,
+		}
+	}
+}
+
+func (m Model) HistoryMsg() tea.Cmd {
+	password, ok := m.selectedPassword()
+	if !ok {
+		return nil
+	}
+	return tea.Sequence(
+		func() tea.Msg {
+			return message.OpenHistoryMsg{Payload: password}
+		},
+		func() tea.Msg {
+			return message.PanelFocused
+		},
+	)
+}
+
+func (m Model) NotificationsMsg() tea.Cmd {
+	return tea.Sequence(
+		func() tea.Msg {
+			return message.OpenNotificationsMsg{}
+		},
+		func() tea.Msg {
+			return message.PanelFocused
+		},
+	)
+}
+
+func (m Model) SettingsMsg() tea.Cmd {
+	return tea.Sequence(
+		func() tea.Msg {
+			return message.OpenSettingsMsg{}
+		},
+		func() tea.Msg {
+			return message.PanelFocused
+		},
+	)
+}
+
+func (m Model) AccountMsg() tea.Cmd {
+	return tea.Sequence(
+		func() tea.Msg {
+			return message.OpenAccountMsg{}
+		},
+		func() tea.Msg {
+			return message.PanelFocused
+		},
+	)
+}
+
+func (m Model) DetailMsg() tea.Cmd {
+	password, ok := m.selectedPassword()
+	if !ok {
+		return nil
+	}
+	return tea.Sequence(
+		func() tea.Msg {
+			return message.OpenDetailMsg{Payload: password}
+		},
+		func() tea.Msg {
+			return message.PanelFocused
+		},
+	)
+}
+
+type PasswordUsedMsg struct {
+	Id int64
+	At time.Time
+}
+
+// MarkAsUsed records that the selected password has just been used.
+func (m Model) MarkAsUsed() tea.Msg {
+	password, ok := m.selectedPassword()
+	if !ok {
+		return nil
+	}
+
+	now := time.Now()
+	_, err := m.db.Exec("UPDATE passwords SET last_used_at = ? WHERE id = ?",
+		now, password.Id)
+	if err != nil {
+		log.Error("shelf.(Model).MarkAsUsed: failed updating last_used_at",
+			"err", err)
+		return nil
+	}
+	return PasswordUsedMsg{Id: password.Id, At: now}
+}
+
+type RestoredFromTrashMsg struct {
+	Id int64
+}
+
+// RestoreFromTrash puts the selected password back where it was,
+// or in Uncategorized when its category has been deleted since.
+func (m Model) RestoreFromTrash() tea.Msg {
+	password, ok := m.selectedPassword()
+	if !ok || !password.DeletedAt.Valid {
+		return nil
+	}
+
+	failed := notification.ShowMsg{
+		Message: "Failed restoring from trash",
+		Level:   notification.LevelError,
+	}
+	tx, err := m.db.Beginx()
+	if err != nil {
+		log.Error("shelf.(Model).RestoreFromTrash: failed to start transaction",
+			"err", err)
+		return failed
+	}
+	record := undo.Record("Restore " + password.Name)
+	if err = record.Track(tx, undo.Passwords, password.Id); err != nil {
+		log.Error("shelf.(Model).RestoreFromTrash: failed recording change",
+			"err", err)
+		_ = tx.Rollback()
+		return failed
+	}
+
+	_, err = tx.Exec("UPDATE passwords SET deleted_at = NULL WHERE id = ?",
+		password.Id)
+	if err != nil {
+		_ = tx.Rollback()
+		var sqliteErr sqlite3.Error
+		if errors.As(err, &sqliteErr) && sqliteErr.Code == sqlite3.ErrConstraint {
+			return notification.ShowMsg{
+				Message: "Another item has taken this name, rename it first",
+				Level:   notification.LevelWarning,
+			}
+		}
+		log.Error("shelf.(Model).RestoreFromTrash: failed restoring password",
+			"err", err)
+		return failed
+	}
+
+	if err = record.Done(tx); err != nil {
+		log.Error("shelf.(Model).RestoreFromTrash: failed recording change",
+			"err", err)
+		_ = tx.Rollback()
+		return failed
+	}
+	if err = tx.Commit(); err != nil {
+		log.Error("shelf.(Model).RestoreFromTrash: failed to commit transaction",
+			"err", err)
+		return failed
+	}
+	record.Push()
+	return RestoredFromTrashMsg{Id: password.Id}
+}
+
+type FavoriteToggledMsg struct {
+	Id       int64
+	Favorite bool
+}
+
+// ToggleFavorite adds the selected password to the favorites,
+// or removes it from them.
+func (m Model) ToggleFavorite() tea.Msg {
+	password, ok := m.selectedPassword()
+	if !ok {
+		return nil
+	}
+
+	favorite := !password.Favorite
+	_, err := m.db.Exec("UPDATE passwords SET favorite = ? WHERE id = ?",
+		favorite, password.Id)
+	if err != nil {
+		log.Error("shelf.(Model).ToggleFavorite: failed updating favorite",
+			"err", err)
+		return notification.ShowMsg{
+			Message: "Failed updating favorites",
+			Level:   notification.LevelError,
+		}
+	}
+	return FavoriteToggledMsg{Id: password.Id, Favorite: favorite}
+}
+
 func (m Model) CopyToClipboard() tea.Msg {
-	row := m.table.SelectedRow()
-	password, _ := entity.NewPasswordFromTableRow(row)
+	password, ok := m.selectedPassword()
+	if !ok {
+		return nil
+	}
 	clipboard.Write(clipboard.FmtText, []byte(password.Password))
+	return message.CopiedMsg{Label: "Password", Value: password.Password}
+}
+
+// CopyValue copies the value of the given schema key, e.g. the
+// email or the username, of the selected password.
+func (m Model) CopyValue(key string) tea.Cmd {
+	password, ok := m.selectedPassword()
+	if !ok {
+		return nil
+	}
+	label := password.Type.Schema().ColumnTitle(key)
+	if label == "" {
+		label = strings.ToUpper(key[:1]) + key[1:]
+	}
+	value := password.Value(key)
+	if value == "" {
+		return func() tea.Msg {
+			return notification.ShowMsg{
+				Message: "This item has no " + strings.ToLower(label),
+				Level:   notification.LevelWarning,
+			}
+		}
+	}
+	return tea.Batch(
+		func() tea.Msg {
+			clipboard.Write(clipboard.FmtText, []byte(value))
+			return message.CopiedMsg{Label: label, Value: value}
+		},
+		m.MarkAsUsed,
+	)
+}
+
+// CopyEntry copies the nth field shown by the preview.
+func (m Model) CopyEntry(n int) tea.Cmd {
+	entry, ok := m.preview.Entry(n)
+	if !ok {
+		return nil
+	}
+	return tea.Batch(
+		func() tea.Msg {
+			clipboard.Write(clipboard.FmtText, []byte(entry.Value))
+			return message.CopiedMsg{Label: entry.Label, Value: entry.Value}
+		},
+		m.MarkAsUsed,
+	)
+}
+
+type OtpTickMsg struct{}
+
+// TickOtp refreshes the one-time password shown in the shelf
+// every second so that the countdown stays accurate.
+func (m Model) TickOtp() tea.Cmd {
+	return tea.Tick(time.Second, func(time.Time) tea.Msg {
+		return OtpTickMsg{}
+	})
+}
+
+// OtpAdvancedMsg is sent when an HOTP counter has been
+// moved forward after its code was used.
+type OtpAdvancedMsg struct {
+	Id     int64
+	Otp    string
+	Copied message.CopiedMsg
+}
+
+func (m Model) CopyOtpToClipboard() tea.Msg {
+	password, ok := m.selectedPassword()
+	if !ok || password.Otp == "" {
+		return nil
+	}
+
+	key, err := otp.Parse(password.Otp)
+	if err != nil {
+		log.Error("shelf.(Model).CopyOtpToClipboard: invalid otp", "err", err)
+		return notification.ShowMsg{
+			Message: "One-time password secret is invalid",
+			Level:   notification.LevelError,
+		}
+	}
+	code := key.Code(time.Now())
+	copied := message.CopiedMsg{Label: "One-time password", Value: code}
+	if key.Type != otp.HOTP {
+		clipboard.Write(clipboard.FmtText, []byte(code))
+		return copied
+	}
+
+	// HOTP codes are single use, hence move on to the next one
+	// before copying, so that a code is never handed out twice.
+	key.Counter++
+	password.Otp = key.String()
+	enc := password.Copy()
+	if err = enc.Encrypt(cache.Get[*rsa.PublicKey](cache.PublicKey)); err == nil {
+		_, err = m.db.Exec("UPDATE passwords SET otp = ? WHERE id = ?",
+			enc.Otp, password.Id)
+	}
+	if err != nil {
+		log.Error("shelf.(Model).CopyOtpToClipboard: failed advancing counter",
+			"err", err)
+		return notification.ShowMsg{
+			Message: "Failed advancing the one-time password counter",
+			Level:   notification.LevelError,
+		}
+	}
+	clipboard.Write(clipboard.FmtText, []byte(code))
+	return OtpAdvancedMsg{Id: password.Id, Otp: password.Otp, Copied: copied}
+}
+
+type BreachCheckedMsg struct {
+	Counts map[int64]int
+}
+
+// CheckBreaches looks up the given passwords in the configured
+// Pwned Passwords dataset. It is a no-op when none is configured.
+func (m Model) CheckBreaches(passwords []entity.Password) tea.Cmd {
+	if m.breachChecker == nil || len(passwords) == 0 {
+		return nil
+	}
+
+	checker := m.breachChecker
+	return func() tea.Msg {
+		counts := make(map[int64]int, len(passwords))
+		for _, password := range passwords {
+			if password.Password == "" {
+				continue
+			}
+			count, err := checker.Count(password.Password)
+			if err != nil {
+				log.Error("shelf.(Model).CheckBreaches: failed checking password",
+					"id", password.Id, "err", err)
+				continue
+			}
+			counts[password.Id] = count
+		}
+		return BreachCheckedMsg{Counts: counts}
+	}
+}
+
+func (m Model) BreachReportMsg() tea.Cmd {
+	if m.breachChecker == nil {
+		return func() tea.Msg {
+			return notification.ShowMsg{
+				Message: "Set " + breach.SourceEnv + " to enable breach checks",
+				Level:   notification.LevelWarning,
+			}
+		}
+	}
+	return tea.Sequence(
+		func() tea.Msg {
+			return message.OpenBreachReportMsg{
+				Passwords: lo.Filter(m.passwords,
+					func(password entity.Password, _ int) bool {
+						return !password.DeletedAt.Valid
+					}),
+				Counts: m.breaches,
+			}
+		},
+		func() tea.Msg {
+			return message.PanelFocused
+		},
+	)
+}
+
+// exportedItem is a password as written in clear by export.
+type exportedItem struct {
+	Name     string            `json:"name"`
+	Type     entity.ItemType   `json:"type"`
+	Category string            `json:"category,omitempty"`
+	Email    string            `json:"email,omitempty"`
+	Username string            `json:"username,omitempty"`
+	Password string            `json:"password,omitempty"`
+	Otp      string            `json:"otp,omitempty"`
+	Notes    string            `json:"notes,omitempty"`
+	Data     map[string]string `json:"data,omitempty"`
+	Fields   []exportedField   `json:"fields,omitempty"`
+	URLs     []exportedURL     `json:"urls,omitempty"`
+	Tags     []string          `json:"tags,omitempty"`
+	Favorite bool              `json:"favorite,omitempty"`
+}
+
+type exportedField struct {
+	Name      string `json:"name"`
+	Value     string `json:"value"`
+	Concealed bool   `json:"concealed,omitempty"`
+}
+
+type exportedURL struct {
+	URL  string        `json:"url"`
+	Rule urlmatch.Rule `json:"match"`
+}
+
+// export writes the given passwords out of the trash, decrypted, to
+// a JSON file in the home directory that only the user can read.
+func (m Model) export(passwords []entity.Password) tea.Msg {
+	items := lo.FilterMap(passwords,
+		func(password entity.Password, _ int) (exportedItem, bool) {
+			return exportedItem{
+				Name:     password.Name,
+				Type:     password.Type,
+				Category: m.categories[password.CategoryId.Int64],
+				Email:    password.Email,
+				Username: password.Username,
+				Password: password.Password,
+				Otp:      password.Otp,
+				Notes:    password.Notes,
+				Data:     password.Data,
+				Fields: lo.Map(password.Fields,
+					func(field entity.Field, _ int) exportedField {
+						return exportedField{
+							Name:      field.Name,
+							Value:     field.Value,
+							Concealed: field.Concealed,
+						}
+					}),
+				URLs: lo.Map(password.URLs,
+					func(url entity.URL, _ int) exportedURL {
+						return exportedURL{URL: url.URL, Rule: url.Rule}
+					}),
+				Tags:     password.Tags,
+				Favorite: password.Favorite,
+			}, !password.DeletedAt.Valid
+		})
+
+	data, err := json.MarshalIndent(items, "", "  ")
+	if err != nil {
+		log.Error("shelf.(Model).export: failed encoding items", "err", err)
+		return notification.ShowMsg{
+			Message: "Failed exporting items",
+			Level:   notification.LevelError,
+		}
+	}
+	homedir, err := os.UserHomeDir()
+	if err != nil {
+		log.Error("shelf.(Model).export: failed getting home directory",
+			"err", err)
+		return notification.ShowMsg{
+			Message: "Failed exporting items",
+			Level:   notification.LevelError,
+		}
+	}
+	path := filepath.Join(homedir,
+		"viscue-export-"+time.Now().Format("20060102-150405")+".json")
+	if err = os.WriteFile(path, data, 0o600); err != nil {
+		log.Error("shelf.(Model).export: failed writing file", "err", err)
+		return notification.ShowMsg{
+			Message: "Failed exporting items",
+			Level:   notification.LevelError,
+		}
+	}
 	return notification.ShowMsg{
-		Message: "Password is copied to clipboard",
+		Message: fmt.Sprintf("Exported %d items unencrypted to %s", len(items), path),
+		Level:   notification.LevelWarning,
 	}
 }
@@ -1,11 +1,19 @@
 package shelf
 
-import "github.com/charmbracelet/bubbles/key"
+import (
+	"viscue/tui/tool/keymap"
+
+	"github.com/charmbracelet/bubbles/key"
+)
 
 type KeyMap struct {
 	Up, Down, Switch, Help,
-	Add, Edit, Delete, Copy,
-	Search, ClearSearch key.Binding
+	Add, Edit, Delete, Copy, CopyOtp, CopyEmail, CopyUsername,
+	History, Detail, Notifications, Favorite, Restore,
+	Search, ClearSearch, Sort, Type, Breach,
+	Preview, Reveal, CopyField, Palette, Settings, Account,
+	Mark, MarkUp, MarkDown, ClearMarks,
+	Move, Tag, Export, Regenerate, Undo, Redo key.Binding
 }
 
 func (k KeyMap) ShortHelp() []key.Binding {
@@ -14,9 +22,33 @@ func (k KeyMap) ShortHelp() []key.Binding {
 
 func (k KeyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
-		{k.Up, k.Down, k.Switch, k.Help},  // first column
-		{k.Add, k.Edit, k.Delete, k.Copy}, // second column
-		{k.Search, k.ClearSearch},         // third column
+		{k.Up, k.Down, k.Switch, k.Help},                   // first column
+		{k.Add, k.Edit, k.Delete, k.Restore},               // second column
+		{k.Copy, k.CopyOtp, k.CopyEmail, k.CopyUsername},   // third column
+		{k.CopyField, k.Preview, k.Reveal, k.Favorite},     // fourth column
+		{k.History, k.Detail, k.Breach, k.Type},            // fifth column
+		{k.Search, k.ClearSearch, k.Sort, k.Notifications}, // sixth column
+		{k.Palette, k.Settings, k.Account},                 // seventh column
+		{k.Mark, k.MarkDown, k.MarkUp},                     // eighth column
+		{k.Undo, k.Redo},                                   // ninth column
+	}
+}
+
+// MarkedKeyMap shows the bulk actions first while rows are marked.
+type MarkedKeyMap struct {
+	KeyMap
+}
+
+func (k MarkedKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Mark, k.Move, k.Tag, k.Delete, k.ClearMarks}
+}
+
+func (k MarkedKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.Mark, k.MarkDown, k.MarkUp, k.ClearMarks}, // first column
+		{k.Move, k.Tag, k.Delete, k.Restore},         // second column
+		{k.Export, k.Regenerate, k.Undo, k.Redo},     // third column
+		{k.Up, k.Down, k.Switch, k.Help},             // fourth column
 	}
 }
 
@@ -49,10 +81,38 @@ var Keys = KeyMap{
 		key.WithKeys("d"),
 		key.WithHelp("d", "delete"),
 	),
+	Restore: key.NewBinding(
+		key.WithKeys("r"),
+		key.WithHelp("r", "restore from trash"),
+	),
 	Copy: key.NewBinding(
 		key.WithKeys("y"),
 		key.WithHelp("y", "copy password"),
 	),
+	CopyOtp: key.NewBinding(
+		key.WithKeys("o"),
+		key.WithHelp("o", "copy one-time password"),
+	),
+	CopyEmail: key.NewBinding(
+		key.WithKeys("E"),
+		key.WithHelp("E", "copy email"),
+	),
+	CopyUsername: key.NewBinding(
+		key.WithKeys("U"),
+		key.WithHelp("U", "copy username"),
+	),
+	History: key.NewBinding(
+		key.WithKeys("h"),
+		key.WithHelp("h", "history"),
+	),
+	Detail: key.NewBinding(
+		key.WithKeys("v"),
+		key.WithHelp("v", "view details"),
+	),
+	Notifications: key.NewBinding(
+		key.WithKeys("n"),
+		key.WithHelp("n", "notifications"),
+	),
 	Search: key.NewBinding(
 		key.WithKeys("f"),
 		key.WithHelp("f", "search"),
@@ -61,4 +121,152 @@ var Keys = KeyMap{
 		key.WithKeys("c"),
 		key.WithHelp("c", "clear search"),
 	),
+	Sort: key.NewBinding(
+		key.WithKeys("s"),
+		key.WithHelp("s", "cycle sort"),
+	),
+	Type: key.NewBinding(
+		key.WithKeys("t"),
+		key.WithHelp("t", "cycle item type"),
+	),
+	Favorite: key.NewBinding(
+		key.WithKeys("p"),
+		key.WithHelp("p", "toggle favorite"),
+	),
+	Preview: key.NewBinding(
+		key.WithKeys("i"),
+		key.WithHelp("i", "toggle details pane"),
+	),
+	Reveal: key.NewBinding(
+		key.WithKeys("ctrl+p"),
+		key.WithHelp("ctrl+p", "reveal concealed"),
+	),
+	CopyField: key.NewBinding(
+		key.WithKeys("1", "2", "3", "4", "5", "6", "7", "8", "9"),
+		key.WithHelp("1-9", "copy detail"),
+	),
+	Palette: key.NewBinding(
+		key.WithKeys("ctrl+k"),
+		key.WithHelp("ctrl+k", "commands"),
+	),
+	Breach: key.NewBinding(
+		key.WithKeys("b"),
+		key.WithHelp("b", "breach report"),
+	),
+	Settings: key.NewBinding(
+		key.WithKeys(","),
+		key.WithHelp(",", "settings"),
+	),
+	Account: key.NewBinding(
+		key.WithKeys("A"),
+		key.WithHelp("A", "account"),
+	),
+	Mark: key.NewBinding(
+		key.WithKeys(" "),
+		key.WithHelp("space", "mark"),
+	),
+	MarkUp: key.NewBinding(
+		key.WithKeys("shift+up", "K"),
+		key.WithHelp("shift+↑/K", "mark up"),
+	),
+	MarkDown: key.NewBinding(
+		key.WithKeys("shift+down", "J"),
+		key.WithHelp("shift+↓/J", "mark down"),
+	),
+	ClearMarks: key.NewBinding(
+		key.WithKeys("esc"),
+		key.WithHelp("esc", "clear marks"),
+	),
+	Move: key.NewBinding(
+		key.WithKeys("M"),
+		key.WithHelp("M", "move to category"),
+	),
+	Tag: key.NewBinding(
+		key.WithKeys("T"),
+		key.WithHelp("T", "add tags"),
+	),
+	Export: key.NewBinding(
+		key.WithKeys("X"),
+		key.WithHelp("X", "export"),
+	),
+	Regenerate: key.NewBinding(
+		key.WithKeys("G"),
+		key.WithHelp("G", "regenerate passwords"),
+	),
+	Undo: key.NewBinding(
+		key.WithKeys("u"),
+		key.WithHelp("u", "undo"),
+	),
+	Redo: key.NewBinding(
+		key.WithKeys("ctrl+r"),
+		key.WithHelp("ctrl+r", "redo"),
+	),
+}
+
+// SearchKeyMap holds the keys ending a search, every other key
+// being typed into it.
+type SearchKeyMap struct {
+	Submit, Cancel key.Binding
+}
+
+var SearchKeys = SearchKeyMap{
+	Submit: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("enter", "keep filter"),
+	),
+	Cancel: key.NewBinding(
+		key.WithKeys("esc"),
+		key.WithHelp("esc", "clear filter"),
+	),
+}
+
+// ApplyKeymap rebinds the keys of the shelf and of its search
+// remapped in the config.
+func ApplyKeymap(config keymap.Config) error {
+	err := config.Apply("shelf", keymap.Section{
+		"up":            &Keys.Up,
+		"down":          &Keys.Down,
+		"switch":        &Keys.Switch,
+		"help":          &Keys.Help,
+		"add":           &Keys.Add,
+		"edit":          &Keys.Edit,
+		"delete":        &Keys.Delete,
+		"restore":       &Keys.Restore,
+		"copy":          &Keys.Copy,
+		"copy_otp":      &Keys.CopyOtp,
+		"copy_email":    &Keys.CopyEmail,
+		"copy_username": &Keys.CopyUsername,
+		"copy_field":    &Keys.CopyField,
+		"preview":       &Keys.Preview,
+		"reveal":        &Keys.Reveal,
+		"favorite":      &Keys.Favorite,
+		"history":       &Keys.History,
+		"detail":        &Keys.Detail,
+		"breach":        &Keys.Breach,
+		"type":          &Keys.Type,
+		"search":        &Keys.Search,
+		"clear_search":  &Keys.ClearSearch,
+		"sort":          &Keys.Sort,
+		"notifications": &Keys.Notifications,
+		"palette":       &Keys.Palette,
+		"settings":      &Keys.Settings,
+		"account":       &Keys.Account,
+		"mark":          &Keys.Mark,
+		"mark_up":       &Keys.MarkUp,
+		"mark_down":     &Keys.MarkDown,
+		"clear_marks":   &Keys.ClearMarks,
+		"move":          &Keys.Move,
+		"tag":           &Keys.Tag,
+		"export":        &Keys.Export,
+		"regenerate":    &Keys.Regenerate,
+		"undo":          &Keys.Undo,
+		"redo":          &Keys.Redo,
+	})
+	if err != nil {
+		return err
+	}
+	return config.Apply("search", keymap.Section{
+		"submit": &SearchKeys.Submit,
+		"cancel": &SearchKeys.Cancel,
+	})
 }
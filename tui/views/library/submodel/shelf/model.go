@@ -1,13 +1,23 @@
 package shelf
 
 import (
+	"database/sql"
+	"slices"
+
+	"viscue/tui/component/notification"
 	"viscue/tui/component/table"
 	"viscue/tui/entity"
 	"viscue/tui/style"
+	"viscue/tui/tool/breach"
+	"viscue/tui/tool/zone"
 	"viscue/tui/views/library/message"
+	"viscue/tui/views/library/submodel/history"
+	"viscue/tui/views/library/submodel/preview"
 	"viscue/tui/views/library/submodel/prompt"
+	"viscue/tui/views/library/submodel/sidebar"
 
 	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -21,12 +31,23 @@ type Model struct {
 	db *sqlx.DB
 
 	// Component
-	search textinput.Model
-	table  table.Model
+	search  textinput.Model
+	table   table.Model
+	preview preview.Model
 
 	// State
 	passwords          []entity.Password
 	selectedCategoryId *int64
+	descendantIds      []int64 // categories nested under the selected one
+	selectedTags       []string
+	matchAllTags       bool
+	categories         map[int64]string // category id to path, used for sorting
+	sortOrder          SortOrder
+	itemType           entity.ItemType // empty shows every type
+	breachChecker      breach.Checker
+	breaches           map[int64]int // password id to times seen in a breach
+	showPreview        bool
+	zoneId             string // prefixes the zones of the pane for mouse events
 
 	// Style
 	paneBorder lipgloss.Style
@@ -35,29 +56,30 @@ type Model struct {
 func New(db *sqlx.DB) tea.Model {
 	search := textinput.New()
 	search.Prompt = "🔍: "
-	search.Placeholder = "Search password..."
+	search.Placeholder = "Search, e.g. tag:prod cat:Work -old"
 	search.Cursor.SetMode(cursor.CursorStatic)
 	defaultSelectedCategoryId := int64(0)
+	breachChecker, err := breach.FromEnv()
+	if err != nil {
+		log.Error("shelf.New: failed setting up breach checker", "err", err)
+	}
 
 	m := Model{
 		db:     db,
 		search: search,
 		table: table.New(
-			table.WithColumns(
-				[]table.Column{
-					{Title: "Id", Width: 0},
-					{Title: "CategoryId", Width: 0},
-					{Title: "Name", Width: 24},
-					{Title: "Email", Width: 24},
-					{Title: "Username", Width: 24},
-					{Title: "Password", Width: 0},
-				}),
 			table.WithFocused(true),
 		),
 		selectedCategoryId: &defaultSelectedCategoryId,
+		categories:         make(map[int64]string),
+		preview:            preview.New(),
+		breachChecker:      breachChecker,
+		breaches:           make(map[int64]int),
+		zoneId:             zone.NewPrefix(),
 		paneBorder:         style.PaneBorderStyle,
 	}
 
+	m.setColumns()
 	m.calculateDimension()
 	return m
 }
@@ -65,6 +87,7 @@ func New(db *sqlx.DB) tea.Model {
 func (m Model) Init() tea.Cmd {
 	return tea.Batch(
 		m.LoadItems,
+		m.TickOtp(),
 		func() tea.Msg {
 			if err := clipboard.Init(); err != nil {
 				log.Error("clipboard init failed", "err", err)
@@ -76,37 +99,189 @@ func (m Model) Init() tea.Cmd {
 }
 
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	marked := m.marked()
+	model, cmd := m.update(msg)
+	// Whatever happened, the preview follows the selected password
+	next := model.(Model)
+	password, ok := next.selectedPassword()
+	next.preview.SetPassword(password, ok)
+	if next.table.Focused() && next.marked() != marked {
+		// The help shows the bulk actions while rows are marked
+		cmd = tea.Batch(cmd, next.SendSetKeysMsg)
+	}
+	return next, cmd
+}
+
+func (m Model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case DataLoadedMsg:
 		m.passwords = msg.Data
+		m.sort()
 		m.sync()
+		return m, m.CheckBreaches(m.passwords)
+	case sidebar.DataLoadedMsg:
+		clear(m.categories)
+		for _, category := range msg.Data {
+			m.categories[category.Id] = entity.Path(msg.Data, category.Id)
+		}
+		if m.sortOrder == SortByCategory {
+			m.sort()
+			m.refresh()
+		}
 		return m, nil
-	case prompt.DataSubmittedMsg[entity.Password]:
-		m.append(msg.Data)
+	case prompt.DataSubmittedMsg[entity.Category]:
+		// Names are only unique within their parent, hence the path
+		name := msg.Data.Name
+		if msg.Data.ParentId.Valid {
+			name = m.categories[msg.Data.ParentId.Int64] + "/" + name
+		}
+		m.categories[msg.Data.Id] = name
+		return m, nil
+	case prompt.DeleteConfirmedMsg[entity.Category]:
+		// Its passwords have been trashed or moved elsewhere
+		delete(m.categories, msg.Payload.Id)
+		return m, m.LoadItems
+	case OtpTickMsg:
+		return m, m.TickOtp()
+	case OtpAdvancedMsg:
+		_, index, found := lo.FindIndexOf(m.passwords,
+			func(password entity.Password) bool {
+				return password.Id == msg.Id
+			})
+		if found {
+			m.passwords[index].Otp = msg.Otp
+		}
 		return m, func() tea.Msg {
-			return message.ClosePromptMsg[entity.Password]{}
+			return msg.Copied
+		}
+	case PasswordUsedMsg:
+		_, index, found := lo.FindIndexOf(m.passwords,
+			func(password entity.Password) bool {
+				return password.Id == msg.Id
+			})
+		if found {
+			m.passwords[index].LastUsedAt = sql.NullTime{Time: msg.At, Valid: true}
+			m.sort()
+			m.refresh()
+		}
+		return m, nil
+	case prompt.TrashedMsg:
+		_, index, found := lo.FindIndexOf(m.passwords,
+			func(password entity.Password) bool {
+				return password.Id == msg.Payload.Id
+			})
+		if found {
+			m.passwords[index].DeletedAt = msg.Payload.DeletedAt
+			m.refresh()
+		}
+		return m, tea.Batch(
+			func() tea.Msg {
+				return message.ClosePromptMsg[entity.Password]{}
+			},
+			func() tea.Msg {
+				return notification.ShowMsg{
+					Message: "Moved to trash",
+					Level:   notification.LevelSuccess,
+				}
+			},
+		)
+	case RestoredFromTrashMsg:
+		_, index, found := lo.FindIndexOf(m.passwords,
+			func(password entity.Password) bool {
+				return password.Id == msg.Id
+			})
+		if found {
+			m.passwords[index].DeletedAt = sql.NullTime{}
+			m.refresh()
+		}
+		return m, func() tea.Msg {
+			return notification.ShowMsg{
+				Message: "Restored from trash",
+				Level:   notification.LevelSuccess,
+			}
+		}
+	case FavoriteToggledMsg:
+		_, index, found := lo.FindIndexOf(m.passwords,
+			func(password entity.Password) bool {
+				return password.Id == msg.Id
+			})
+		if !found {
+			return m, nil
+		}
+		m.passwords[index].Favorite = msg.Favorite
+		m.sort()
+		m.refresh()
+		text := "Removed from favorites"
+		if msg.Favorite {
+			text = "Added to favorites"
+		}
+		return m, func() tea.Msg {
+			return notification.ShowMsg{Message: text}
+		}
+	case BreachCheckedMsg:
+		for id, count := range msg.Counts {
+			if count > 0 {
+				m.breaches[id] = count
+			} else {
+				delete(m.breaches, id)
+			}
 		}
+		m.refresh()
+		return m, nil
+	case prompt.DataSubmittedMsg[entity.Password]:
+		m.append(msg.Data)
+		return m, tea.Batch(
+			func() tea.Msg {
+				return message.ClosePromptMsg[entity.Password]{}
+			},
+			m.CheckBreaches([]entity.Password{msg.Data}),
+		)
+	case history.RestoredMsg:
+		m.append(msg.Password)
+		return m, tea.Batch(
+			func() tea.Msg {
+				return message.ClosePanelMsg{}
+			},
+			m.CheckBreaches([]entity.Password{msg.Password}),
+		)
 	case prompt.DeleteConfirmedMsg[entity.Password]:
 		m.passwords = lo.Filter(m.passwords,
 			func(item entity.Password, index int) bool {
 				return item.Id != msg.Payload.Id
 			},
 		)
+		delete(m.breaches, msg.Payload.Id)
 		m.sync()
 		return m, func() tea.Msg {
 			return message.ClosePromptMsg[entity.Password]{}
 		}
 	case message.CategorySelectedMsg:
-		m.selectedCategoryId = msg
+		m.selectedCategoryId = msg.Id
+		m.descendantIds = msg.Descendants
 		m.sync()
 		return m, nil
+	case message.TagsSelectedMsg:
+		m.selectedTags = msg.Tags
+		m.matchAllTags = msg.MatchAll
+		m.refresh()
+		return m, nil
+	case prompt.BulkAppliedMsg:
+		m.table.ClearMarks()
+		closePrompt := func() tea.Msg {
+			return message.ClosePromptMsg[entity.Password]{}
+		}
+		if msg.Action == message.BulkExport {
+			return m, tea.Batch(closePrompt, func() tea.Msg {
+				return m.export(msg.Passwords)
+			})
+		}
+		return m, tea.Batch(closePrompt, m.LoadItems, m.notifyBulkApplied(msg))
 	case message.SwitchFocusMsg:
 		if msg == message.ShelfFocused {
 			m.table.Focus()
-			return m, func() tea.Msg {
-				return message.SetHelpKeysMsg{Keys: Keys}
-			}
+			return m, m.SendSetKeysMsg
 		} else {
+			m.search.Blur()
 			m.table.Blur()
 			return m, nil
 		}
@@ -123,6 +298,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		m.calculateDimension()
 		return m, nil
+	case tea.MouseMsg:
+		return m.updateMouse(msg)
 	case tea.KeyMsg:
 		if !m.table.Focused() {
 			// Since our parent model passes msg to both
@@ -130,13 +307,13 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			// we ignore msg if our model is not focused
 			return m, nil
 		} else if m.search.Focused() {
-			switch msg.String() {
-			case "esc":
+			switch {
+			case key.Matches(msg, SearchKeys.Cancel):
 				m.search.SetValue("")
 				m.search.Blur()
 				m.filter()
 				return m, nil
-			case "enter":
+			case key.Matches(msg, SearchKeys.Submit):
 				m.search.Blur()
 				return m, nil
 			}
@@ -145,30 +322,107 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.filter()
 			return m, searchCmd
 		} else {
-			switch msg.String() {
-			case "j", "down", "k", "up":
-				var cmd tea.Cmd
-				m.table, cmd = m.table.Update(msg)
-				return m, cmd
-			case "ctrl+h":
+			switch {
+			case key.Matches(msg, Keys.Up):
+				m.table.Up()
+				return m, nil
+			case key.Matches(msg, Keys.Down):
+				m.table.Down()
+				return m, nil
+			case key.Matches(msg, Keys.Switch):
 				m.table.Blur()
 				m.search.Blur()
 				return m, func() tea.Msg { return message.SidebarFocused }
-			case "y":
-				return m, m.CopyToClipboard
-			case "a":
+			case key.Matches(msg, Keys.Copy):
+				return m, tea.Batch(m.CopyToClipboard, m.MarkAsUsed)
+			case key.Matches(msg, Keys.CopyOtp):
+				return m, tea.Batch(m.CopyOtpToClipboard, m.MarkAsUsed)
+			case key.Matches(msg, Keys.CopyEmail):
+				return m, m.CopyValue(entity.KeyEmail)
+			case key.Matches(msg, Keys.CopyUsername):
+				return m, m.CopyValue(entity.KeyUsername)
+			case key.Matches(msg, Keys.Add):
 				return m, m.AddPasswordPromptMsg()
-			case "e", "enter":
+			case key.Matches(msg, Keys.Edit):
 				return m, m.EditPasswordPromptMsg()
-			case "d":
+			case key.Matches(msg, Keys.Mark):
+				m.table.ToggleMark()
+				m.table.Down()
+				return m, nil
+			case key.Matches(msg, Keys.MarkUp):
+				m.table.MarkTo(m.table.Index() - 1)
+				return m, nil
+			case key.Matches(msg, Keys.MarkDown):
+				m.table.MarkTo(m.table.Index() + 1)
+				return m, nil
+			case key.Matches(msg, Keys.ClearMarks):
+				m.table.ClearMarks()
+				return m, nil
+			case key.Matches(msg, Keys.Move):
+				return m, m.BulkPromptMsg(message.BulkMove)
+			case key.Matches(msg, Keys.Tag):
+				return m, m.BulkPromptMsg(message.BulkTag)
+			case key.Matches(msg, Keys.Export):
+				return m, m.BulkPromptMsg(message.BulkExport)
+			case key.Matches(msg, Keys.Regenerate):
+				return m, m.BulkPromptMsg(message.BulkRegenerate)
+			case key.Matches(msg, Keys.Undo):
+				return m, func() tea.Msg { return message.UndoMsg{} }
+			case key.Matches(msg, Keys.Redo):
+				return m, func() tea.Msg { return message.RedoMsg{} }
+			case key.Matches(msg, Keys.Delete):
+				if m.marked() {
+					return m, m.BulkPromptMsg(message.BulkDelete)
+				}
 				return m, m.DeletePasswordPromptMsg()
-			case "f":
+			case key.Matches(msg, Keys.History):
+				return m, m.HistoryMsg()
+			case key.Matches(msg, Keys.Detail):
+				return m, m.DetailMsg()
+			case key.Matches(msg, Keys.Notifications):
+				return m, m.NotificationsMsg()
+			case key.Matches(msg, Keys.Settings):
+				return m, m.SettingsMsg()
+			case key.Matches(msg, Keys.Account):
+				return m, m.AccountMsg()
+			case key.Matches(msg, Keys.Search):
 				m.search.Focus()
 				return m, textinput.Blink
-			case "c":
+			case key.Matches(msg, Keys.ClearSearch):
 				m.search.Blur()
 				m.search.SetValue("")
 				return m, nil
+			case key.Matches(msg, Keys.Sort):
+				m.sortOrder = m.sortOrder.Next()
+				m.sort()
+				m.refresh()
+				return m, nil
+			case key.Matches(msg, Keys.Breach):
+				return m, m.BreachReportMsg()
+			case key.Matches(msg, Keys.Favorite):
+				return m, m.ToggleFavorite
+			case key.Matches(msg, Keys.Restore):
+				if m.marked() {
+					return m, m.BulkPromptMsg(message.BulkRestore)
+				}
+				return m, m.RestoreFromTrash
+			case key.Matches(msg, Keys.Preview):
+				m.showPreview = !m.showPreview
+				m.calculateDimension()
+				return m, nil
+			case key.Matches(msg, Keys.Reveal):
+				m.preview.ToggleConcealed()
+				return m, nil
+			case key.Matches(msg, Keys.CopyField):
+				// The n-th key of the binding copies the n-th entry
+				n := slices.Index(Keys.CopyField.Keys(), msg.String()) + 1
+				return m, m.CopyEntry(n)
+			case key.Matches(msg, Keys.Type):
+				m.itemType = nextItemType(m.itemType)
+				m.setColumns()
+				m.calculateDimension()
+				m.refresh()
+				return m, nil
 			}
 		}
 	}
@@ -179,21 +433,36 @@ func (m Model) View() string {
 	// Title
 	titleStyle := style.ModelTitleStyle
 	if m.table.Focused() {
-		titleStyle = style.ModelTitleFocusedStyle
+		titleStyle = style.Emphasize(style.ModelTitleFocusedStyle)
 	}
 
 	// Search Box
 	searchBoxStyle := style.SearchBoxStyle.
 		Width(m.table.Width()).
-		BorderForeground(style.ColorGray)
+		BorderForeground(style.ColorMuted)
 	if m.search.Focused() {
-		searchBoxStyle = searchBoxStyle.BorderForeground(style.ColorPurple)
+		searchBoxStyle = searchBoxStyle.BorderForeground(style.ColorAccent)
 	}
+	compact := style.IsCompact()
+	if compact {
+		searchBoxStyle = searchBoxStyle.BorderTop(false).BorderBottom(false)
+	}
+
+	title := titleStyle.Render("Password")
+	sortLabel := lipgloss.NewStyle().Foreground(style.ColorMuted).
+		MarginBottom(titleStyle.GetMarginBottom()).
+		MaxWidth(m.table.Width() - lipgloss.Width(title)).
+		Render(m.filterLabel())
 
-	return m.paneBorder.Render(lipgloss.JoinVertical(
+	view := m.paneBorder.Render(lipgloss.JoinVertical(
 		lipgloss.Left,
-		titleStyle.Render("Password"),
-		searchBoxStyle.Render(m.search.View()),
+		lipgloss.JoinHorizontal(lipgloss.Top, title, sortLabel),
+		zone.Mark(m.zoneId+"search", searchBoxStyle.Render(m.search.View())),
 		m.table.View(),
+		m.otpView(),
 	))
+	if m.showPreview && !compact {
+		view = lipgloss.JoinHorizontal(lipgloss.Top, view, m.preview.View())
+	}
+	return zone.Mark(m.zoneId+"pane", view)
 }
@@ -1,103 +1,408 @@
 package shelf
 
 import (
+	"fmt"
+	"slices"
 	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
 
 	"viscue/tui/component/table"
 	"viscue/tui/entity"
 	"viscue/tui/style"
 	"viscue/tui/tool/cache"
+	"viscue/tui/tool/crypto"
+	"viscue/tui/tool/otp"
+	"viscue/tui/tool/query"
+	"viscue/tui/tool/zone"
+	"viscue/tui/views/library/message"
 
-	"github.com/sahilm/fuzzy"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 	"github.com/samber/lo"
 )
 
+// toTableRow builds the table row of the password, flagging
+// it when it was found in a breach.
+func (m Model) toTableRow(password entity.Password) table.Row {
+	row := password.ToTableRow()
+	if m.itemType == "" {
+		row[3], row[4] = password.Type.String(), row[3]
+	}
+	if m.breaches[password.Id] > 0 {
+		row[2] = "⚠ " + row[2]
+	}
+	if password.Favorite {
+		row[2] = "★ " + row[2]
+	}
+	return row
+}
+
+// selectedPassword returns the password under the table cursor.
+func (m Model) selectedPassword() (entity.Password, bool) {
+	row := m.table.SelectedRow()
+	if row == nil {
+		return entity.Password{}, false
+	}
+	id, err := strconv.ParseInt(row[0], 10, 64)
+	if err != nil {
+		return entity.Password{}, false
+	}
+	return lo.Find(m.passwords, func(password entity.Password) bool {
+		return password.Id == id
+	})
+}
+
+// marked tells whether any row of the table is marked.
+func (m Model) marked() bool {
+	return len(m.table.MarkedRows()) > 0
+}
+
+// markedPasswords returns the passwords of the marked rows, or
+// the one under the cursor when none is marked.
+func (m Model) markedPasswords() []entity.Password {
+	rows := m.table.MarkedRows()
+	if len(rows) == 0 {
+		if password, ok := m.selectedPassword(); ok {
+			return []entity.Password{password}
+		}
+		return nil
+	}
+	ids := lo.Map(rows, func(row table.Row, _ int) string { return row[0] })
+	return lo.Filter(m.passwords, func(password entity.Password, _ int) bool {
+		return lo.Contains(ids, strconv.FormatInt(password.Id, 10))
+	})
+}
+
+type SortOrder int
+
+const (
+	SortByName SortOrder = iota
+	SortByCategory
+	SortByRecentlyUsed
+	SortByRecentlyModified
+)
+
+func (order SortOrder) String() string {
+	switch order {
+	case SortByCategory:
+		return "category"
+	case SortByRecentlyUsed:
+		return "recently used"
+	case SortByRecentlyModified:
+		return "recently modified"
+	default:
+		return "name"
+	}
+}
+
+// Next returns the following sort order, wrapping around.
+func (order SortOrder) Next() SortOrder {
+	return (order + 1) % (SortByRecentlyModified + 1)
+}
+
+// sort orders the passwords by the current sort order, favorites
+// first, using the name as the tiebreaker.
+func (m *Model) sort() {
+	byName := func(a, b entity.Password) bool {
+		return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+	}
+	sort.SliceStable(m.passwords, func(i, j int) bool {
+		a, b := m.passwords[i], m.passwords[j]
+		if a.Favorite != b.Favorite {
+			// Favorites are pinned on top whatever the order
+			return a.Favorite
+		}
+		switch m.sortOrder {
+		case SortByCategory:
+			if a.CategoryId.Valid != b.CategoryId.Valid {
+				// Uncategorized goes last
+				return a.CategoryId.Valid
+			}
+			nameA := strings.ToLower(m.categories[a.CategoryId.Int64])
+			nameB := strings.ToLower(m.categories[b.CategoryId.Int64])
+			if nameA != nameB {
+				return nameA < nameB
+			}
+		case SortByRecentlyUsed:
+			if !a.LastUsedAt.Time.Equal(b.LastUsedAt.Time) {
+				return a.LastUsedAt.Time.After(b.LastUsedAt.Time)
+			}
+		case SortByRecentlyModified:
+			if !a.UpdatedAt.Equal(b.UpdatedAt) {
+				return a.UpdatedAt.After(b.UpdatedAt)
+			}
+		}
+		return byName(a, b)
+	})
+}
+
+// refresh rebuilds the rows while keeping the
+// cursor on the currently selected password.
+func (m *Model) refresh() {
+	selected, ok := m.selectedPassword()
+	m.filter()
+	if !ok {
+		return
+	}
+	_, index, found := lo.FindIndexOf(m.table.Rows(), func(row table.Row) bool {
+		return row[0] == strconv.FormatInt(selected.Id, 10)
+	})
+	if found {
+		m.table.SetIndex(index)
+	}
+}
+
+// visible tells whether the password matches the selected
+// category, tags and item type.
+func (m Model) visible(password entity.Password) bool {
+	if m.itemType != "" && password.Type != m.itemType {
+		return false
+	}
+	if len(m.selectedTags) > 0 {
+		if m.matchAllTags && !lo.EveryBy(m.selectedTags, password.HasTag) {
+			return false
+		}
+		if !m.matchAllTags && !lo.SomeBy(m.selectedTags, password.HasTag) {
+			return false
+		}
+	}
+	if m.selectedCategoryId == nil {
+		return false
+	}
+	// Trashed passwords only show up in the trash
+	if password.DeletedAt.Valid != (*m.selectedCategoryId == -3) {
+		return false
+	}
+	switch *m.selectedCategoryId {
+	case 0:
+		return true
+	case -1:
+		return !password.CategoryId.Valid
+	case -2:
+		return password.Favorite
+	case -3:
+		return true
+	default:
+		return password.CategoryId.Valid &&
+			(password.CategoryId.Int64 == *m.selectedCategoryId ||
+				slices.Contains(m.descendantIds, password.CategoryId.Int64))
+	}
+}
+
+// filter shows the visible passwords matching the search query,
+// emphasizing the characters of their name that were matched.
 func (m *Model) filter() {
-	value := m.search.Value()
-	if value == "" {
+	search := query.Parse(m.search.Value())
+	if len(search) == 0 {
 		m.sync()
 		return
 	}
 
-	var passwordsByCategory []entity.Password
-	if m.selectedCategoryId != nil {
-		switch *m.selectedCategoryId {
-		case 0:
-			passwordsByCategory = m.passwords
-		case -1:
-			passwordsByCategory = lo.Filter(m.passwords,
-				func(password entity.Password, index int) bool {
-					return !password.CategoryId.Valid
-				})
-		default:
-			passwordsByCategory = lo.Filter(m.passwords,
-				func(password entity.Password, index int) bool {
-					return password.CategoryId.Int64 == *m.selectedCategoryId &&
-						password.CategoryId.Valid
-				})
+	var rows []table.Row
+	highlights := table.Highlights{}
+	for _, password := range m.passwords {
+		if !m.visible(password) {
+			continue
+		}
+		matched, ok := search.Match(m.document(password))
+		if !ok {
+			continue
+		}
+		row := m.toTableRow(password)
+		if len(matched) > 0 {
+			// The name may be prefixed with the favorite and breach marks
+			offset := utf8.RuneCountInString(row[2]) -
+				utf8.RuneCountInString(password.Name)
+			highlights[len(rows)] = map[int][]int{
+				2: lo.Map(matched, func(index int, _ int) int {
+					return index + offset
+				}),
+			}
 		}
+		rows = append(rows, row)
 	}
-	ranks := fuzzy.Find(value,
-		lo.Map(passwordsByCategory,
-			func(password entity.Password, _ int) string {
-				return password.Name
-			},
-		),
-	)
-	sort.Stable(ranks)
-	indexes := lo.Map(ranks, func(match fuzzy.Match, _ int) int {
-		return match.Index
-	})
-	rows := lo.FilterMap(passwordsByCategory,
-		func(password entity.Password, index int) (table.Row, bool) {
-			return password.ToTableRow(), lo.Contains(
-				indexes,
-				index,
-			)
-		},
-	)
 	m.table.SetRows(rows)
+	m.table.SetHighlights(highlights)
+}
+
+// document lists what the search looks through in the password.
+func (m Model) document(password entity.Password) query.Document {
+	var flags []string
+	if password.Favorite {
+		flags = append(flags, "favorite")
+	}
+	if m.breaches[password.Id] > 0 {
+		flags = append(flags, "breached")
+	}
+	if password.Otp != "" {
+		flags = append(flags, "otp")
+	}
+	if password.Password != "" && crypto.IsWeakPassword(password.Password) {
+		flags = append(flags, "weak")
+	}
+	if password.DeletedAt.Valid {
+		flags = append(flags, "trashed")
+	}
+	var categories []string
+	if password.CategoryId.Valid {
+		categories = append(categories, m.categories[password.CategoryId.Int64])
+	}
+	return query.Document{
+		Name: password.Name,
+		Fields: map[query.Field][]string{
+			query.FieldEmail:    {password.Value(entity.KeyEmail)},
+			query.FieldUsername: {password.Value(entity.KeyUsername)},
+			query.FieldURL: lo.Map(password.URLs,
+				func(url entity.URL, _ int) string {
+					return url.URL
+				}),
+			query.FieldTag:      password.Tags,
+			query.FieldCategory: categories,
+			query.FieldNotes:    {password.Notes},
+			query.FieldType:     {string(password.Type), password.Type.String()},
+			query.FieldIs:       flags,
+		},
+	}
 }
 
 func (m *Model) sync() {
-	var rows []table.Row
-	if m.selectedCategoryId != nil {
-		switch *m.selectedCategoryId {
-		case 0:
-			rows = lo.Map(m.passwords,
-				func(password entity.Password, index int) table.Row {
-					return password.ToTableRow()
-				})
-		case -1:
-			rows = lo.FilterMap(m.passwords,
-				func(password entity.Password, index int) (table.Row, bool) {
-					return password.ToTableRow(), !password.CategoryId.Valid
-				})
-		default:
-			rows = lo.FilterMap(m.passwords,
-				func(password entity.Password, index int) (table.Row, bool) {
-					return password.ToTableRow(),
-						password.CategoryId.Int64 == *m.selectedCategoryId &&
-							password.CategoryId.Valid
-				})
-		}
+	m.table.SetRows(lo.FilterMap(m.passwords,
+		func(password entity.Password, _ int) (table.Row, bool) {
+			return m.toTableRow(password), m.visible(password)
+		}))
+}
+
+// nextItemType returns the following type filter, going through
+// every type before showing all of them again.
+func nextItemType(itemType entity.ItemType) entity.ItemType {
+	switch itemType {
+	case "":
+		return entity.ItemTypes[0]
+	case entity.ItemTypes[len(entity.ItemTypes)-1]:
+		return ""
+	default:
+		return itemType.Next()
 	}
-	m.table.SetRows(rows)
+}
+
+// setColumns titles the columns after the type filter. Showing
+// every type, the columns hold the type and its first value.
+func (m *Model) setColumns() {
+	first, second := "Type", "Details"
+	if m.itemType != "" {
+		schema := m.itemType.Schema()
+		first = schema.ColumnTitle(schema.Columns[0])
+		second = schema.ColumnTitle(schema.Columns[1])
+	}
+	widths := lo.Map(m.table.Columns(), func(column table.Column, _ int) int {
+		return column.Width
+	})
+	if len(widths) == 0 {
+		widths = []int{0, 0, 24, 24, 24, 24, 0}
+	}
+	m.table.SetColumns([]table.Column{
+		{Title: "Id", Width: widths[0]},
+		{Title: "CategoryId", Width: widths[1]},
+		{Title: "Name", Width: widths[2]},
+		{Title: first, Width: widths[3]},
+		{Title: second, Width: widths[4]},
+		{Title: "URL", Width: widths[5]},
+		{Title: "Password", Width: widths[6]},
+	})
+}
+
+func (m Model) filterLabel() string {
+	label := " sorted by " + m.sortOrder.String()
+	if m.itemType != "" {
+		label = " " + m.itemType.String() + " only," + label
+	}
+	if marked := len(m.table.MarkedRows()); marked > 0 {
+		label += fmt.Sprintf(", %d marked", marked)
+	}
+	return label
 }
 
 func (m *Model) calculateDimension() {
+	if style.IsCompact() {
+		m.calculateCompactDimension()
+		return
+	}
 	appHeight := style.CalculateAppHeight() - 2
 	appWidth := cache.Get[int](cache.TerminalWidth) - 6
 	shelfWidth := appWidth * 60 / 100
+	if m.showPreview {
+		// The preview takes what the sidebar and the shelf leave,
+		// both panes counting their border and padding.
+		shelfWidth = appWidth * 45 / 100
+		sidebarWidth := appWidth * 20 / 100
+		previewWidth := appWidth + 6 - (sidebarWidth + 6) - (shelfWidth + 6) - 3
+		m.preview.SetSize(previewWidth, appHeight)
+	}
 	paneWidth := shelfWidth + 4
-	columnWidth := (shelfWidth - 8) / 3
-	m.table.SetHeight(appHeight - 8)
+	m.table.SetHeight(appHeight - 9) // Leave a line for the one-time password
 	m.table.SetWidth(shelfWidth)
-	m.table.SetColumnsWidth(0, 0, columnWidth, columnWidth, columnWidth, 0)
+	if m.itemType == "" || m.itemType.Schema().URLs {
+		columnWidth := (shelfWidth - 10) / 4
+		m.table.SetColumnsWidth(0, 0, columnWidth, columnWidth, columnWidth,
+			columnWidth, 0)
+	} else {
+		columnWidth := (shelfWidth - 8) / 3
+		m.table.SetColumnsWidth(0, 0, columnWidth, columnWidth, columnWidth, 0, 0)
+	}
 	m.search.Width = shelfWidth - 11
 	m.paneBorder = m.paneBorder.Height(appHeight).
 		MaxHeight(appHeight + 2).
-		Width(paneWidth)
+		Width(paneWidth).
+		PaddingTop(1).
+		PaddingBottom(1)
+}
+
+// calculateCompactDimension gives the shelf the whole width below
+// the collapsed sidebar, without the preview and keeping only the
+// name and the first column of the type.
+func (m *Model) calculateCompactDimension() {
+	appHeight := style.CalculateAppHeight() - 3 // Leave a line for the sidebar
+	shelfWidth := cache.Get[int](cache.TerminalWidth) - 6
+	m.table.SetHeight(appHeight - 5) // Leave a line for the one-time password
+	m.table.SetWidth(shelfWidth)
+	columnWidth := (shelfWidth - 6) / 2
+	m.table.SetColumnsWidth(0, 0, columnWidth, columnWidth, 0, 0, 0)
+	m.search.Width = shelfWidth - 11
+	m.paneBorder = m.paneBorder.Height(appHeight).
+		MaxHeight(appHeight + 2).
+		Width(shelfWidth + 4).
+		PaddingTop(0).
+		PaddingBottom(0)
+}
+
+// otpView renders the current one-time password of the
+// selected password along with its countdown.
+func (m Model) otpView() string {
+	password, ok := m.selectedPassword()
+	if !ok || password.Otp == "" {
+		return ""
+	}
+
+	label := lipgloss.NewStyle().Foreground(style.ColorMuted).Render
+	key, err := otp.Parse(password.Otp)
+	if err != nil {
+		return label("OTP ") + lipgloss.NewStyle().Foreground(style.ColorError).
+			Render("invalid secret")
+	}
+
+	now := time.Now()
+	code := key.Code(now)
+	code = code[:len(code)/2] + " " + code[len(code)/2:]
+	if key.Type == otp.HOTP {
+		return label("OTP ") + code + label(
+			fmt.Sprintf(" · counter %d", key.Counter))
+	}
+	return label("OTP ") + code + label(
+		fmt.Sprintf(" · %ds", int(key.Remaining(now).Seconds())))
 }
 
 func (m *Model) append(payload entity.Password) {
@@ -107,11 +412,54 @@ func (m *Model) append(payload entity.Password) {
 		})
 	if !found {
 		m.passwords = append(m.passwords, payload)
-		sort.Slice(m.passwords, func(i, j int) bool {
-			return m.passwords[i].Id < m.passwords[j].Id
-		})
 	} else {
 		m.passwords[index] = payload
 	}
+	m.sort()
 	m.sync()
 }
+
+// Passwords returns the passwords out of the trash, in the shelf order.
+func (m Model) Passwords() []entity.Password {
+	return lo.Filter(m.passwords, func(password entity.Password, _ int) bool {
+		return !password.DeletedAt.Valid
+	})
+}
+
+// Selected returns the password under the table cursor.
+func (m Model) Selected() (entity.Password, bool) {
+	return m.selectedPassword()
+}
+
+// Category returns the path of the category with the id.
+func (m Model) Category(id int64) string {
+	return m.categories[id]
+}
+
+// updateMouse focuses the shelf when clicked, selecting the password
+// under the mouse. Double clicking a password edits it, and the wheel
+// scrolls the table.
+func (m Model) updateMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	if _, ok := zone.Hit(m.zoneId+"pane", msg); !ok {
+		return m, nil
+	}
+	if _, ok := zone.IsWheel(msg); ok {
+		var cmd tea.Cmd
+		m.table, cmd = m.table.Update(msg)
+		return m, cmd
+	}
+	if !zone.IsClick(msg) {
+		return m, nil
+	}
+
+	focus := func() tea.Msg { return message.ShelfFocused }
+	if _, ok := zone.Hit(m.zoneId+"search", msg); ok {
+		m.search.Focus()
+		return m, tea.Batch(focus, textinput.Blink)
+	}
+	m.search.Blur()
+	if double, ok := m.table.Click(msg); ok && double {
+		return m, tea.Sequence(focus, m.EditPasswordPromptMsg())
+	}
+	return m, focus
+}
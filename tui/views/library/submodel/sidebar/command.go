@@ -1,6 +1,9 @@
 package sidebar
 
 import (
+	"slices"
+
+	"viscue/tui/component/notification"
 	"viscue/tui/entity"
 	"viscue/tui/views/library/message"
 
@@ -12,17 +15,25 @@ type DataLoadedMsg struct {
 	Data []entity.Category
 }
 
+type TagsLoadedMsg struct {
+	Data []entity.Tag
+}
+
 func (m Model) LoadItems() tea.Msg {
 	rows, err := m.db.Queryx(`
 		WITH sorter AS (
-			SELECT 0 AS id, 'All' AS name, 1 AS sort_order
+			SELECT 0 AS id, 'All' AS name, NULL AS parent_id, 1 AS sort_order
 			UNION ALL
-			SELECT id, name, 2 AS sort_order FROM categories
+			SELECT -2 AS id, 'Favorites' AS name, NULL AS parent_id, 2 AS sort_order
 			UNION ALL
-			SELECT -1 AS id, 'Uncategorized' AS name, 3 AS sort_order
+			SELECT id, name, parent_id, 3 AS sort_order FROM categories
+			UNION ALL
+			SELECT -1 AS id, 'Uncategorized' AS name, NULL AS parent_id, 4 AS sort_order
+			UNION ALL
+			SELECT -3 AS id, 'Trash' AS name, NULL AS parent_id, 5 AS sort_order
 			ORDER BY sort_order, id
 		)
-		SELECT id, name FROM sorter
+		SELECT id, name, parent_id FROM sorter
 	`)
 	if err != nil {
 		return err
@@ -58,17 +69,18 @@ func (m Model) AddCategoryPromptMsg() tea.Cmd {
 }
 
 func (m Model) EditCategoryPromptMsg() tea.Cmd {
-	category, ok := m.list.SelectedItem().(entity.Category)
+	category, ok := m.selectedCategory()
 	if !ok {
 		return nil
-	} else if category.Id == 0 || category.Id == -1 {
-		return nil
+	} else if category.Id <= 0 {
+		// All, Favorites, Uncategorized and Trash are not stored
+		return builtInCategoryMsg(category, "renamed")
 	}
 
 	return tea.Sequence(
 		func() tea.Msg {
 			return message.OpenPromptMsg[entity.Category]{
-				Payload: m.list.SelectedItem().(entity.Category),
+				Payload: category,
 			}
 		},
 		func() tea.Msg {
@@ -78,21 +90,25 @@ func (m Model) EditCategoryPromptMsg() tea.Cmd {
 }
 
 func (m Model) CategorySelectedMsg() tea.Msg {
-	category, ok := m.list.SelectedItem().(entity.Category)
+	category, ok := m.selectedCategory()
 	if !ok {
-		return message.CategorySelectedMsg(nil)
+		return message.CategorySelectedMsg{}
 	}
 	log.Debug("sidebar.(Model).CategorySelectedMsg:", "category", category)
-	return message.CategorySelectedMsg(&category.Id)
+	msg := message.CategorySelectedMsg{Id: &category.Id}
+	if category.Id > 0 {
+		msg.Descendants = entity.Descendants(m.categories, category.Id)
+	}
+	return msg
 }
 
 func (m Model) DeleteCategoryPromptMsg() tea.Cmd {
-	category, ok := m.list.SelectedItem().(entity.Category)
+	category, ok := m.selectedCategory()
 	if !ok {
 		return nil
-	} else if category.Id == 0 || category.Id == -1 {
-		// TODO: Show notification, cannot delete...
-		return nil
+	} else if category.Id <= 0 {
+		// All, Favorites, Uncategorized and Trash are not stored
+		return builtInCategoryMsg(category, "deleted")
 	}
 	return tea.Sequence(
 		func() tea.Msg {
@@ -106,3 +122,36 @@ func (m Model) DeleteCategoryPromptMsg() tea.Cmd {
 		},
 	)
 }
+
+// LoadTags loads the tags used by at least one password.
+func (m Model) LoadTags() tea.Msg {
+	var tags []entity.Tag
+	err := m.db.Select(&tags,
+		`SELECT id, name FROM tags
+		WHERE id IN (SELECT tag_id FROM password_tags)
+		ORDER BY name COLLATE NOCASE`,
+	)
+	if err != nil {
+		log.Error("sidebar.(Model).LoadTags: failed loading tags", "err", err)
+		return nil
+	}
+	return TagsLoadedMsg{Data: tags}
+}
+
+func (m Model) TagsSelectedMsg() tea.Msg {
+	return message.TagsSelectedMsg{
+		Tags:     slices.Clone(m.selectedTags),
+		MatchAll: m.matchAllTags,
+	}
+}
+
+// builtInCategoryMsg warns that a category the sidebar
+// provides by itself cannot be changed the given way.
+func builtInCategoryMsg(category entity.Category, action string) tea.Cmd {
+	return func() tea.Msg {
+		return notification.ShowMsg{
+			Message: category.Name + " is built in and cannot be " + action,
+			Level:   notification.LevelWarning,
+		}
+	}
+}
@@ -1,11 +1,17 @@
 package sidebar
 
-import "github.com/charmbracelet/bubbles/key"
+import (
+	"viscue/tui/tool/keymap"
+
+	"github.com/charmbracelet/bubbles/key"
+)
 
 type KeyMap struct {
 	Up, Down, Switch, Help,
 	Add, Edit, Delete,
-	Search, ClearSearch key.Binding
+	Search, ClearSearch, Tags,
+	Fold, Collapse, Expand, Palette,
+	Undo, Redo key.Binding
 }
 
 func (k KeyMap) ShortHelp() []key.Binding {
@@ -14,25 +20,38 @@ func (k KeyMap) ShortHelp() []key.Binding {
 
 func (k KeyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
-		{k.Up, k.Down, k.Switch, k.Help}, // first column
-		{k.Add, k.Edit, k.Delete},        // second column
-		{k.Search, k.ClearSearch},        // third column
+		{k.Up, k.Down, k.Switch, k.Help},             // first column
+		{k.Add, k.Edit, k.Delete, k.Fold},            // second column
+		{k.Search, k.ClearSearch, k.Tags, k.Palette}, // third column
+		{k.Collapse, k.Expand, k.Undo, k.Redo},       // fourth column
 	}
 }
 
 var Keys = KeyMap{
 	Up: key.NewBinding(
-		key.WithKeys("up", "k"),
+		key.WithKeys("up", "k", "shift+tab"),
 		key.WithHelp("↑/k", "up"),
 	),
 	Down: key.NewBinding(
-		key.WithKeys("down", "j"),
+		key.WithKeys("down", "j", "tab"),
 		key.WithHelp("↓/j", "down"),
 	),
 	Switch: key.NewBinding(
 		key.WithKeys("ctrl+l"),
 		key.WithHelp("ctrl+l", "focus right"),
 	),
+	Fold: key.NewBinding(
+		key.WithKeys(" "),
+		key.WithHelp("space", "fold/unfold subcategories"),
+	),
+	Collapse: key.NewBinding(
+		key.WithKeys("left"),
+		key.WithHelp("←", "fold subcategories"),
+	),
+	Expand: key.NewBinding(
+		key.WithKeys("right"),
+		key.WithHelp("→", "unfold subcategories"),
+	),
 	Help: key.NewBinding(
 		key.WithKeys("?"),
 		key.WithHelp("?", "toggle help"),
@@ -57,4 +76,123 @@ var Keys = KeyMap{
 		key.WithKeys("c"),
 		key.WithHelp("c", "clear search"),
 	),
+	Tags: key.NewBinding(
+		key.WithKeys("t"),
+		key.WithHelp("t", "focus tags"),
+	),
+	Palette: key.NewBinding(
+		key.WithKeys("ctrl+k"),
+		key.WithHelp("ctrl+k", "commands"),
+	),
+	Undo: key.NewBinding(
+		key.WithKeys("u"),
+		key.WithHelp("u", "undo"),
+	),
+	Redo: key.NewBinding(
+		key.WithKeys("ctrl+r"),
+		key.WithHelp("ctrl+r", "redo"),
+	),
+}
+
+type TagKeyMap struct {
+	Up, Down, Toggle, Mode, Clear, Categories, Switch, Help key.Binding
+}
+
+func (k TagKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Up, k.Down, k.Toggle, k.Mode, k.Help}
+}
+
+func (k TagKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.Up, k.Down, k.Switch, k.Help}, // first column
+		{k.Toggle, k.Mode, k.Clear},      // second column
+		{k.Categories},                   // third column
+	}
+}
+
+var TagKeys = TagKeyMap{
+	Up:     Keys.Up,
+	Down:   Keys.Down,
+	Switch: Keys.Switch,
+	Help:   Keys.Help,
+	Toggle: key.NewBinding(
+		key.WithKeys(" ", "enter"),
+		key.WithHelp("space", "toggle tag"),
+	),
+	Mode: key.NewBinding(
+		key.WithKeys("m"),
+		key.WithHelp("m", "match any/all"),
+	),
+	Clear: key.NewBinding(
+		key.WithKeys("c"),
+		key.WithHelp("c", "clear tags"),
+	),
+	Categories: key.NewBinding(
+		key.WithKeys("t"),
+		key.WithHelp("t", "focus categories"),
+	),
+}
+
+// SearchKeyMap holds the keys ending a search, every other key
+// being typed into it.
+type SearchKeyMap struct {
+	Submit, Cancel key.Binding
+}
+
+var SearchKeys = SearchKeyMap{
+	Submit: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("enter", "keep filter"),
+	),
+	Cancel: key.NewBinding(
+		key.WithKeys("esc"),
+		key.WithHelp("esc", "clear filter"),
+	),
+}
+
+// ApplyKeymap rebinds the keys of the sidebar, of its tags and
+// of its search remapped in the config.
+func ApplyKeymap(config keymap.Config) error {
+	err := config.Apply("sidebar", keymap.Section{
+		"up":           &Keys.Up,
+		"down":         &Keys.Down,
+		"switch":       &Keys.Switch,
+		"help":         &Keys.Help,
+		"add":          &Keys.Add,
+		"edit":         &Keys.Edit,
+		"delete":       &Keys.Delete,
+		"search":       &Keys.Search,
+		"clear_search": &Keys.ClearSearch,
+		"tags":         &Keys.Tags,
+		"fold":         &Keys.Fold,
+		"collapse":     &Keys.Collapse,
+		"expand":       &Keys.Expand,
+		"palette":      &Keys.Palette,
+		"undo":         &Keys.Undo,
+		"redo":         &Keys.Redo,
+	})
+	if err != nil {
+		return err
+	}
+	// The tags share the moves of the categories
+	TagKeys.Up, TagKeys.Down = Keys.Up, Keys.Down
+	TagKeys.Switch, TagKeys.Help = Keys.Switch, Keys.Help
+	err = config.Apply("tags", keymap.Section{
+		"up":         &TagKeys.Up,
+		"down":       &TagKeys.Down,
+		"switch":     &TagKeys.Switch,
+		"help":       &TagKeys.Help,
+		"toggle":     &TagKeys.Toggle,
+		"mode":       &TagKeys.Mode,
+		"clear":      &TagKeys.Clear,
+		"categories": &TagKeys.Categories,
+	})
+	if err != nil {
+		return err
+	}
+	// The search section is shared with the shelf
+	return config.Apply("search", keymap.Section{
+		"submit": &SearchKeys.Submit,
+		"cancel": &SearchKeys.Cancel,
+	})
 }
@@ -4,10 +4,12 @@ import (
 	"viscue/tui/component/list"
 	"viscue/tui/entity"
 	"viscue/tui/style"
+	"viscue/tui/tool/zone"
 	"viscue/tui/views/library/message"
 	"viscue/tui/views/library/submodel/prompt"
 
 	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -21,9 +23,15 @@ type Model struct {
 	// Component
 	search textinput.Model
 	list   list.Model
+	tags   list.Model
 
 	// State
-	categories []entity.Category
+	categories   []entity.Category
+	collapsed    map[int64]bool // categories whose children are hidden
+	allTags      []entity.Tag
+	selectedTags []string
+	matchAllTags bool
+	zoneId       string // prefixes the zones of the pane for mouse events
 
 	// 	Style
 	paneBorder lipgloss.Style
@@ -36,9 +44,15 @@ func New(db *sqlx.DB) tea.Model {
 	search.Cursor.SetMode(cursor.CursorStatic)
 
 	m := Model{
-		db:         db,
-		search:     search,
-		list:       list.New(list.WithFocused(false)),
+		db:     db,
+		search: search,
+		list:   list.New(list.WithFocused(false)),
+		tags: list.New(
+			list.WithFocused(false),
+			list.WithEmptyText("No tags"),
+		),
+		collapsed:  make(map[int64]bool),
+		zoneId:     zone.NewPrefix(),
 		paneBorder: style.PaneBorderStyle,
 	}
 
@@ -47,23 +61,41 @@ func New(db *sqlx.DB) tea.Model {
 }
 
 func (m Model) Init() tea.Cmd {
-	return m.LoadItems
+	return tea.Batch(m.LoadItems, m.LoadTags)
 }
 
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case DataLoadedMsg:
+		selected, _ := m.selectedCategory()
 		m.categories = msg.Data
-		m.list.SetItems(
-			lo.Map(m.categories,
-				func(category entity.Category, _ int) list.Item {
-					return category
-				},
-			),
-		)
+		m.rebuild(selected.Id)
 		return m, nil
+	case TagsLoadedMsg:
+		m.allTags = msg.Data
+		selected := lo.Filter(m.selectedTags, func(name string, _ int) bool {
+			return lo.ContainsBy(m.allTags, func(tag entity.Tag) bool {
+				return tag.Name == name
+			})
+		})
+		changed := len(selected) != len(m.selectedTags)
+		m.selectedTags = selected
+		m.syncTags()
+		if changed {
+			return m, m.TagsSelectedMsg
+		}
+		return m, nil
+	case prompt.DataSubmittedMsg[entity.Password],
+		prompt.DeleteConfirmedMsg[entity.Password], prompt.BulkAppliedMsg:
+		return m, m.LoadTags
 	case message.SwitchFocusMsg:
 		if msg == message.SidebarFocused {
+			if m.tags.Focused() {
+				// Focused with a click on the tags
+				return m, func() tea.Msg {
+					return message.SetHelpKeysMsg{Keys: TagKeys}
+				}
+			}
 			m.list.Focus()
 			return m, func() tea.Msg {
 				return message.SetHelpKeysMsg{
@@ -71,23 +103,15 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 			}
 		} else {
+			m.search.Blur()
 			m.list.Blur()
+			m.tags.Blur()
 			return m, nil
 		}
 	case message.ShouldReloadMsg:
-		return m, m.LoadItems
+		return m, tea.Batch(m.LoadItems, m.LoadTags)
 	case prompt.DeleteConfirmedMsg[entity.Category]:
-		m.categories = lo.Filter(m.categories,
-			func(category entity.Category, index int) bool {
-				return category.Id != msg.Payload.Id
-			})
-		m.list.SetItems(
-			lo.Map(m.categories,
-				func(category entity.Category, _ int) list.Item {
-					return category
-				},
-			),
-		)
+		m.remove(msg.Payload)
 		return m, tea.Batch(
 			func() tea.Msg {
 				return message.ClosePromptMsg[entity.Category]{}
@@ -108,18 +132,22 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, cmd
 	case tea.WindowSizeMsg:
 		m.calculateDimension()
+	case tea.MouseMsg:
+		return m.updateMouse(msg)
 	case tea.KeyMsg:
-		if !m.list.Focused() {
+		if m.tags.Focused() {
+			return m.updateTags(msg)
+		} else if !m.list.Focused() {
 			// Since our parent model passes msg to both
 			// shelf and sidebar, especially for tea.KeyMsg
 			// we ignore msg if our model is not focused
 			return m, nil
 		} else if m.search.Focused() {
-			switch keypress := msg.String(); keypress {
-			case "enter":
+			switch {
+			case key.Matches(msg, SearchKeys.Submit):
 				m.search.Blur()
 				return m, m.CategorySelectedMsg
-			case "esc":
+			case key.Matches(msg, SearchKeys.Cancel):
 				m.search.SetValue("")
 				m.search.Blur()
 				m.filter()
@@ -130,35 +158,56 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.filter()
 			return m, tea.Batch(searchCmd, m.CategorySelectedMsg)
 		} else {
-			switch keypress := msg.String(); keypress {
-			case "k", "up", "shift+tab", "j", "down", "tab":
-				var listCmd tea.Cmd
-				m.list, listCmd = m.list.Update(msg)
+			switch {
+			case key.Matches(msg, Keys.Up), key.Matches(msg, Keys.Down):
+				if key.Matches(msg, Keys.Up) {
+					m.list.Up()
+				} else {
+					m.list.Down()
+				}
 				return m, tea.Sequence(
-					listCmd,
 					m.CategorySelectedMsg,
 					func() tea.Msg {
 						return message.ClearFilter{}
 					},
 				)
-			case "ctrl+l":
+			case key.Matches(msg, Keys.Switch):
 				m.search.Blur()
 				m.list.Blur()
 				return m, func() tea.Msg { return message.ShelfFocused }
-			case "a":
+			case key.Matches(msg, Keys.Add):
 				return m, m.AddCategoryPromptMsg()
-			case "e", "enter":
+			case key.Matches(msg, Keys.Edit):
 				return m, m.EditCategoryPromptMsg()
-			case "d":
+			case key.Matches(msg, Keys.Delete):
 				return m, m.DeleteCategoryPromptMsg()
-			case "f":
+			case key.Matches(msg, Keys.Search):
 				m.search.Focus()
 				return m, textinput.Blink
-			case "c":
+			case key.Matches(msg, Keys.ClearSearch):
 				m.search.Blur()
 				m.search.SetValue("")
 				m.filter()
 				return m, m.CategorySelectedMsg
+			case key.Matches(msg, Keys.Fold):
+				m.toggleFold()
+				return m, m.CategorySelectedMsg
+			case key.Matches(msg, Keys.Collapse):
+				m.fold(true)
+				return m, m.CategorySelectedMsg
+			case key.Matches(msg, Keys.Expand):
+				m.fold(false)
+				return m, m.CategorySelectedMsg
+			case key.Matches(msg, Keys.Undo):
+				return m, func() tea.Msg { return message.UndoMsg{} }
+			case key.Matches(msg, Keys.Redo):
+				return m, func() tea.Msg { return message.RedoMsg{} }
+			case key.Matches(msg, Keys.Tags):
+				m.list.Blur()
+				m.tags.Focus()
+				return m, func() tea.Msg {
+					return message.SetHelpKeysMsg{Keys: TagKeys}
+				}
 			}
 		}
 	}
@@ -169,21 +218,38 @@ func (m Model) View() string {
 	// Title
 	titleStyle := style.ModelTitleStyle
 	if m.list.Focused() {
-		titleStyle = style.ModelTitleFocusedStyle
+		titleStyle = style.Emphasize(style.ModelTitleFocusedStyle)
 	}
 
 	// Search Box
 	searchBoxStyle := style.SearchBoxStyle.
 		Width(m.list.Width()).
-		BorderForeground(style.ColorGray)
+		BorderForeground(style.ColorMuted)
 	if m.search.Focused() {
-		searchBoxStyle = searchBoxStyle.BorderForeground(style.ColorPurple)
+		searchBoxStyle = searchBoxStyle.BorderForeground(style.ColorAccent)
+	}
+	if style.IsCompact() {
+		searchBoxStyle = searchBoxStyle.BorderTop(false).BorderBottom(false)
+	}
+
+	tagsTitleStyle := style.ModelTitleStyle
+	if m.tags.Focused() {
+		tagsTitleStyle = style.Emphasize(style.ModelTitleFocusedStyle)
+	}
+	mode := "any"
+	if m.matchAllTags {
+		mode = "all"
 	}
+	modeLabel := lipgloss.NewStyle().Foreground(style.ColorMuted).
+		Render(" match " + mode)
 
-	return m.paneBorder.Render(lipgloss.JoinVertical(
+	return zone.Mark(m.zoneId+"pane", m.paneBorder.Render(lipgloss.JoinVertical(
 		lipgloss.Left,
 		titleStyle.Render("Category"),
-		searchBoxStyle.Render(m.search.View()),
+		zone.Mark(m.zoneId+"search", searchBoxStyle.Render(m.search.View())),
 		m.list.View(),
-	))
+		lipgloss.JoinHorizontal(lipgloss.Top,
+			tagsTitleStyle.Render("Tags"), zone.Mark(m.zoneId+"mode", modeLabel)),
+		m.tags.View(),
+	)))
 }
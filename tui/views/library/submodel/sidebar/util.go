@@ -2,45 +2,61 @@ package sidebar
 
 import (
 	"sort"
+	"strings"
 
 	"viscue/tui/component/list"
 	"viscue/tui/entity"
 	"viscue/tui/style"
 	"viscue/tui/tool/cache"
+	"viscue/tui/tool/zone"
+	"viscue/tui/views/library/message"
 
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/log"
 	"github.com/sahilm/fuzzy"
 	"github.com/samber/lo"
 )
 
+// categoryItem is a category of the list, labelled with
+// its indentation and fold marker in the tree, or with its
+// full path when searching.
+type categoryItem struct {
+	entity.Category
+	label string
+}
+
+// String implements list.Item
+func (item categoryItem) String() string {
+	return item.label
+}
+
 func (m *Model) filter() {
 	value := m.search.Value()
 	log.Debug("sidebar.(*Model).filter:", "m.search.Value()", value)
 	if value == "" {
-		m.list.SetItems(
-			lo.Map(m.categories,
-				func(item entity.Category, index int) list.Item {
-					return item
-				},
-			),
-		)
+		m.list.SetItems(m.tree())
 		return
 	}
 
-	ranks := fuzzy.Find(value,
-		lo.Map(m.categories,
-			func(category entity.Category, _ int) string {
+	paths := lo.Map(m.categories,
+		func(category entity.Category, _ int) string {
+			if category.Id <= 0 {
 				return category.Name
-			},
-		),
+			}
+			return entity.Path(m.categories, category.Id)
+		},
 	)
+	ranks := fuzzy.Find(value, paths)
 	sort.Stable(ranks)
 	indexes := lo.Map(ranks, func(match fuzzy.Match, _ int) int {
 		return match.Index
 	})
 	items := lo.FilterMap(m.categories,
 		func(category entity.Category, index int) (list.Item, bool) {
-			return category, lo.Contains(
+			return categoryItem{category, paths[index]}, lo.Contains(
 				indexes,
 				index,
 			)
@@ -50,17 +66,184 @@ func (m *Model) filter() {
 	m.list.SetItems(items)
 }
 
+// tree lists the categories depth first, children sorted by name
+// and hidden under a collapsed parent, between All and Favorites
+// on top, Uncategorized and Trash at the bottom.
+func (m Model) tree() []list.Item {
+	var items []list.Item
+	var walk func(categories []entity.Category, depth int)
+	walk = func(categories []entity.Category, depth int) {
+		sort.SliceStable(categories, func(i, j int) bool {
+			return strings.ToLower(categories[i].Name) <
+				strings.ToLower(categories[j].Name)
+		})
+		for _, category := range categories {
+			children := entity.Children(m.categories, category.Id)
+			marker := "  "
+			if len(children) > 0 && m.collapsed[category.Id] {
+				marker = "▸ "
+			} else if len(children) > 0 {
+				marker = "▾ "
+			}
+			items = append(items, categoryItem{
+				Category: category,
+				label:    strings.Repeat("  ", depth) + marker + category.Name,
+			})
+			if !m.collapsed[category.Id] {
+				walk(children, depth+1)
+			}
+		}
+	}
+
+	var top []entity.Category
+	for _, category := range m.categories {
+		switch {
+		case category.Id == 0 || category.Id == -2:
+			// All and Favorites come first
+			items = append(items, categoryItem{category, "  " + category.Name})
+		case category.Id > 0 && (!category.ParentId.Valid ||
+			!m.exists(category.ParentId.Int64)):
+			top = append(top, category)
+		}
+	}
+	walk(top, 0)
+	for _, id := range []int64{-1, -3} {
+		if category, ok := lo.Find(m.categories, func(category entity.Category) bool {
+			return category.Id == id
+		}); ok {
+			items = append(items, categoryItem{category, "  " + category.Name})
+		}
+	}
+	return items
+}
+
+func (m Model) exists(id int64) bool {
+	return lo.ContainsBy(m.categories, func(category entity.Category) bool {
+		return category.Id == id
+	})
+}
+
+func (m Model) selectedCategory() (entity.Category, bool) {
+	item, ok := m.list.SelectedItem().(categoryItem)
+	return item.Category, ok
+}
+
+// rebuild refreshes the list, keeping the cursor on the given category.
+func (m *Model) rebuild(id int64) {
+	m.filter()
+	_, index, found := lo.FindIndexOf(m.list.Items(), func(item list.Item) bool {
+		return item.(categoryItem).Id == id
+	})
+	if found {
+		m.list.SetIndex(index)
+	}
+}
+
+// fold collapses or expands the selected category. Collapsing a
+// category with nothing left to hide moves to its parent instead.
+func (m *Model) fold(collapse bool) {
+	category, ok := m.selectedCategory()
+	if !ok || category.Id <= 0 || m.search.Value() != "" {
+		return
+	}
+	hasChildren := len(entity.Children(m.categories, category.Id)) > 0
+	if collapse && (!hasChildren || m.collapsed[category.Id]) {
+		if category.ParentId.Valid {
+			m.rebuild(category.ParentId.Int64)
+		}
+		return
+	}
+	if hasChildren {
+		m.collapsed[category.Id] = collapse
+		m.rebuild(category.Id)
+	}
+}
+
+// toggleFold collapses the selected category, or expands it back.
+func (m *Model) toggleFold() {
+	category, ok := m.selectedCategory()
+	if ok && len(entity.Children(m.categories, category.Id)) > 0 {
+		m.fold(!m.collapsed[category.Id])
+	}
+}
+
 func (m *Model) calculateDimension() {
+	if style.IsCompact() {
+		m.calculateCompactDimension()
+		return
+	}
 	appHeight := style.CalculateAppHeight() - 2
 	appWidth := cache.Get[int](cache.TerminalWidth) - 6
 	sidebarWidth := appWidth * 20 / 100
 	paneWidth := sidebarWidth + 4
-	m.list.SetHeight(appHeight - 8)
+	tagsHeight := max(3, (appHeight-8)/3)
+	m.list.SetHeight(appHeight - 8 - tagsHeight - 2) // Leave room for the tags title
 	m.list.SetWidth(sidebarWidth)
+	m.tags.SetHeight(tagsHeight)
+	m.tags.SetWidth(sidebarWidth)
 	m.search.Width = sidebarWidth - 11
 	m.paneBorder = m.paneBorder.Height(appHeight).
 		MaxHeight(appHeight + 2).
-		Width(paneWidth)
+		Width(paneWidth).
+		PaddingTop(1).
+		PaddingBottom(1)
+}
+
+// calculateCompactDimension sizes the pane as the dropdown opened
+// from the collapsed sidebar, over the shelf below it.
+func (m *Model) calculateCompactDimension() {
+	appHeight := style.CalculateAppHeight() - 3 // Leave a line for the collapsed sidebar
+	appWidth := cache.Get[int](cache.TerminalWidth) - 6
+	sidebarWidth := min(appWidth, max(28, appWidth/2))
+	tagsHeight := max(2, (appHeight-6)/3)
+	m.list.SetHeight(appHeight - 6 - tagsHeight) // Leave room for the tags title
+	m.list.SetWidth(sidebarWidth)
+	m.tags.SetHeight(tagsHeight)
+	m.tags.SetWidth(sidebarWidth)
+	m.search.Width = sidebarWidth - 11
+	m.paneBorder = m.paneBorder.Height(appHeight).
+		MaxHeight(appHeight + 2).
+		Width(sidebarWidth + 4).
+		PaddingTop(0).
+		PaddingBottom(0)
+}
+
+// Focused reports whether the categories or the tags are focused,
+// which opens the dropdown when compact.
+func (m Model) Focused() bool {
+	return m.list.Focused() || m.tags.Focused() || m.search.Focused()
+}
+
+// Covers reports whether the mouse is over the sidebar,
+// either its pane or its collapsed line.
+func (m Model) Covers(msg tea.MouseMsg) bool {
+	_, pane := zone.Hit(m.zoneId+"pane", msg)
+	_, bar := zone.Hit(m.zoneId+"bar", msg)
+	return pane || bar
+}
+
+// CollapsedView renders the sidebar on a single line of the width,
+// naming the selected category and tags, for the compact layout.
+func (m Model) CollapsedView(width int) string {
+	titleStyle := style.ModelTitleStyle.MarginBottom(0)
+	arrow := "▾"
+	if m.Focused() {
+		titleStyle = style.Emphasize(style.ModelTitleFocusedStyle).MarginBottom(0)
+		arrow = "▴"
+	}
+	category, _ := m.selectedCategory()
+	title := titleStyle.Render(arrow + " " + category.Name)
+
+	var selection string
+	if len(m.selectedTags) > 0 {
+		selection = " #" + strings.Join(m.selectedTags, " #")
+	}
+	selection = lipgloss.NewStyle().Foreground(style.ColorMuted).
+		MaxWidth(max(0, width-lipgloss.Width(title))).
+		Render(selection)
+	return zone.Mark(m.zoneId+"bar", lipgloss.NewStyle().Width(width).
+		MaxWidth(width).
+		Render(title+selection))
 }
 
 func (m *Model) append(payload entity.Category) {
@@ -69,26 +252,176 @@ func (m *Model) append(payload entity.Category) {
 			return item.Id == payload.Id
 		})
 	if !found {
-		all := m.categories[0]
-		uncategorized := m.categories[len(m.categories)-1]
-		m.categories = m.categories[1 : len(m.categories)-1]
+		// The tree places it, whatever its position in the slice
 		m.categories = append(m.categories, payload)
-		sort.Slice(m.categories, func(i, j int) bool {
-			return m.categories[i].Name < m.categories[j].Name
-		})
-		m.categories = append([]entity.Category{all}, m.categories...)
-		m.categories = append(m.categories, uncategorized)
 	} else {
 		m.categories[index] = payload
 	}
-	m.list.SetItems(
-		lo.Map(m.categories,
-			func(category entity.Category, _ int) list.Item {
-				return category
-			},
-		),
+	// Unfold the parents so that the category stays in sight
+	for _, category := range m.categories {
+		if lo.Contains(entity.Descendants(m.categories, category.Id), payload.Id) {
+			delete(m.collapsed, category.Id)
+		}
+	}
+	m.rebuild(payload.Id)
+}
+
+// remove drops the category, its subcategories moving up to its parent.
+func (m *Model) remove(payload entity.Category) {
+	m.categories = lo.FilterMap(m.categories,
+		func(category entity.Category, _ int) (entity.Category, bool) {
+			if category.ParentId.Valid && category.ParentId.Int64 == payload.Id {
+				category.ParentId = payload.ParentId
+			}
+			return category, category.Id != payload.Id
+		})
+	delete(m.collapsed, payload.Id)
+	m.filter()
+}
+
+// tagItem is a tag of the list, ticked when selected.
+type tagItem struct {
+	name     string
+	selected bool
+}
+
+// String implements list.Item
+func (item tagItem) String() string {
+	if item.selected {
+		return "✓ " + item.name
+	}
+	return "  " + item.name
+}
+
+// syncTags rebuilds the tag items, keeping the cursor in place.
+func (m *Model) syncTags() {
+	index := m.tags.Index()
+	m.tags.SetItems(
+		lo.Map(m.allTags, func(tag entity.Tag, _ int) list.Item {
+			return tagItem{
+				name:     tag.Name,
+				selected: lo.Contains(m.selectedTags, tag.Name),
+			}
+		}),
 	)
-	if index != -1 {
-		m.list.SetIndex(index)
+	m.tags.SetIndex(max(0, min(index, len(m.allTags)-1)))
+}
+
+// toggleTag selects or deselects the tag under the cursor.
+func (m *Model) toggleTag() {
+	item, ok := m.tags.SelectedItem().(tagItem)
+	if !ok {
+		return
+	}
+	if item.selected {
+		m.selectedTags = lo.Without(m.selectedTags, item.name)
+	} else {
+		m.selectedTags = append(m.selectedTags, item.name)
+	}
+	m.syncTags()
+}
+
+func (m Model) updateTags(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, TagKeys.Up):
+		m.tags.Up()
+		return m, nil
+	case key.Matches(msg, TagKeys.Down):
+		m.tags.Down()
+		return m, nil
+	case key.Matches(msg, TagKeys.Toggle):
+		m.toggleTag()
+		return m, m.TagsSelectedMsg
+	case key.Matches(msg, TagKeys.Mode):
+		m.matchAllTags = !m.matchAllTags
+		return m, m.TagsSelectedMsg
+	case key.Matches(msg, TagKeys.Clear):
+		m.selectedTags = nil
+		m.syncTags()
+		return m, m.TagsSelectedMsg
+	case key.Matches(msg, TagKeys.Categories):
+		m.tags.Blur()
+		m.list.Focus()
+		return m, func() tea.Msg {
+			return message.SetHelpKeysMsg{Keys: Keys}
+		}
+	case key.Matches(msg, TagKeys.Switch):
+		m.tags.Blur()
+		return m, func() tea.Msg { return message.ShelfFocused }
+	}
+	return m, nil
+}
+
+// updateMouse focuses the sidebar when clicked, selecting the category
+// or toggling the tag under the mouse. Double clicking a category
+// edits it, and the wheel scrolls the list underneath.
+func (m Model) updateMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	if _, ok := zone.Hit(m.zoneId+"bar", msg); ok && zone.IsClick(msg) {
+		// The collapsed sidebar opens the dropdown, or closes it
+		if m.Focused() {
+			return m, func() tea.Msg { return message.ShelfFocused }
+		}
+		m.tags.Blur()
+		m.list.Focus()
+		return m, func() tea.Msg { return message.SidebarFocused }
+	}
+	if _, ok := zone.Hit(m.zoneId+"pane", msg); !ok {
+		return m, nil
+	}
+	if _, ok := zone.IsWheel(msg); ok {
+		index := m.list.Index()
+		m.list, _ = m.list.Update(msg)
+		m.tags, _ = m.tags.Update(msg)
+		if m.list.Index() == index {
+			return m, nil
+		}
+		return m, tea.Sequence(
+			m.CategorySelectedMsg,
+			func() tea.Msg {
+				return message.ClearFilter{}
+			},
+		)
+	}
+	if !zone.IsClick(msg) {
+		return m, nil
+	}
+
+	focus := func() tea.Msg { return message.SidebarFocused }
+	if _, ok := zone.Hit(m.zoneId+"mode", msg); ok {
+		m.matchAllTags = !m.matchAllTags
+		return m, m.TagsSelectedMsg
+	}
+	if _, ok := zone.Hit(m.zoneId+"search", msg); ok {
+		m.tags.Blur()
+		m.list.Focus()
+		m.search.Focus()
+		return m, tea.Batch(focus, textinput.Blink)
+	}
+
+	m.search.Blur()
+	if _, ok := m.tags.ItemAt(msg); ok {
+		m.tags.Click(msg)
+		m.list.Blur()
+		m.tags.Focus()
+		m.toggleTag()
+		return m, tea.Sequence(focus, m.TagsSelectedMsg)
+	}
+
+	m.tags.Blur()
+	m.list.Focus()
+	double, ok := m.list.Click(msg)
+	if !ok {
+		return m, focus
+	}
+	cmds := []tea.Cmd{
+		focus,
+		m.CategorySelectedMsg,
+		func() tea.Msg {
+			return message.ClearFilter{}
+		},
+	}
+	if category, _ := m.selectedCategory(); double && category.Id > 0 {
+		cmds = append(cmds, m.EditCategoryPromptMsg())
 	}
+	return m, tea.Sequence(cmds...)
 }
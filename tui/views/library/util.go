@@ -0,0 +1,138 @@
+package library
+
+import (
+	"strconv"
+
+	"viscue/tui/entity"
+	"viscue/tui/style"
+	"viscue/tui/tool/cache"
+	"viscue/tui/views/library/message"
+	"viscue/tui/views/library/submodel/palette"
+	"viscue/tui/views/library/submodel/preview"
+	"viscue/tui/views/library/submodel/shelf"
+	"viscue/tui/views/library/submodel/sidebar"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// paletteEntries lists every action the library offers, acting
+// on the current selection, followed by every item.
+func (m Model) paletteEntries() []palette.Entry {
+	s, _ := m.shelf.(shelf.Model)
+	b, _ := m.sidebar.(sidebar.Model)
+	hint := func(binding key.Binding) string {
+		return binding.Help().Key
+	}
+
+	entries := []palette.Entry{
+		{Title: "Add item", Hint: hint(shelf.Keys.Add),
+			Cmd: s.AddPasswordPromptMsg()},
+		{Title: "Edit item", Hint: hint(shelf.Keys.Edit),
+			Cmd: s.EditPasswordPromptMsg()},
+		{Title: "Delete item", Hint: hint(shelf.Keys.Delete),
+			Cmd: s.DeletePasswordPromptMsg()},
+		{Title: "Restore item from trash", Hint: hint(shelf.Keys.Restore),
+			Cmd: s.RestoreFromTrash},
+		{Title: "Toggle favorite", Hint: hint(shelf.Keys.Favorite),
+			Cmd: s.ToggleFavorite},
+		{Title: "Move items to category", Hint: hint(shelf.Keys.Move),
+			Cmd: s.BulkPromptMsg(message.BulkMove)},
+		{Title: "Add tags to items", Hint: hint(shelf.Keys.Tag),
+			Cmd: s.BulkPromptMsg(message.BulkTag)},
+		{Title: "Regenerate passwords", Hint: hint(shelf.Keys.Regenerate),
+			Cmd: s.BulkPromptMsg(message.BulkRegenerate)},
+		{Title: "Copy password", Hint: hint(shelf.Keys.Copy),
+			Cmd: tea.Batch(s.CopyToClipboard, s.MarkAsUsed)},
+		{Title: "Copy one-time password", Hint: hint(shelf.Keys.CopyOtp),
+			Cmd: tea.Batch(s.CopyOtpToClipboard, s.MarkAsUsed)},
+		{Title: "Copy email", Hint: hint(shelf.Keys.CopyEmail),
+			Cmd: s.CopyValue(entity.KeyEmail)},
+		{Title: "Copy username", Hint: hint(shelf.Keys.CopyUsername),
+			Cmd: s.CopyValue(entity.KeyUsername)},
+	}
+	if password, ok := s.Selected(); ok {
+		for i, entry := range preview.Entries(password) {
+			if i >= 9 {
+				break
+			}
+			entries = append(entries, palette.Entry{
+				Title: "Copy " + entry.Label,
+				Hint:  strconv.Itoa(i + 1),
+				Cmd:   s.CopyEntry(i + 1),
+			})
+		}
+	}
+	entries = append(entries,
+		palette.Entry{Title: "Generate password", Cmd: m.Generate},
+		palette.Entry{Title: "Password history", Hint: hint(shelf.Keys.History),
+			Cmd: s.HistoryMsg()},
+		palette.Entry{Title: "View details", Hint: hint(shelf.Keys.Detail),
+			Cmd: s.DetailMsg()},
+		palette.Entry{Title: "Breach report", Hint: hint(shelf.Keys.Breach),
+			Cmd: s.BreachReportMsg()},
+		palette.Entry{Title: "Notifications",
+			Hint: hint(shelf.Keys.Notifications), Cmd: s.NotificationsMsg()},
+		palette.Entry{Title: "Add category", Hint: hint(sidebar.Keys.Add),
+			Cmd: b.AddCategoryPromptMsg()},
+		palette.Entry{Title: "Edit category", Hint: hint(sidebar.Keys.Edit),
+			Cmd: b.EditCategoryPromptMsg()},
+		palette.Entry{Title: "Delete category", Hint: hint(sidebar.Keys.Delete),
+			Cmd: b.DeleteCategoryPromptMsg()},
+		palette.Entry{Title: "Settings",
+			Hint: hint(shelf.Keys.Settings), Cmd: s.SettingsMsg()},
+		palette.Entry{Title: "Account",
+			Hint: hint(shelf.Keys.Account), Cmd: s.AccountMsg()},
+		palette.Entry{Title: "Export items", Cmd: s.ExportPromptMsg()},
+		palette.Entry{Title: "Export marked items", Hint: hint(shelf.Keys.Export),
+			Cmd: s.BulkPromptMsg(message.BulkExport)},
+		palette.Entry{Title: "Undo", Hint: hint(shelf.Keys.Undo), Cmd: m.Undo},
+		palette.Entry{Title: "Redo", Hint: hint(shelf.Keys.Redo), Cmd: m.Redo},
+		palette.Entry{Title: "Lock", Cmd: m.Lock},
+	)
+
+	for _, password := range s.Passwords() {
+		hint := password.Type.String()
+		if password.CategoryId.Valid {
+			hint = s.Category(password.CategoryId.Int64)
+		}
+		entries = append(entries, palette.Entry{
+			Title: password.Name,
+			Hint:  hint,
+			Cmd: tea.Sequence(
+				func() tea.Msg {
+					return message.OpenDetailMsg{Payload: password}
+				},
+				func() tea.Msg {
+					return message.PanelFocused
+				},
+			),
+		})
+	}
+	return entries
+}
+
+// compactView renders the collapsed sidebar above the shelf, the
+// sidebar dropping down over the shelf while focused.
+func (m Model) compactView() string {
+	b, _ := m.sidebar.(sidebar.Model)
+	shelfView := m.shelf.View()
+	if b.Focused() {
+		shelfView = style.Overlay(shelfView, b.View(), lipgloss.Left, lipgloss.Top)
+	}
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		b.CollapsedView(cache.Get[int](cache.TerminalWidth)),
+		shelfView,
+	)
+}
+
+// overlayPalette draws the palette, when open, over the
+// upper part of the view.
+func (m Model) overlayPalette(view string) string {
+	if m.palette == nil {
+		return view
+	}
+	return style.Overlay(view, m.palette.View(), lipgloss.Center, 0.2)
+}
@@ -3,16 +3,17 @@ package login
 import (
 	"encoding/hex"
 	"errors"
-	"fmt"
 	"strings"
+	"time"
 
 	"viscue/tui/tool/cache"
 	"viscue/tui/tool/crypto"
+	"viscue/tui/tool/keychain"
+	"viscue/tui/tool/vault"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/log"
 	validation "github.com/go-ozzo/ozzo-validation/v4"
-	"github.com/zalando/go-keyring"
 )
 
 type loginRequest struct {
@@ -35,7 +36,7 @@ func (m *login) submit() tea.Msg {
 
 	if err := req.Validate(); err != nil {
 		msg := strings.Split(err.Error(), "; ")
-		return fmt.Errorf(strings.Join(msg, " and "))
+		return errors.New(strings.Join(msg, " and "))
 	}
 
 	if m.shouldCreateAccount {
@@ -72,8 +73,9 @@ func (m *login) signup() tea.Msg {
 	}
 
 	_, err = tx.Exec(
-		"INSERT INTO configurations VALUES (?, ?), (?, ?)",
+		"INSERT INTO configurations VALUES (?, ?), (?, ?), (?, ?)",
 		"username", username, "password", hashedPassword,
+		"created_at", time.Now().UTC().Format(time.RFC3339),
 	)
 	if err != nil {
 		log.Error("failed to insert to configurations", "err", err)
@@ -88,7 +90,7 @@ func (m *login) signup() tea.Msg {
 		return errors.New("failed generating secret key")
 	}
 
-	err = keyring.Set(crypto.SecretKeyStorageName, username, sc)
+	err = keychain.Set(crypto.SecretKeyStorageName, username, sc)
 	if err != nil {
 		log.Error("failed saving secret key in keyring", "err", err)
 		_ = tx.Rollback()
@@ -134,8 +136,8 @@ func (m *login) signup() tea.Msg {
 	if err != nil {
 		log.Error("failed to commit transaction", "err", err)
 		_ = tx.Rollback()
-		_ = keyring.Delete(crypto.SecretKeyStorageName, username)
-		_ = keyring.Delete(crypto.SaltStorageName, username)
+		_ = keychain.Delete(crypto.SecretKeyStorageName, username)
+		_ = keychain.Delete(crypto.SaltStorageName, username)
 		return errors.New("something went wrong while saving to database")
 	}
 
@@ -151,62 +153,9 @@ func (m *login) signup() tea.Msg {
 // 5. Compute public key from private key
 // 6. Return SetStoreMessages with payloads.
 func (m *login) login() tea.Msg {
-	username := m.usernameInput.Value()
-	password := m.passwordInput.Value()
-
-	var hashedPassword string
-	err := m.db.QueryRowx(
-		"SELECT value FROM configurations WHERE key = ?", "password").
-		Scan(&hashedPassword)
-	if err != nil {
-		log.Error("failed querying password from database", "err", err)
-		return errors.New("failed querying password from database")
-	}
-
-	match, err := crypto.MatchPassword(password, hashedPassword)
+	err := vault.Unlock(m.db, m.usernameInput.Value(), m.passwordInput.Value())
 	if err != nil {
 		return err
-	} else if !match {
-		return errors.New("authentication failed password mismatched")
 	}
-
-	sc, err := keyring.Get(crypto.SecretKeyStorageName, username)
-	if err != nil {
-		log.Error("failed to find secret key in keyring", "err", err)
-		return errors.New("secret key was not found")
-	}
-
-	auc, err := crypto.GenerateAccountUnlockKey(password, sc, username)
-	if err != nil {
-		log.Error("failed to generate account unlock key", "err", err)
-		return errors.New("failed generating account unlock key")
-	}
-
-	var encodedEncryptedPrivateKey string
-	err = m.db.QueryRowx("SELECT value FROM configurations WHERE key = ?",
-		"encrypted_private_key").Scan(&encodedEncryptedPrivateKey)
-	if err != nil {
-		log.Error("failed querying encrypted private key from database", "err",
-			err)
-		return errors.New("failed querying encrypted private key from database")
-	}
-
-	encryptedPrivateKey, err := hex.DecodeString(encodedEncryptedPrivateKey)
-	if err != nil {
-		log.Error("failed decoding encrypted private key", "err", err)
-		return errors.New("failed decoding encrypted private key")
-	}
-
-	privateKey, err := crypto.DecryptRsaKey(encryptedPrivateKey, auc)
-	if err != nil {
-		log.Error("failed decrypting private key", "err", err)
-		return errors.New("failed decrypting private key")
-	}
-
-	// Store necessary values in cache
-	cache.Set(cache.AccountUnlockKey, auc)
-	cache.Set(cache.PrivateKey, privateKey)
-	cache.Set(cache.PublicKey, &privateKey.PublicKey)
-
 	return Successful{}
 }
@@ -5,6 +5,7 @@ import (
 	"errors"
 
 	"viscue/tui/style"
+	"viscue/tui/tool/keymap"
 
 	"github.com/charmbracelet/bubbles/cursor"
 	"github.com/charmbracelet/bubbles/help"
@@ -47,6 +48,15 @@ var keys = keyMap{
 	),
 }
 
+// ApplyKeymap rebinds the keys of the login remapped in the config.
+func ApplyKeymap(config keymap.Config) error {
+	return config.Apply("login", keymap.Section{
+		"tab":    &keys.Tab,
+		"quit":   &keys.Quit,
+		"submit": &keys.Submit,
+	})
+}
+
 type login struct {
 	db *sqlx.DB
 